@@ -0,0 +1,40 @@
+package commandservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"log"
+)
+
+// ChallengeCommandService maneja los cambios del catálogo de challenges
+type ChallengeCommandService struct {
+	repository repositories.ChallengeRepository
+}
+
+// NewChallengeCommandService crea una nueva instancia del servicio
+func NewChallengeCommandService(repository repositories.ChallengeRepository) *ChallengeCommandService {
+	return &ChallengeCommandService{
+		repository: repository,
+	}
+}
+
+// HandleChallengeEvent aplica un evento de creación, actualización o archivado al catálogo.
+// Los eventos más antiguos que el último aplicado se descartan (idempotencia y orden).
+func (s *ChallengeCommandService) HandleChallengeEvent(ctx context.Context, challenge *aggregates.Challenge) error {
+	applied, err := s.repository.Save(ctx, challenge)
+	if err != nil {
+		log.Printf("Error saving challenge: %v", err)
+		return err
+	}
+
+	if !applied {
+		log.Printf("Challenge %s event for %s is not newer than the stored version, skipping",
+			challenge.Change(), challenge.ChallengeID().Value())
+		return nil
+	}
+
+	log.Printf("Successfully applied challenge %s event for challenge ID: %s, title: %s",
+		challenge.Change(), challenge.ChallengeID().Value(), challenge.Title())
+	return nil
+}
@@ -0,0 +1,76 @@
+package commandservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"fmt"
+)
+
+// ChallengeSyncService sincroniza los eventos del catálogo de challenges de su tópico. Implementa TopicSyncer.
+type ChallengeSyncService struct {
+	topic          string
+	repository     repositories.ChallengeRepository
+	payloadDecoder events.PayloadDecoder
+}
+
+// NewChallengeSyncService crea una nueva instancia del servicio de sincronización
+func NewChallengeSyncService(topic string, repository repositories.ChallengeRepository, payloadDecoder events.PayloadDecoder) *ChallengeSyncService {
+	return &ChallengeSyncService{
+		topic:          topic,
+		repository:     repository,
+		payloadDecoder: payloadDecoder,
+	}
+}
+
+// Target retorna el target de sincronización del servicio
+func (s *ChallengeSyncService) Target() valueobjects.SyncTarget {
+	return valueobjects.SyncTargetChallenge
+}
+
+// Topic retorna el tópico que sincroniza el servicio
+func (s *ChallengeSyncService) Topic() string {
+	return s.topic
+}
+
+// SyncMessage aplica al catálogo el cambio de un mensaje si es más reciente que el guardado.
+// En dry-run solo verifica si el challenge está en el catálogo.
+func (s *ChallengeSyncService) SyncMessage(ctx context.Context, msg *events.Message, dryRun bool) (SyncOutcome, string, error) {
+	payload, err := s.payloadDecoder.Decode(ctx, msg.Value)
+	if err != nil {
+		return undecodableMessage(fmt.Errorf("error decoding message payload: %w", err))
+	}
+
+	// Los eventos legados sin tipo se interpretan como actualizaciones, como en el consumidor
+	envelope, err := events.ParseMessage(payload, msg.Headers, events.ChallengeUpdatedEventType)
+	if err != nil {
+		return invalidMessage(fmt.Errorf("error parsing event envelope: %w", err))
+	}
+
+	challenge, err := events.DecodeChallenge(envelope, msg.Timestamp)
+	if err != nil {
+		return invalidMessage(fmt.Errorf("error decoding event: %w", err))
+	}
+	key := challenge.ChallengeID().Value()
+
+	if dryRun {
+		existing, err := s.repository.FindByChallengeID(ctx, challenge.ChallengeID())
+		if err != nil {
+			return SyncOutcomeFailed, key, fmt.Errorf("error checking existing challenge %s: %w", key, err)
+		}
+		if existing == nil {
+			return SyncOutcomeMissing, key, nil
+		}
+		return SyncOutcomeSkipped, key, nil
+	}
+
+	applied, err := s.repository.Save(ctx, challenge)
+	if err != nil {
+		return SyncOutcomeFailed, key, fmt.Errorf("error saving challenge %s: %w", key, err)
+	}
+	if !applied {
+		return SyncOutcomeSkipped, key, nil
+	}
+	return SyncOutcomeSynced, key, nil
+}
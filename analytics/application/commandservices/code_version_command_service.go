@@ -0,0 +1,38 @@
+package commandservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"log"
+)
+
+// CodeVersionCommandService maneja los eventos de versiones de código
+type CodeVersionCommandService struct {
+	repository repositories.CodeVersionRepository
+}
+
+// NewCodeVersionCommandService crea una nueva instancia del servicio
+func NewCodeVersionCommandService(repository repositories.CodeVersionRepository) *CodeVersionCommandService {
+	return &CodeVersionCommandService{
+		repository: repository,
+	}
+}
+
+// HandleCodeVersionEvent guarda una nueva versión de código. Las versiones repetidas se ignoran (idempotencia).
+func (s *CodeVersionCommandService) HandleCodeVersionEvent(ctx context.Context, version *aggregates.CodeVersion) error {
+	inserted, err := s.repository.Save(ctx, version)
+	if err != nil {
+		log.Printf("Error saving code version: %v", err)
+		return err
+	}
+
+	if !inserted {
+		log.Printf("Code version already exists: %s, skipping", version.CodeVersionID().Value())
+		return nil
+	}
+
+	log.Printf("Successfully saved code version %d (%s) for student ID: %s, challenge ID: %s",
+		version.VersionNumber(), version.CodeVersionID().Value(), version.StudentID().Value(), version.ChallengeID().Value())
+	return nil
+}
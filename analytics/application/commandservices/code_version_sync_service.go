@@ -0,0 +1,74 @@
+package commandservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"fmt"
+)
+
+// CodeVersionSyncService sincroniza los eventos de versiones de código de su tópico. Implementa TopicSyncer.
+type CodeVersionSyncService struct {
+	topic          string
+	repository     repositories.CodeVersionRepository
+	payloadDecoder events.PayloadDecoder
+}
+
+// NewCodeVersionSyncService crea una nueva instancia del servicio de sincronización
+func NewCodeVersionSyncService(topic string, repository repositories.CodeVersionRepository, payloadDecoder events.PayloadDecoder) *CodeVersionSyncService {
+	return &CodeVersionSyncService{
+		topic:          topic,
+		repository:     repository,
+		payloadDecoder: payloadDecoder,
+	}
+}
+
+// Target retorna el target de sincronización del servicio
+func (s *CodeVersionSyncService) Target() valueobjects.SyncTarget {
+	return valueobjects.SyncTargetCodeVersion
+}
+
+// Topic retorna el tópico que sincroniza el servicio
+func (s *CodeVersionSyncService) Topic() string {
+	return s.topic
+}
+
+// SyncMessage guarda la versión de código de un mensaje si no existe. En dry-run solo verifica si existe.
+func (s *CodeVersionSyncService) SyncMessage(ctx context.Context, msg *events.Message, dryRun bool) (SyncOutcome, string, error) {
+	payload, err := s.payloadDecoder.Decode(ctx, msg.Value)
+	if err != nil {
+		return undecodableMessage(fmt.Errorf("error decoding message payload: %w", err))
+	}
+
+	envelope, err := events.ParseMessage(payload, msg.Headers, events.CodeVersionCreatedEventType)
+	if err != nil {
+		return invalidMessage(fmt.Errorf("error parsing event envelope: %w", err))
+	}
+
+	version, err := events.DecodeCodeVersion(envelope, msg.Timestamp)
+	if err != nil {
+		return invalidMessage(fmt.Errorf("error decoding event: %w", err))
+	}
+	key := version.CodeVersionID().Value()
+
+	if dryRun {
+		exists, err := s.repository.Exists(ctx, version.CodeVersionID())
+		if err != nil {
+			return SyncOutcomeFailed, key, fmt.Errorf("error checking existing code version %s: %w", key, err)
+		}
+		if !exists {
+			return SyncOutcomeMissing, key, nil
+		}
+		return SyncOutcomeSkipped, key, nil
+	}
+
+	inserted, err := s.repository.Save(ctx, version)
+	if err != nil {
+		return SyncOutcomeFailed, key, fmt.Errorf("error saving code version %s: %w", key, err)
+	}
+	if !inserted {
+		return SyncOutcomeSkipped, key, nil
+	}
+	return SyncOutcomeSynced, key, nil
+}
@@ -0,0 +1,128 @@
+package commandservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrUnknownConsumerTopic se retorna si el consumidor no está suscrito al tópico indicado
+var ErrUnknownConsumerTopic = errors.New("consumer is not subscribed to topic")
+
+// ErrUnknownPartition se retorna si la partición indicada no existe en el tópico
+var ErrUnknownPartition = errors.New("partition does not exist")
+
+// ErrInvalidOffsetReset se retorna si la petición de reseteo de offsets no es válida
+var ErrInvalidOffsetReset = errors.New("invalid offset reset")
+
+// OffsetResetStrategy indica a qué posición se mueven los offsets de un consumer group
+type OffsetResetStrategy string
+
+const (
+	OffsetResetEarliest  OffsetResetStrategy = "earliest"  // Mensaje más antiguo disponible
+	OffsetResetLatest    OffsetResetStrategy = "latest"    // Siguiente mensaje a publicar
+	OffsetResetTimestamp OffsetResetStrategy = "timestamp" // Primer mensaje publicado desde una fecha
+	OffsetResetOffset    OffsetResetStrategy = "offset"    // Offset explícito
+)
+
+// OffsetResetRequest es una petición para mover los offsets del consumer group en un tópico
+type OffsetResetRequest struct {
+	Topic      string
+	Partitions []int32 // Vacío = todas las particiones del tópico
+	Strategy   OffsetResetStrategy
+	Timestamp  *time.Time // Requerido con OffsetResetTimestamp
+	Offset     *int64     // Requerido con OffsetResetOffset
+}
+
+// Validate verifica que la petición tenga los datos que requiere su estrategia
+func (r OffsetResetRequest) Validate() error {
+	if r.Topic == "" {
+		return fmt.Errorf("%w: topic cannot be empty", ErrInvalidOffsetReset)
+	}
+
+	switch r.Strategy {
+	case OffsetResetEarliest, OffsetResetLatest:
+	case OffsetResetTimestamp:
+		if r.Timestamp == nil {
+			return fmt.Errorf("%w: timestamp is required for the timestamp strategy", ErrInvalidOffsetReset)
+		}
+	case OffsetResetOffset:
+		if r.Offset == nil || *r.Offset < 0 {
+			return fmt.Errorf("%w: a non-negative offset is required for the offset strategy", ErrInvalidOffsetReset)
+		}
+		if len(r.Partitions) == 0 {
+			return fmt.Errorf("%w: partitions are required for the offset strategy", ErrInvalidOffsetReset)
+		}
+	default:
+		return fmt.Errorf("%w: strategy must be one of earliest, latest, timestamp, offset", ErrInvalidOffsetReset)
+	}
+	return nil
+}
+
+// PartitionOffsetReset es el offset al que se mueve una partición
+type PartitionOffsetReset struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+// ConsumerController controla el consumo de los tópicos del consumer group de esta instancia
+type ConsumerController interface {
+	// PausePartitions detiene la lectura de las particiones (todas si partitions está vacío) y retorna las pausadas
+	PausePartitions(topic string, partitions []int32) ([]int32, error)
+	// ResumePartitions retoma la lectura de las particiones (todas si partitions está vacío) y retorna las retomadas
+	ResumePartitions(topic string, partitions []int32) ([]int32, error)
+	// ResetOffsets calcula los offsets de destino y los aplica al reiniciar la sesión del consumer group
+	ResetOffsets(ctx context.Context, request OffsetResetRequest) ([]PartitionOffsetReset, error)
+}
+
+// ConsumerAdminService maneja las operaciones de administración de los consumidores de Kafka
+type ConsumerAdminService struct {
+	controller ConsumerController
+}
+
+// NewConsumerAdminService crea una nueva instancia del servicio
+func NewConsumerAdminService(controller ConsumerController) *ConsumerAdminService {
+	return &ConsumerAdminService{
+		controller: controller,
+	}
+}
+
+// Pause detiene la lectura de un tópico o de algunas de sus particiones
+func (s *ConsumerAdminService) Pause(topic string, partitions []int32) ([]int32, error) {
+	paused, err := s.controller.PausePartitions(topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Paused consumption of topic %s partitions %v", topic, paused)
+	return paused, nil
+}
+
+// Resume retoma la lectura de un tópico o de algunas de sus particiones
+func (s *ConsumerAdminService) Resume(topic string, partitions []int32) ([]int32, error) {
+	resumed, err := s.controller.ResumePartitions(topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Resumed consumption of topic %s partitions %v", topic, resumed)
+	return resumed, nil
+}
+
+// ResetOffsets mueve los offsets del consumer group en un tópico
+func (s *ConsumerAdminService) ResetOffsets(ctx context.Context, request OffsetResetRequest) ([]PartitionOffsetReset, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	offsets, err := s.controller.ResetOffsets(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Scheduled %s offset reset of topic %s: %v", request.Strategy, request.Topic, offsets)
+	return offsets, nil
+}
@@ -0,0 +1,194 @@
+package commandservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// IngestStatus es el resultado de ingerir un evento recibido por HTTP
+type IngestStatus string
+
+const (
+	IngestStatusAccepted IngestStatus = "accepted" // Guardado o ya existente
+	IngestStatusRejected IngestStatus = "rejected" // Evento inválido, no se guardó
+)
+
+// IngestResult es el resultado de un evento de un lote ingerido por HTTP
+type IngestResult struct {
+	Index     int          `json:"index"`        // Posición del evento en el lote
+	ID        string       `json:"id,omitempty"` // ID de la ejecución o del usuario
+	Status    IngestStatus `json:"status"`
+	Duplicate bool         `json:"duplicate"`         // El evento ya estaba guardado (o una versión más reciente)
+	Updated   bool         `json:"updated,omitempty"` // El evento reemplazó una versión anterior de la ejecución
+	Error     string       `json:"error,omitempty"`
+}
+
+// EventIngestService guarda eventos recibidos directamente por HTTP, para los productores que no pueden
+// publicar en Kafka. Los eventos pasan por la misma conversión a dominio y el mismo guardado idempotente
+// que los consumidores, por lo que reenviar un lote es seguro.
+type EventIngestService struct {
+	executionRepository repositories.ExecutionAnalyticsRepository
+	userRegRepository   repositories.UserRegistrationAnalyticsRepository
+	timestamps          events.TimestampPolicy
+	languages           *valueobjects.LanguageRegistry
+}
+
+// NewEventIngestService crea una nueva instancia del servicio
+func NewEventIngestService(
+	executionRepository repositories.ExecutionAnalyticsRepository,
+	userRegRepository repositories.UserRegistrationAnalyticsRepository,
+	timestamps events.TimestampPolicy,
+	languages *valueobjects.LanguageRegistry,
+) *EventIngestService {
+	return &EventIngestService{
+		executionRepository: executionRepository,
+		userRegRepository:   userRegRepository,
+		timestamps:          timestamps,
+		languages:           languages,
+	}
+}
+
+// IngestExecutions guarda los eventos de ejecución del lote. Cada evento puede venir con el formato de
+// ExecutionAnalyticsEvent, en un envelope versionado o como CloudEvent. Los eventos válidos se guardan en
+// un solo lote; los inválidos, y los que la base de datos rechaza por sus datos, se rechazan sin afectar
+// al resto. Un error transitorio de la base de datos interrumpe el lote y se retorna.
+func (s *EventIngestService) IngestExecutions(ctx context.Context, items []json.RawMessage) ([]IngestResult, error) {
+	results := make([]IngestResult, len(items))
+	executions := make([]*aggregates.ExecutionAnalytics, 0, len(items))
+	indexes := make([]int, 0, len(items))
+	for index, item := range items {
+		result := IngestResult{Index: index}
+
+		envelope, err := events.ParseEnvelope(item, events.ExecutionAnalyticsEventType)
+		if err != nil {
+			results[index] = rejected(result, err)
+			continue
+		}
+		execution, err := events.DecodeExecutionAnalytics(envelope, s.languages)
+		if err != nil {
+			results[index] = rejected(result, err)
+			continue
+		}
+		result.ID = execution.ExecutionID().Value()
+		results[index] = result
+
+		executions = append(executions, execution)
+		indexes = append(indexes, index)
+	}
+
+	if err := s.saveExecutions(ctx, executions, indexes, results); err != nil {
+		return nil, err
+	}
+
+	logIngest("execution", results)
+	return results, nil
+}
+
+// saveExecutions inserta las ejecuciones, o reemplaza las guardadas si el evento es una versión más reciente,
+// y completa sus resultados (indexes indica la posición de cada una en results). Si la base de datos
+// rechaza el lote por los datos de alguna ejecución, las guarda una a una para rechazar solo esas.
+func (s *EventIngestService) saveExecutions(ctx context.Context, executions []*aggregates.ExecutionAnalytics, indexes []int, results []IngestResult) error {
+	if len(executions) == 0 {
+		return nil
+	}
+
+	saved, err := s.executionRepository.SaveBatch(ctx, executions)
+	if err == nil {
+		for i, index := range indexes {
+			results[index].Status = IngestStatusAccepted
+			results[index].Updated = saved.Outcomes[i] == repositories.SaveOutcomeUpdated
+			results[index].Duplicate = saved.Outcomes[i] == repositories.SaveOutcomeStale
+		}
+		return nil
+	}
+
+	if !repositories.IsDataError(err) {
+		return fmt.Errorf("error saving %d execution(s): %w", len(executions), err)
+	}
+	if len(executions) == 1 {
+		results[indexes[0]] = rejected(results[indexes[0]], err)
+		return nil
+	}
+
+	log.Printf("Batch of %d executions rejected by the database, saving individually: %v", len(executions), err)
+	for i := range executions {
+		if err := s.saveExecutions(ctx, executions[i:i+1], indexes[i:i+1], results); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IngestRegistrations guarda los eventos de registro de usuarios del lote, con el formato de
+// UserRegisteredEvent, en un envelope versionado o como CloudEvent. Los eventos sin fecha de registro
+// toman la hora de recepción, como los mensajes de Kafka toman la de publicación. Los registros que la base
+// de datos rechaza por sus datos se rechazan sin afectar al resto; un error transitorio interrumpe el lote.
+func (s *EventIngestService) IngestRegistrations(ctx context.Context, items []json.RawMessage) ([]IngestResult, error) {
+	receivedAt := time.Now()
+	results := make([]IngestResult, 0, len(items))
+	for index, item := range items {
+		result := IngestResult{Index: index}
+
+		envelope, err := events.ParseEnvelope(item, events.UserRegisteredEventType)
+		if err != nil {
+			results = append(results, rejected(result, err))
+			continue
+		}
+		userReg, err := events.DecodeUserRegistration(envelope, receivedAt, s.timestamps)
+		if err != nil {
+			results = append(results, rejected(result, err))
+			continue
+		}
+		result.ID = userReg.UserID().Value()
+
+		// Verificar si ya existe (idempotencia)
+		existing, err := s.userRegRepository.FindByUserID(ctx, userReg.UserID())
+		if err != nil {
+			return nil, fmt.Errorf("error checking existing user %s: %w", result.ID, err)
+		}
+		if existing == nil {
+			if err := s.userRegRepository.Save(ctx, userReg); err != nil {
+				if repositories.IsDataError(err) {
+					results = append(results, rejected(result, err))
+					continue
+				}
+				return nil, fmt.Errorf("error saving user registration %s: %w", result.ID, err)
+			}
+		}
+		result.Status = IngestStatusAccepted
+		result.Duplicate = existing != nil
+		results = append(results, result)
+	}
+
+	logIngest("user registration", results)
+	return results, nil
+}
+
+// rejected marca el resultado como rechazado por err
+func rejected(result IngestResult, err error) IngestResult {
+	result.Status = IngestStatusRejected
+	result.Error = err.Error()
+	return result
+}
+
+// logIngest registra el resumen de un lote ingerido
+func logIngest(kind string, results []IngestResult) {
+	var accepted, duplicates int
+	for _, result := range results {
+		if result.Status == IngestStatusAccepted {
+			accepted++
+			if result.Duplicate {
+				duplicates++
+			}
+		}
+	}
+	log.Printf("Ingested %s events over HTTP: %d accepted (%d already existed), %d rejected",
+		kind, accepted, duplicates, len(results)-accepted)
+}
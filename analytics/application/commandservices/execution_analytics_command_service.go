@@ -20,31 +20,55 @@ func NewExecutionAnalyticsCommandService(repository repositories.ExecutionAnalyt
 	}
 }
 
-// SaveExecutionAnalytics guarda un nuevo registro de analytics
+// SaveExecutionAnalytics guarda un registro de analytics. Si la ejecución ya existe, se reemplaza solo
+// si el evento trae una versión más reciente (re-evaluación o cambio de estado).
 func (s *ExecutionAnalyticsCommandService) SaveExecutionAnalytics(ctx context.Context, execution *aggregates.ExecutionAnalytics) error {
-	// Verificar si ya existe
-	existing, err := s.repository.FindByExecutionID(ctx, execution.ExecutionID())
+	result, err := s.repository.Save(ctx, execution)
 	if err != nil {
-		return fmt.Errorf("error checking existing execution: %w", err)
+		return fmt.Errorf("error saving execution analytics: %w", err)
 	}
 
-	if existing != nil {
-		log.Printf("Execution analytics already exists for execution ID: %s", execution.ExecutionID().Value())
-		return nil // Idempotencia: no es error si ya existe
+	switch {
+	case result.Inserted > 0:
+		log.Printf("Saved execution analytics: %s (Student: %s, Challenge: %s, Success: %v)",
+			execution.ExecutionID().Value(),
+			execution.StudentID().Value(),
+			execution.ChallengeID().Value(),
+			execution.Success(),
+		)
+	case result.Updated > 0:
+		log.Printf("Updated execution analytics: %s to revision %d (Success: %v)",
+			execution.ExecutionID().Value(), execution.Revision(), execution.Success())
+	default:
+		// Idempotencia: no es error si ya existe una versión igual o más reciente
+		log.Printf("Execution analytics already up to date for execution ID: %s", execution.ExecutionID().Value())
 	}
 
-	// Guardar nuevo registro
-	if err := s.repository.Save(ctx, execution); err != nil {
-		return fmt.Errorf("error saving execution analytics: %w", err)
+	return nil
+}
+
+// SaveExecutionAnalyticsBatch guarda un lote de analytics; las ejecuciones existentes solo se reemplazan
+// por versiones más recientes
+func (s *ExecutionAnalyticsCommandService) SaveExecutionAnalyticsBatch(ctx context.Context, executions []*aggregates.ExecutionAnalytics) error {
+	result, err := s.repository.SaveBatch(ctx, executions)
+	if err != nil {
+		return fmt.Errorf("error saving execution analytics batch: %w", err)
 	}
 
-	log.Printf("Saved execution analytics: %s (Student: %s, Challenge: %s, Success: %v)",
-		execution.ExecutionID().Value(),
-		execution.StudentID().Value(),
-		execution.ChallengeID().Value(),
-		execution.Success(),
-	)
+	log.Printf("Saved execution analytics batch: %d inserted, %d updated, %d already up to date",
+		result.Inserted, result.Updated, result.Stale)
+	return nil
+}
 
+// SaveExecutionAnalyticsBatchWithOffset guarda un lote de analytics junto con el offset del consumidor
+func (s *ExecutionAnalyticsCommandService) SaveExecutionAnalyticsBatchWithOffset(ctx context.Context, executions []*aggregates.ExecutionAnalytics, offset repositories.ConsumerOffset) error {
+	result, err := s.repository.SaveBatchWithOffset(ctx, executions, offset)
+	if err != nil {
+		return fmt.Errorf("error saving execution analytics batch with offset: %w", err)
+	}
+
+	log.Printf("Saved execution analytics batch: %d inserted, %d updated, %d already up to date (%s partition %d, next offset %d)",
+		result.Inserted, result.Updated, result.Stale, offset.Topic, offset.Partition, offset.Offset)
 	return nil
 }
 
@@ -52,3 +76,13 @@ func (s *ExecutionAnalyticsCommandService) SaveExecutionAnalytics(ctx context.Co
 func (s *ExecutionAnalyticsCommandService) HandleExecutionAnalyticsEvent(ctx context.Context, execution *aggregates.ExecutionAnalytics) error {
 	return s.SaveExecutionAnalytics(ctx, execution)
 }
+
+// HandleExecutionAnalyticsBatch implementa EventHandler de Kafka para lotes
+func (s *ExecutionAnalyticsCommandService) HandleExecutionAnalyticsBatch(ctx context.Context, executions []*aggregates.ExecutionAnalytics) error {
+	return s.SaveExecutionAnalyticsBatch(ctx, executions)
+}
+
+// HandleExecutionAnalyticsBatchWithOffset implementa EventHandler de Kafka para lotes con offsets guardados en la base de datos
+func (s *ExecutionAnalyticsCommandService) HandleExecutionAnalyticsBatchWithOffset(ctx context.Context, executions []*aggregates.ExecutionAnalytics, offset repositories.ConsumerOffset) error {
+	return s.SaveExecutionAnalyticsBatchWithOffset(ctx, executions, offset)
+}
@@ -0,0 +1,595 @@
+package commandservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// SyncOutcome es el resultado de sincronizar un mensaje
+type SyncOutcome int
+
+const (
+	SyncOutcomeFailed  SyncOutcome = iota // Falla de infraestructura: detiene el job
+	SyncOutcomeSynced                     // Evento guardado
+	SyncOutcomeSkipped                    // El evento ya existía
+	SyncOutcomeInvalid                    // Mensaje que no pudo decodificarse o validarse
+	SyncOutcomeMissing                    // Dry-run: el evento no está en la base de datos
+)
+
+// TopicSyncer sincroniza los mensajes de un tópico hacia la base de datos
+type TopicSyncer interface {
+	Target() valueobjects.SyncTarget
+	Topic() string
+	// SyncMessage procesa un mensaje y retorna el ID de negocio del evento si pudo leerse. En dry-run no escribe:
+	// retorna SyncOutcomeMissing o SyncOutcomeSkipped según el evento exista en la base de datos.
+	// Con SyncOutcomeInvalid el error describe por qué se descartó; con SyncOutcomeFailed el job se detiene
+	// y puede retomarse desde su último checkpoint.
+	SyncMessage(ctx context.Context, msg *events.Message, dryRun bool) (SyncOutcome, string, error)
+}
+
+// invalidMessage descarta un mensaje sin detener el job
+func invalidMessage(err error) (SyncOutcome, string, error) {
+	return SyncOutcomeInvalid, "", err
+}
+
+// undecodableMessage descarta un mensaje cuyo payload no pudo decodificarse, salvo que el error sea
+// temporal (por ejemplo, el Schema Registry no disponible): en ese caso el job se detiene para retomarse
+// desde su checkpoint sin perder el mensaje
+func undecodableMessage(err error) (SyncOutcome, string, error) {
+	if events.IsTemporary(err) {
+		return SyncOutcomeFailed, "", err
+	}
+	return invalidMessage(err)
+}
+
+// ErrSyncJobNotFound se retorna si el job no existe
+var ErrSyncJobNotFound = errors.New("sync job not found")
+
+// ErrUnknownSyncTopic se retorna si ningún syncer lee el tópico indicado
+var ErrUnknownSyncTopic = errors.New("no syncer registered for topic")
+
+// errSyncJobCancelled es la causa de cancelación de un job cancelado por el usuario
+var errSyncJobCancelled = errors.New("sync job cancelled")
+
+const (
+	// Frecuencia con que se guardan los checkpoints de un job en ejecución
+	syncCheckpointInterval = 5 * time.Second
+	syncCheckpointMessages = 500
+	// Tiempo sin mensajes tras el cual una partición se da por terminada aunque no se haya llegado a su último
+	// offset (por ejemplo, si en Kafka termina en marcadores de transacción)
+	syncPartitionIdleTimeout = 30 * time.Second
+	// Tiempo máximo para guardar el estado final de un job
+	syncSaveTimeout = 10 * time.Second
+)
+
+// runningSyncJob es un job en ejecución en esta instancia
+type runningSyncJob struct {
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+}
+
+// syncReport acumula las diferencias encontradas por un job hasta el siguiente checkpoint
+type syncReport struct {
+	items    []*entities.ReconciliationItem
+	seenKeys []string // Dry-run: IDs leídos del tópico, para buscar registros sin evento
+}
+
+// SyncJobService ejecuta en segundo plano los jobs de sincronización de tópicos desde una fuente de mensajes
+// (Kafka o archivos). Cada job lee cada partición desde su offset más antiguo hasta el último offset al
+// iniciar, guardando checkpoints para retomarse tras una cancelación, una falla o un reinicio.
+type SyncJobService struct {
+	source         events.MessageSource
+	repository     repositories.SyncJobRepository
+	reconciliation repositories.ReconciliationRepository
+	syncers        map[valueobjects.SyncTarget]TopicSyncer
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	running map[string]*runningSyncJob
+	wg      sync.WaitGroup
+}
+
+// NewSyncJobService crea una nueva instancia del servicio con un syncer por target que lee los tópicos de source
+func NewSyncJobService(source events.MessageSource, repository repositories.SyncJobRepository, reconciliation repositories.ReconciliationRepository, syncers ...TopicSyncer) *SyncJobService {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	bySyncTarget := make(map[valueobjects.SyncTarget]TopicSyncer, len(syncers))
+	for _, syncer := range syncers {
+		bySyncTarget[syncer.Target()] = syncer
+	}
+
+	return &SyncJobService{
+		source:         source,
+		repository:     repository,
+		reconciliation: reconciliation,
+		syncers:        bySyncTarget,
+		ctx:            ctx,
+		cancel:         cancel,
+		running:        make(map[string]*runningSyncJob),
+	}
+}
+
+// StartJob crea un job para el target y lo ejecuta en segundo plano. En dry-run el job no escribe datos:
+// reporta los eventos que faltan en la base de datos, los registros sin evento y los eventos inválidos.
+// Retorna repositories.ErrSyncJobAlreadyActive si ya hay un job activo para el target.
+func (s *SyncJobService) StartJob(ctx context.Context, target valueobjects.SyncTarget, dryRun bool) (*aggregates.SyncJob, error) {
+	syncer, ok := s.syncers[target]
+	if !ok {
+		return nil, fmt.Errorf("no syncer registered for target %s", target)
+	}
+
+	job, err := aggregates.NewSyncJob(target, syncer.Topic(), dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.create(ctx, job, syncer)
+}
+
+// StartReplay crea un job que reprocesa los mensajes del tópico publicados desde from y, si se indica,
+// antes de to. Los offsets de la ventana los resuelve la fuente (en Kafka, con la API offsets-for-times).
+func (s *SyncJobService) StartReplay(ctx context.Context, topic string, from time.Time, to *time.Time, dryRun bool) (*aggregates.SyncJob, error) {
+	syncer, ok := s.syncerForTopic(topic)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSyncTopic, topic)
+	}
+
+	job, err := aggregates.NewReplayJob(syncer.Target(), topic, from, to, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.create(ctx, job, syncer)
+}
+
+// create guarda un nuevo job y lo ejecuta en segundo plano
+func (s *SyncJobService) create(ctx context.Context, job *aggregates.SyncJob, syncer TopicSyncer) (*aggregates.SyncJob, error) {
+	if err := s.repository.Create(ctx, job); err != nil {
+		if errors.Is(err, repositories.ErrSyncJobAlreadyActive) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error creating sync job: %w", err)
+	}
+
+	log.Printf("Created sync job %s for topic %s", job.ID(), job.Topic())
+	s.launch(job.ID(), syncer)
+	return job, nil
+}
+
+// WaitJob espera a que el job deje de ejecutarse en esta instancia y retorna su estado
+func (s *SyncJobService) WaitJob(ctx context.Context, id string) (*aggregates.SyncJob, error) {
+	s.mu.Lock()
+	running, ok := s.running[id]
+	s.mu.Unlock()
+
+	if ok {
+		select {
+		case <-running.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return s.findJob(ctx, id)
+}
+
+// CancelJob cancela un job activo. Si se ejecuta en esta instancia, espera a que guarde su checkpoint.
+func (s *SyncJobService) CancelJob(ctx context.Context, id string) (*aggregates.SyncJob, error) {
+	s.mu.Lock()
+	running, ok := s.running[id]
+	s.mu.Unlock()
+
+	if ok {
+		running.cancel(errSyncJobCancelled)
+		select {
+		case <-running.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return s.findJob(ctx, id)
+	}
+
+	// Job huérfano (por ejemplo, de una instancia detenida): solo se actualiza su estado
+	job, err := s.findJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := job.Cancel(); err != nil {
+		return nil, err
+	}
+	if err := s.repository.Save(ctx, job); err != nil {
+		return nil, fmt.Errorf("error saving sync job: %w", err)
+	}
+
+	log.Printf("Cancelled sync job %s", id)
+	return job, nil
+}
+
+// ResumeJob retoma un job fallido o cancelado desde sus checkpoints
+func (s *SyncJobService) ResumeJob(ctx context.Context, id string) (*aggregates.SyncJob, error) {
+	if s.isRunning(id) {
+		return nil, fmt.Errorf("%w: job is already running", aggregates.ErrInvalidSyncJobTransition)
+	}
+
+	job, err := s.findJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	syncer, ok := s.syncers[job.Target()]
+	if !ok {
+		return nil, fmt.Errorf("no syncer registered for target %s", job.Target())
+	}
+
+	if err := job.Resume(); err != nil {
+		return nil, err
+	}
+	if err := s.repository.Save(ctx, job); err != nil {
+		if errors.Is(err, repositories.ErrSyncJobAlreadyActive) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error saving sync job: %w", err)
+	}
+
+	log.Printf("Resuming sync job %s for topic %s", job.ID(), job.Topic())
+	s.launch(job.ID(), syncer)
+	return job, nil
+}
+
+// ResumeInterruptedJobs retoma los jobs que quedaron activos al detenerse el servicio
+func (s *SyncJobService) ResumeInterruptedJobs(ctx context.Context) error {
+	jobs, err := s.repository.FindActive(ctx)
+	if err != nil {
+		return fmt.Errorf("error finding active sync jobs: %w", err)
+	}
+
+	for _, job := range jobs {
+		if s.isRunning(job.ID()) {
+			continue
+		}
+
+		syncer, ok := s.syncers[job.Target()]
+		if !ok {
+			log.Printf("Skipping interrupted sync job %s: no syncer registered for target %s", job.ID(), job.Target())
+			continue
+		}
+
+		log.Printf("Resuming interrupted sync job %s for topic %s", job.ID(), job.Topic())
+		s.launch(job.ID(), syncer)
+	}
+	return nil
+}
+
+// Close detiene los jobs en ejecución. Quedan activos con su último checkpoint y se retoman al reiniciar.
+func (s *SyncJobService) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// findJob busca un job y retorna ErrSyncJobNotFound si no existe
+func (s *SyncJobService) findJob(ctx context.Context, id string) (*aggregates.SyncJob, error) {
+	job, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding sync job: %w", err)
+	}
+	if job == nil {
+		return nil, ErrSyncJobNotFound
+	}
+	return job, nil
+}
+
+// syncerForTopic busca el syncer que lee el tópico
+func (s *SyncJobService) syncerForTopic(topic string) (TopicSyncer, bool) {
+	for _, syncer := range s.syncers {
+		if syncer.Topic() == topic {
+			return syncer, true
+		}
+	}
+	return nil, false
+}
+
+// isRunning indica si el job se ejecuta en esta instancia
+func (s *SyncJobService) isRunning(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.running[id]
+	return ok
+}
+
+// launch ejecuta el job en una goroutine
+func (s *SyncJobService) launch(id string, syncer TopicSyncer) {
+	ctx, cancel := context.WithCancelCause(s.ctx)
+	running := &runningSyncJob{cancel: cancel, done: make(chan struct{})}
+
+	s.mu.Lock()
+	s.running[id] = running
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(running.done)
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, id)
+			s.mu.Unlock()
+			cancel(nil)
+		}()
+
+		s.run(ctx, id, syncer)
+	}()
+}
+
+// run ejecuta un job hasta completarlo, cancelarlo, fallar o detener el servicio
+func (s *SyncJobService) run(ctx context.Context, id string, syncer TopicSyncer) {
+	job, err := s.repository.FindByID(ctx, id)
+	if err != nil || job == nil {
+		log.Printf("Error loading sync job %s: %v", id, err)
+		return
+	}
+
+	if job.Status() == valueobjects.SyncJobPending {
+		checkpoints, err := s.partitionRanges(ctx, job)
+		if err != nil {
+			s.finish(job, err)
+			return
+		}
+		if err := job.Start(checkpoints); err != nil {
+			s.finish(job, err)
+			return
+		}
+		s.saveJob(job)
+	}
+
+	report := &syncReport{}
+	err = s.syncPartitions(ctx, job, syncer, report)
+	if err == nil {
+		err = s.reconcile(ctx, job, report)
+	}
+	if err != nil && ctx.Err() != nil {
+		if context.Cause(ctx) == errSyncJobCancelled {
+			err = errSyncJobCancelled
+		} else {
+			// Servicio detenido: el job queda activo y se retoma al reiniciar
+			log.Printf("Sync job %s interrupted at %.1f%%, will resume on restart", job.ID(), job.Progress())
+			s.saveProgress(job, report)
+			return
+		}
+	}
+
+	// Sin el reporte guardado el job queda activo en su último checkpoint y se retoma al reiniciar
+	if s.flushReport(job, report) != nil {
+		return
+	}
+	s.finish(job, err)
+
+	// Los IDs leídos se conservan mientras el job pueda retomarse
+	if job.Status() == valueobjects.SyncJobCompleted && job.ReportsOrphans() {
+		ctx, cancel := context.WithTimeout(context.Background(), syncSaveTimeout)
+		defer cancel()
+		if err := s.reconciliation.DeleteSeenKeys(ctx, job.ID()); err != nil {
+			log.Printf("Error deleting seen keys of sync job %s: %v", job.ID(), err)
+		}
+	}
+}
+
+// reconcile busca, en un dry-run del tópico completo, los registros de la base de datos sin evento en el tópico
+func (s *SyncJobService) reconcile(ctx context.Context, job *aggregates.SyncJob, report *syncReport) error {
+	if !job.ReportsOrphans() {
+		return nil
+	}
+	if err := s.flushReport(job, report); err != nil {
+		return err
+	}
+
+	orphaned, err := s.reconciliation.RecordOrphans(ctx, job.ID(), job.Target())
+	if err != nil {
+		return fmt.Errorf("error finding records without event: %w", err)
+	}
+	job.RecordOrphaned(orphaned)
+	return nil
+}
+
+// finish marca el job como completado, cancelado o fallido según el resultado
+func (s *SyncJobService) finish(job *aggregates.SyncJob, err error) {
+	var transitionErr error
+	switch {
+	case err == nil:
+		transitionErr = job.Complete()
+		if job.DryRun() {
+			log.Printf("Sync job %s (dry-run) completed for topic %s: %d present, %d missing, %d orphaned, %d invalid, %d unread",
+				job.ID(), job.Topic(), job.Skipped(), job.Missing(), job.Orphaned(), job.Invalid(), job.Unread())
+		} else {
+			log.Printf("Sync job %s completed for topic %s: %d synced, %d skipped, %d invalid, %d unread",
+				job.ID(), job.Topic(), job.Synced(), job.Skipped(), job.Invalid(), job.Unread())
+		}
+	case errors.Is(err, errSyncJobCancelled):
+		transitionErr = job.Cancel()
+		log.Printf("Sync job %s cancelled at %.1f%%", job.ID(), job.Progress())
+	default:
+		transitionErr = job.Fail(err)
+		log.Printf("Sync job %s failed at %.1f%%: %v", job.ID(), job.Progress(), err)
+	}
+
+	if transitionErr != nil {
+		log.Printf("Error finishing sync job %s: %v", job.ID(), transitionErr)
+		return
+	}
+	s.saveJob(job)
+}
+
+// saveJob guarda el estado del job aunque el contexto del job ya haya sido cancelado
+func (s *SyncJobService) saveJob(job *aggregates.SyncJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), syncSaveTimeout)
+	defer cancel()
+
+	if err := s.repository.Save(ctx, job); err != nil {
+		log.Printf("Error saving sync job %s: %v", job.ID(), err)
+	}
+}
+
+// saveProgress guarda el reporte acumulado y luego el checkpoint del job. Si el reporte no puede guardarse,
+// el checkpoint no avanza: al retomar el job se vuelven a leer los mensajes.
+func (s *SyncJobService) saveProgress(job *aggregates.SyncJob, report *syncReport) {
+	if s.flushReport(job, report) != nil {
+		return
+	}
+	s.saveJob(job)
+}
+
+// flushReport guarda las diferencias y los IDs acumulados desde el último checkpoint
+func (s *SyncJobService) flushReport(job *aggregates.SyncJob, report *syncReport) error {
+	ctx, cancel := context.WithTimeout(context.Background(), syncSaveTimeout)
+	defer cancel()
+
+	if err := s.reconciliation.SaveItems(ctx, job.ID(), report.items); err != nil {
+		log.Printf("Error saving reconciliation report of sync job %s: %v", job.ID(), err)
+		return fmt.Errorf("error saving reconciliation report: %w", err)
+	}
+	report.items = nil
+
+	if err := s.reconciliation.SaveSeenKeys(ctx, job.ID(), report.seenKeys); err != nil {
+		log.Printf("Error saving seen keys of sync job %s: %v", job.ID(), err)
+		return fmt.Errorf("error saving seen keys: %w", err)
+	}
+	report.seenKeys = nil
+	return nil
+}
+
+// syncPartitions sincroniza las particiones pendientes del job
+func (s *SyncJobService) syncPartitions(ctx context.Context, job *aggregates.SyncJob, syncer TopicSyncer, report *syncReport) error {
+	for _, checkpoint := range job.Checkpoints() {
+		if checkpoint.IsDone() {
+			continue
+		}
+		if err := s.syncPartition(ctx, job, checkpoint, syncer, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncPartition lee una partición desde su checkpoint hasta su offset final
+func (s *SyncJobService) syncPartition(ctx context.Context, job *aggregates.SyncJob, checkpoint *entities.SyncCheckpoint, syncer TopicSyncer, report *syncReport) error {
+	partition := checkpoint.Partition()
+	log.Printf("Sync job %s: syncing partition %d of topic %s from offset %d to %d",
+		job.ID(), partition, job.Topic(), checkpoint.NextOffset(), checkpoint.EndOffset())
+
+	// Si los mensajes desde el checkpoint ya no están disponibles, la fuente continúa desde el más antiguo
+	reader, err := s.source.Read(ctx, job.Topic(), partition, checkpoint.NextOffset())
+	if err != nil {
+		return fmt.Errorf("error reading partition %d: %w", partition, err)
+	}
+	defer reader.Close()
+
+	idle := time.NewTimer(syncPartitionIdleTimeout)
+	defer idle.Stop()
+
+	lastCheckpoint := time.Now()
+	sinceCheckpoint := 0
+
+	for !checkpoint.IsDone() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err := <-reader.Errors():
+			return fmt.Errorf("error reading partition %d: %w", partition, err)
+
+		case <-idle.C:
+			log.Printf("Sync job %s: no messages from partition %d for %v, stopping at offset %d of %d",
+				job.ID(), partition, syncPartitionIdleTimeout, checkpoint.NextOffset(), checkpoint.EndOffset())
+			s.stopPartition(job, checkpoint, report, fmt.Sprintf("no messages for %v", syncPartitionIdleTimeout))
+
+		case msg, ok := <-reader.Messages():
+			if !ok {
+				// Fuente acotada leída por completo
+				log.Printf("Sync job %s: reached end of partition %d at offset %d of %d",
+					job.ID(), partition, checkpoint.NextOffset(), checkpoint.EndOffset())
+				s.stopPartition(job, checkpoint, report, "source ended")
+				continue
+			}
+			idle.Reset(syncPartitionIdleTimeout)
+
+			if msg.Offset >= checkpoint.EndOffset() {
+				// Mensaje publicado después de iniciar el job
+				job.CompletePartition(partition)
+				continue
+			}
+
+			outcome, key, err := syncer.SyncMessage(ctx, msg, job.DryRun())
+			switch outcome {
+			case SyncOutcomeSynced:
+				job.RecordSynced(partition, msg.Offset)
+			case SyncOutcomeSkipped:
+				job.RecordSkipped(partition, msg.Offset)
+			case SyncOutcomeMissing:
+				job.RecordMissing(partition, msg.Offset)
+				report.items = append(report.items, entities.NewReconciliationItem(
+					valueobjects.ReconciliationMissing, key, partition, msg.Offset, "event not found in database"))
+			case SyncOutcomeInvalid:
+				log.Printf("Sync job %s: skipping invalid message (partition %d, offset %d): %v", job.ID(), partition, msg.Offset, err)
+				job.RecordInvalid(partition, msg.Offset)
+				report.items = append(report.items, entities.NewReconciliationItem(
+					valueobjects.ReconciliationInvalid, key, partition, msg.Offset, err.Error()))
+			default:
+				return fmt.Errorf("error syncing message (partition %d, offset %d): %w", partition, msg.Offset, err)
+			}
+			if job.ReportsOrphans() && key != "" {
+				report.seenKeys = append(report.seenKeys, key)
+			}
+
+			sinceCheckpoint++
+			if sinceCheckpoint >= syncCheckpointMessages || time.Since(lastCheckpoint) >= syncCheckpointInterval {
+				s.saveProgress(job, report)
+				sinceCheckpoint = 0
+				lastCheckpoint = time.Now()
+			}
+		}
+	}
+
+	s.saveProgress(job, report)
+	if checkpoint.IsIncomplete() {
+		log.Printf("Sync job %s: partition %d incomplete, %d offset(s) not read (%.1f%% of job)",
+			job.ID(), partition, checkpoint.Unread(), job.Progress())
+		return nil
+	}
+	log.Printf("Sync job %s: completed partition %d (%.1f%% of job)", job.ID(), partition, job.Progress())
+	return nil
+}
+
+// stopPartition da la partición por terminada donde llegó y, si quedaron offsets sin leer, los agrega
+// al reporte para que se vuelvan a sincronizar con otro job
+func (s *SyncJobService) stopPartition(job *aggregates.SyncJob, checkpoint *entities.SyncCheckpoint, report *syncReport, reason string) {
+	job.StopPartition(checkpoint.Partition())
+	if !checkpoint.IsIncomplete() {
+		return
+	}
+	report.items = append(report.items, entities.NewReconciliationItem(
+		valueobjects.ReconciliationUnread, "", checkpoint.Partition(), checkpoint.NextOffset(),
+		fmt.Sprintf("offsets %d to %d not read: %s", checkpoint.NextOffset(), checkpoint.EndOffset()-1, reason)))
+}
+
+// partitionRanges obtiene de la fuente el rango de offsets a sincronizar de cada partición del tópico
+func (s *SyncJobService) partitionRanges(ctx context.Context, job *aggregates.SyncJob) ([]*entities.SyncCheckpoint, error) {
+	ranges, err := s.source.Partitions(ctx, job.Topic(), job.From(), job.To())
+	if err != nil {
+		return nil, fmt.Errorf("error getting partitions of topic %s: %w", job.Topic(), err)
+	}
+
+	checkpoints := make([]*entities.SyncCheckpoint, 0, len(ranges))
+	for _, partitionRange := range ranges {
+		checkpoints = append(checkpoints, entities.NewSyncCheckpoint(partitionRange.Partition, partitionRange.Start, partitionRange.End))
+	}
+	return checkpoints, nil
+}
@@ -0,0 +1,254 @@
+package commandservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/source"
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySyncJobRepository guarda los jobs en memoria
+type memorySyncJobRepository struct {
+	mu   sync.Mutex
+	jobs map[string]*aggregates.SyncJob
+}
+
+func newMemorySyncJobRepository() *memorySyncJobRepository {
+	return &memorySyncJobRepository{jobs: make(map[string]*aggregates.SyncJob)}
+}
+
+func (r *memorySyncJobRepository) Create(ctx context.Context, job *aggregates.SyncJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.jobs {
+		if existing.Target() == job.Target() && existing.Status().IsActive() {
+			return repositories.ErrSyncJobAlreadyActive
+		}
+	}
+	r.jobs[job.ID()] = job
+	return nil
+}
+
+func (r *memorySyncJobRepository) Save(ctx context.Context, job *aggregates.SyncJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.ID()] = job
+	return nil
+}
+
+func (r *memorySyncJobRepository) FindByID(ctx context.Context, id string) (*aggregates.SyncJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs[id], nil
+}
+
+func (r *memorySyncJobRepository) FindActive(ctx context.Context) ([]*aggregates.SyncJob, error) {
+	return nil, nil
+}
+
+func (r *memorySyncJobRepository) FindRecent(ctx context.Context, limit int) ([]*aggregates.SyncJob, error) {
+	return nil, nil
+}
+
+// memoryReconciliationRepository guarda el reporte de reconciliación en memoria
+type memoryReconciliationRepository struct {
+	mu    sync.Mutex
+	items []*entities.ReconciliationItem
+}
+
+func (r *memoryReconciliationRepository) SaveItems(ctx context.Context, jobID string, items []*entities.ReconciliationItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items = append(r.items, items...)
+	return nil
+}
+
+func (r *memoryReconciliationRepository) SaveSeenKeys(ctx context.Context, jobID string, keys []string) error {
+	return nil
+}
+
+func (r *memoryReconciliationRepository) RecordOrphans(ctx context.Context, jobID string, target valueobjects.SyncTarget) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryReconciliationRepository) DeleteSeenKeys(ctx context.Context, jobID string) error {
+	return nil
+}
+
+func (r *memoryReconciliationRepository) FindItems(ctx context.Context, jobID string, kind *valueobjects.ReconciliationKind, limit, offset int) ([]*entities.ReconciliationItem, error) {
+	return nil, nil
+}
+
+// recordingSyncer sincroniza eventos {"execution_id": ...} y registra los IDs guardados
+type recordingSyncer struct {
+	mu     sync.Mutex
+	synced []string
+}
+
+func (s *recordingSyncer) Target() valueobjects.SyncTarget {
+	return valueobjects.SyncTargetExecutionAnalytics
+}
+
+func (s *recordingSyncer) Topic() string {
+	return "executions"
+}
+
+func (s *recordingSyncer) SyncMessage(ctx context.Context, msg *events.Message, dryRun bool) (SyncOutcome, string, error) {
+	var event struct {
+		ExecutionID string `json:"execution_id"`
+	}
+	if err := json.Unmarshal(msg.Value, &event); err != nil || event.ExecutionID == "" {
+		return invalidMessage(errors.New("missing execution_id"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.synced = append(s.synced, event.ExecutionID)
+	return SyncOutcomeSynced, event.ExecutionID, nil
+}
+
+func (s *recordingSyncer) syncedIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := append([]string(nil), s.synced...)
+	sort.Strings(ids)
+	return ids
+}
+
+func TestSyncJobServiceSyncsMemorySource(t *testing.T) {
+	memory := source.NewMemorySource(2)
+	for _, id := range []string{"exec-1", "exec-2", "exec-3", "exec-4"} {
+		memory.Publish("executions", []byte(id), []byte(`{"execution_id":"`+id+`"}`), nil, time.Time{})
+	}
+	memory.Publish("executions", []byte("exec-1"), []byte(`{"language":"go"}`), nil, time.Time{})
+
+	reconciliation := &memoryReconciliationRepository{}
+	syncer := &recordingSyncer{}
+	service := NewSyncJobService(memory, newMemorySyncJobRepository(), reconciliation, syncer)
+	defer service.Close()
+
+	job, err := service.StartJob(context.Background(), valueobjects.SyncTargetExecutionAnalytics, false)
+	if err != nil {
+		t.Fatalf("StartJob: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err = service.WaitJob(ctx, job.ID())
+	if err != nil {
+		t.Fatalf("WaitJob: %v", err)
+	}
+
+	if job.Status() != valueobjects.SyncJobCompleted {
+		t.Fatalf("expected completed job, got %s (%s)", job.Status(), job.ErrorMessage())
+	}
+	if job.Synced() != 4 || job.Invalid() != 1 || job.Unread() != 0 || job.Progress() != 100 {
+		t.Fatalf("unexpected counters: synced %d, invalid %d, unread %d, progress %.1f",
+			job.Synced(), job.Invalid(), job.Unread(), job.Progress())
+	}
+
+	ids := syncer.syncedIDs()
+	if len(ids) != 4 || ids[0] != "exec-1" || ids[3] != "exec-4" {
+		t.Fatalf("unexpected synced events %v", ids)
+	}
+
+	// El mensaje inválido queda en el reporte de reconciliación
+	if len(reconciliation.items) != 1 || reconciliation.items[0].Kind() != valueobjects.ReconciliationInvalid {
+		t.Fatalf("expected one invalid reconciliation item, got %d", len(reconciliation.items))
+	}
+}
+
+func TestSyncJobServiceReplaysTimeWindowFromMemorySource(t *testing.T) {
+	memory := source.NewMemorySource(1)
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"exec-1", "exec-2", "exec-3", "exec-4"} {
+		memory.Publish("executions", nil, []byte(`{"execution_id":"`+id+`"}`), nil, base.Add(time.Duration(i)*time.Hour))
+	}
+
+	syncer := &recordingSyncer{}
+	service := NewSyncJobService(memory, newMemorySyncJobRepository(), &memoryReconciliationRepository{}, syncer)
+	defer service.Close()
+
+	to := base.Add(3 * time.Hour)
+	job, err := service.StartReplay(context.Background(), "executions", base.Add(time.Hour), &to, false)
+	if err != nil {
+		t.Fatalf("StartReplay: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err = service.WaitJob(ctx, job.ID())
+	if err != nil {
+		t.Fatalf("WaitJob: %v", err)
+	}
+
+	if job.Status() != valueobjects.SyncJobCompleted || job.Synced() != 2 {
+		t.Fatalf("expected completed replay with 2 synced events, got %s with %d", job.Status(), job.Synced())
+	}
+	if ids := syncer.syncedIDs(); len(ids) != 2 || ids[0] != "exec-2" || ids[1] != "exec-3" {
+		t.Fatalf("unexpected replayed events %v", ids)
+	}
+}
+
+// unavailableError simula un error temporal del Schema Registry
+type unavailableError struct{}
+
+func (unavailableError) Error() string   { return "schema registry unavailable" }
+func (unavailableError) Temporary() bool { return true }
+
+// failingDecodeSyncer falla al decodificar cada mensaje con el error indicado
+type failingDecodeSyncer struct {
+	recordingSyncer
+	err error
+}
+
+func (s *failingDecodeSyncer) SyncMessage(ctx context.Context, msg *events.Message, dryRun bool) (SyncOutcome, string, error) {
+	return undecodableMessage(s.err)
+}
+
+func TestUndecodableMessageKeepsTemporaryErrorsAsFailures(t *testing.T) {
+	if outcome, _, _ := undecodableMessage(unavailableError{}); outcome != SyncOutcomeFailed {
+		t.Fatalf("expected temporary error to fail the job, got outcome %d", outcome)
+	}
+	if outcome, _, _ := undecodableMessage(errors.New("invalid avro payload")); outcome != SyncOutcomeInvalid {
+		t.Fatalf("expected permanent error to skip the message, got outcome %d", outcome)
+	}
+}
+
+func TestSyncJobServiceStopsAtCheckpointOnTemporaryDecodeError(t *testing.T) {
+	memory := source.NewMemorySource(1)
+	memory.Publish("executions", nil, []byte(`{"execution_id":"exec-1"}`), nil, time.Time{})
+
+	syncer := &failingDecodeSyncer{err: unavailableError{}}
+	service := NewSyncJobService(memory, newMemorySyncJobRepository(), &memoryReconciliationRepository{}, syncer)
+	defer service.Close()
+
+	job, err := service.StartJob(context.Background(), valueobjects.SyncTargetExecutionAnalytics, false)
+	if err != nil {
+		t.Fatalf("StartJob: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err = service.WaitJob(ctx, job.ID())
+	if err != nil {
+		t.Fatalf("WaitJob: %v", err)
+	}
+
+	if job.Status() != valueobjects.SyncJobFailed || job.Invalid() != 0 {
+		t.Fatalf("expected failed job without invalid messages, got %s with %d invalid", job.Status(), job.Invalid())
+	}
+	// El mensaje no se saltó: al retomar el job se vuelve a leer
+	if checkpoint := job.Checkpoint(0); checkpoint.NextOffset() != 0 {
+		t.Fatalf("expected checkpoint to stay at offset 0, got %d", checkpoint.NextOffset())
+	}
+}
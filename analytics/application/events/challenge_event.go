@@ -0,0 +1,108 @@
+package events
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Tipos de los eventos del catálogo de challenges
+const (
+	ChallengeCreatedEventType  = "challenge.created"
+	ChallengeUpdatedEventType  = "challenge.updated"
+	ChallengeArchivedEventType = "challenge.archived"
+)
+
+// ChallengeSchemaVersion es la versión de esquema que entiende ChallengeEvent
+const ChallengeSchemaVersion = 1
+
+// challengeUpcasters migra payloads antiguos a ChallengeSchemaVersion
+var challengeUpcasters = NewUpcasterChain("challenge", ChallengeSchemaVersion)
+
+// ChallengeEvent representa el payload de los eventos de creación, actualización y archivado de challenges
+type ChallengeEvent struct {
+	ChallengeID string     `json:"challenge_id"`
+	Title       string     `json:"title"`
+	Difficulty  string     `json:"difficulty"`
+	Tags        []string   `json:"tags"`
+	CourseID    string     `json:"course_id"`
+	TestCount   int        `json:"test_count"`
+	Archived    *bool      `json:"archived,omitempty"`    // Eventos legados sin tipo: true equivale a un archivado
+	OccurredAt  *time.Time `json:"occurred_at,omitempty"` // Fecha del cambio en el servicio de challenges
+}
+
+// DecodeChallenge migra el payload del envelope a la versión actual y lo convierte a dominio.
+// El tipo de cambio sale del sufijo del tipo de evento (created, updated o archived). La fecha del cambio
+// es la del payload, el occurred_at del envelope o, en su defecto, messageTime (el timestamp del mensaje).
+func DecodeChallenge(envelope *Envelope, messageTime time.Time) (*aggregates.Challenge, error) {
+	payload, err := challengeUpcasters.Upcast(envelope.SchemaVersion, envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var event ChallengeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("error unmarshaling challenge event: %w", err)
+	}
+
+	change := challengeChange(envelope.Type)
+	if change != valueobjects.ChallengeArchived && event.Archived != nil && *event.Archived {
+		change = valueobjects.ChallengeArchived
+	}
+
+	occurredAt := messageTime
+	if event.OccurredAt != nil {
+		occurredAt = *event.OccurredAt
+	} else if envelope.OccurredAt != nil {
+		occurredAt = *envelope.OccurredAt
+	}
+
+	log.Printf("Processing challenge %s event v%d: ChallengeID=%s, Title=%s",
+		change, envelope.SchemaVersion, event.ChallengeID, event.Title)
+
+	challenge, err := event.ToDomain(change, occurredAt.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error converting challenge event to domain: %w", err)
+	}
+	challenge.SetEventID(envelope.ID)
+
+	return challenge, nil
+}
+
+// challengeChange obtiene el tipo de cambio del tipo de evento. Los tipos desconocidos se tratan como actualización.
+func challengeChange(eventType string) valueobjects.ChallengeChange {
+	suffix := eventType[strings.LastIndex(eventType, ".")+1:]
+	change, err := valueobjects.NewChallengeChange(suffix)
+	if err != nil {
+		return valueobjects.ChallengeUpdated
+	}
+	return change
+}
+
+// ToDomain convierte el evento a un aggregate de dominio
+func (e *ChallengeEvent) ToDomain(change valueobjects.ChallengeChange, occurredAt time.Time) (*aggregates.Challenge, error) {
+	challengeID, err := valueobjects.NewChallengeID(e.ChallengeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid challenge ID: %w", err)
+	}
+
+	challenge, err := aggregates.NewChallenge(
+		challengeID,
+		change,
+		e.Title,
+		e.Difficulty,
+		e.Tags,
+		e.CourseID,
+		e.TestCount,
+		occurredAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating challenge aggregate: %w", err)
+	}
+
+	return challenge, nil
+}
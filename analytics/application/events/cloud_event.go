@@ -0,0 +1,173 @@
+package events
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CloudEventsSpecVersion es la versión de la especificación CloudEvents soportada
+const CloudEventsSpecVersion = "1.0"
+
+// CloudEventsContentType es el content type del modo estructurado
+const CloudEventsContentType = "application/cloudevents+json"
+
+// CloudEventHeaderPrefix es el prefijo de los atributos CloudEvents en los headers de Kafka (modo binario)
+const CloudEventHeaderPrefix = "ce_"
+
+// CloudEvent representa un evento CloudEvents 1.0. En modo estructurado se serializa como JSON;
+// en modo binario sus atributos viajan como headers y data como cuerpo del mensaje.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	Type            string          `json:"type"`
+	Source          string          `json:"source"`
+	ID              string          `json:"id"`
+	Time            *time.Time      `json:"time,omitempty"`
+	Subject         string          `json:"subject,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataSchema      string          `json:"dataschema,omitempty"`
+	SchemaVersion   int             `json:"schemaversion,omitempty"` // Extensión: versión de esquema de data
+	Data            json.RawMessage `json:"data,omitempty"`
+	DataBase64      string          `json:"data_base64,omitempty"`
+}
+
+// NewCloudEvent crea un CloudEvent con data serializada como JSON
+func NewCloudEvent(eventType, source, id string, occurredAt time.Time, schemaVersion int, data interface{}) (*CloudEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling cloud event data: %w", err)
+	}
+
+	occurredAt = occurredAt.UTC()
+	event := &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		Type:            eventType,
+		Source:          source,
+		ID:              id,
+		Time:            &occurredAt,
+		DataContentType: "application/json",
+		SchemaVersion:   schemaVersion,
+		Data:            payload,
+	}
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// ParseCloudEvent deserializa un CloudEvent en modo estructurado
+func ParseCloudEvent(data []byte) (*CloudEvent, error) {
+	var event CloudEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("error unmarshaling cloud event: %w", err)
+	}
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// CloudEventFromAttributes construye un CloudEvent en modo binario a partir de sus atributos
+// (sin el prefijo de transporte) y del cuerpo del mensaje
+func CloudEventFromAttributes(attributes map[string]string, data []byte) (*CloudEvent, error) {
+	event := &CloudEvent{
+		SpecVersion:     attributes["specversion"],
+		Type:            attributes["type"],
+		Source:          attributes["source"],
+		ID:              attributes["id"],
+		Subject:         attributes["subject"],
+		DataContentType: attributes["datacontenttype"],
+		DataSchema:      attributes["dataschema"],
+		Data:            json.RawMessage(data),
+	}
+
+	if value := attributes["time"]; value != "" {
+		occurredAt, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cloud event time %q: %w", value, err)
+		}
+		event.Time = &occurredAt
+	}
+
+	if value := attributes["schemaversion"]; value != "" {
+		version, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cloud event schemaversion %q: %w", value, err)
+		}
+		event.SchemaVersion = version
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Validate verifica los atributos requeridos por la especificación
+func (e *CloudEvent) Validate() error {
+	if !strings.HasPrefix(e.SpecVersion, "1.") {
+		return fmt.Errorf("unsupported cloud events specversion %q", e.SpecVersion)
+	}
+	if e.ID == "" || e.Source == "" || e.Type == "" {
+		return fmt.Errorf("cloud event requires id, source and type")
+	}
+	return nil
+}
+
+// Attributes retorna los atributos del evento para el modo binario (sin data)
+func (e *CloudEvent) Attributes() map[string]string {
+	attributes := map[string]string{
+		"specversion": e.SpecVersion,
+		"type":        e.Type,
+		"source":      e.Source,
+		"id":          e.ID,
+	}
+	if e.Time != nil {
+		attributes["time"] = e.Time.UTC().Format(time.RFC3339Nano)
+	}
+	if e.Subject != "" {
+		attributes["subject"] = e.Subject
+	}
+	if e.DataContentType != "" {
+		attributes["datacontenttype"] = e.DataContentType
+	}
+	if e.DataSchema != "" {
+		attributes["dataschema"] = e.DataSchema
+	}
+	if e.SchemaVersion > 0 {
+		attributes["schemaversion"] = strconv.Itoa(e.SchemaVersion)
+	}
+	return attributes
+}
+
+// Envelope convierte el CloudEvent al envelope que entienden los decoders de eventos
+func (e *CloudEvent) Envelope() (*Envelope, error) {
+	payload := e.Data
+	if e.DataBase64 != "" {
+		decoded, err := base64.StdEncoding.DecodeString(e.DataBase64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cloud event data_base64: %w", err)
+		}
+		payload = decoded
+	}
+	if len(payload) == 0 || string(payload) == "null" {
+		return nil, fmt.Errorf("cloud event %s has no data", e.ID)
+	}
+
+	schemaVersion := e.SchemaVersion
+	if schemaVersion <= 0 {
+		schemaVersion = LegacySchemaVersion
+	}
+
+	return &Envelope{
+		Type:          e.Type,
+		SchemaVersion: schemaVersion,
+		ID:            e.ID,
+		Source:        e.Source,
+		OccurredAt:    e.Time,
+		Payload:       payload,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// CodeVersionCreatedEventType es el tipo de los eventos de nuevas versiones de código
+const CodeVersionCreatedEventType = "code.version.created"
+
+// CodeVersionSchemaVersion es la versión de esquema que entiende CodeVersionEvent
+const CodeVersionSchemaVersion = 1
+
+// codeVersionUpcasters migra payloads antiguos a CodeVersionSchemaVersion
+var codeVersionUpcasters = NewUpcasterChain(CodeVersionCreatedEventType, CodeVersionSchemaVersion)
+
+// CodeVersionEvent representa el payload del evento de una nueva versión de código
+type CodeVersionEvent struct {
+	CodeVersionID   string     `json:"code_version_id"`
+	ChallengeID     string     `json:"challenge_id"`
+	StudentID       string     `json:"student_id"`
+	VersionNumber   int        `json:"version_number"`
+	ParentVersionID string     `json:"parent_version_id,omitempty"`
+	LinesChanged    int        `json:"lines_changed"`
+	Timestamp       *time.Time `json:"timestamp,omitempty"`
+}
+
+// DecodeCodeVersion migra el payload del envelope a la versión actual y lo convierte a dominio.
+// Si el payload no trae timestamp, la fecha de la versión es el occurred_at del envelope o, en su defecto,
+// messageTime (el timestamp del mensaje).
+func DecodeCodeVersion(envelope *Envelope, messageTime time.Time) (*aggregates.CodeVersion, error) {
+	payload, err := codeVersionUpcasters.Upcast(envelope.SchemaVersion, envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var event CodeVersionEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("error unmarshaling code version event: %w", err)
+	}
+
+	createdAt := messageTime
+	if event.Timestamp != nil {
+		createdAt = *event.Timestamp
+	} else if envelope.OccurredAt != nil {
+		createdAt = *envelope.OccurredAt
+	}
+
+	log.Printf("Processing code version event v%d: CodeVersionID=%s, Version=%d, ChallengeID=%s, StudentID=%s",
+		envelope.SchemaVersion, event.CodeVersionID, event.VersionNumber, event.ChallengeID, event.StudentID)
+
+	version, err := event.ToDomain(createdAt.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error converting code version event to domain: %w", err)
+	}
+	version.SetEventID(envelope.ID)
+
+	return version, nil
+}
+
+// ToDomain convierte el evento a un aggregate de dominio
+func (e *CodeVersionEvent) ToDomain(createdAt time.Time) (*aggregates.CodeVersion, error) {
+	codeVersionID, err := valueobjects.NewCodeVersionID(e.CodeVersionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid code version ID: %w", err)
+	}
+
+	challengeID, err := valueobjects.NewChallengeID(e.ChallengeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid challenge ID: %w", err)
+	}
+
+	studentID, err := valueobjects.NewStudentID(e.StudentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid student ID: %w", err)
+	}
+
+	var parentVersionID *valueobjects.CodeVersionID
+	if e.ParentVersionID != "" {
+		parent, err := valueobjects.NewCodeVersionID(e.ParentVersionID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent version ID: %w", err)
+		}
+		parentVersionID = &parent
+	}
+
+	version, err := aggregates.NewCodeVersion(
+		codeVersionID,
+		challengeID,
+		studentID,
+		e.VersionNumber,
+		parentVersionID,
+		e.LinesChanged,
+		createdAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating code version aggregate: %w", err)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,130 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LegacySchemaVersion es la versión asignada a los eventos publicados sin envelope
+const LegacySchemaVersion = 1
+
+// Envelope es el sobre versionado que envuelve el payload de un evento
+type Envelope struct {
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schema_version"`
+	ID            string          `json:"id"`
+	Source        string          `json:"source,omitempty"`
+	OccurredAt    *time.Time      `json:"occurred_at,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// ParseMessage interpreta un mensaje en cualquiera de los formatos soportados: CloudEvents en modo
+// binario (atributos ce_* en los headers), CloudEvents en modo estructurado, envelope versionado o
+// evento legado. headers contiene los headers del mensaje, puede ser nil.
+func ParseMessage(data []byte, headers map[string]string, defaultType string) (*Envelope, error) {
+	if attributes := BinaryCloudEventAttributes(headers); attributes != nil {
+		event, err := CloudEventFromAttributes(attributes, data)
+		if err != nil {
+			return nil, err
+		}
+		return event.Envelope()
+	}
+	return ParseEnvelope(data, defaultType)
+}
+
+// ParseEnvelope interpreta un mensaje como CloudEvent estructurado, envelope versionado o, si no es
+// ninguno, como evento legado. Los eventos legados reciben el tipo por defecto y la versión LegacySchemaVersion.
+func ParseEnvelope(data []byte, defaultType string) (*Envelope, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("error unmarshaling event: %w", err)
+	}
+
+	if isCloudEvent(fields) {
+		event, err := ParseCloudEvent(data)
+		if err != nil {
+			return nil, err
+		}
+		return event.Envelope()
+	}
+
+	if !isEnvelope(fields) {
+		return &Envelope{
+			Type:          defaultType,
+			SchemaVersion: LegacySchemaVersion,
+			Payload:       json.RawMessage(data),
+		}, nil
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("error unmarshaling event envelope: %w", err)
+	}
+
+	if envelope.Type == "" {
+		envelope.Type = defaultType
+	}
+	if envelope.SchemaVersion <= 0 {
+		return nil, fmt.Errorf("invalid schema version %d for event %s", envelope.SchemaVersion, envelope.ID)
+	}
+	if len(envelope.Payload) == 0 || string(envelope.Payload) == "null" {
+		return nil, fmt.Errorf("event envelope %s has no payload", envelope.ID)
+	}
+
+	return &envelope, nil
+}
+
+// PeekEventType retorna el tipo declarado por el mensaje sin decodificar el payload,
+// o "" si es un evento legado o no es JSON
+func PeekEventType(data []byte, headers map[string]string) string {
+	if attributes := BinaryCloudEventAttributes(headers); attributes != nil {
+		return attributes["type"]
+	}
+
+	var fields struct {
+		SpecVersion   string           `json:"specversion"`
+		SchemaVersion *json.RawMessage `json:"schema_version"`
+		Type          string           `json:"type"`
+	}
+	if json.Unmarshal(data, &fields) != nil {
+		return ""
+	}
+	if fields.SpecVersion != "" || fields.SchemaVersion != nil {
+		return fields.Type
+	}
+	return ""
+}
+
+// BinaryCloudEventAttributes extrae los atributos CloudEvents de los headers de Kafka (prefijo ce_).
+// Retorna nil si el mensaje no está en modo binario.
+func BinaryCloudEventAttributes(headers map[string]string) map[string]string {
+	if headers["ce_specversion"] == "" {
+		return nil
+	}
+
+	attributes := make(map[string]string)
+	for key, value := range headers {
+		if name, ok := strings.CutPrefix(key, CloudEventHeaderPrefix); ok && name != "" {
+			attributes[name] = value
+		}
+	}
+	if contentType := headers["content-type"]; contentType != "" {
+		attributes["datacontenttype"] = contentType
+	}
+	return attributes
+}
+
+// isCloudEvent detecta un CloudEvent en modo estructurado
+func isCloudEvent(fields map[string]json.RawMessage) bool {
+	_, hasSpecVersion := fields["specversion"]
+	return hasSpecVersion
+}
+
+// isEnvelope detecta el formato envelope: un payload acompañado de su versión de esquema
+func isEnvelope(fields map[string]json.RawMessage) bool {
+	_, hasPayload := fields["payload"]
+	_, hasVersion := fields["schema_version"]
+	return hasPayload && hasVersion
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEnvelope(t *testing.T) {
+	occurredAt := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		data    string
+		want    Envelope
+		invalid bool
+	}{
+		{
+			name: "legacy payload without envelope",
+			data: `{"userId":"u1","username":"ada"}`,
+			want: Envelope{Type: "user.registered", SchemaVersion: LegacySchemaVersion, Payload: []byte(`{"userId":"u1","username":"ada"}`)},
+		},
+		{
+			name: "versioned envelope",
+			data: `{"type":"user.registered.v2","schema_version":2,"id":"evt-1","source":"iam","occurred_at":"2024-03-15T10:30:00Z","payload":{"userId":"u1"}}`,
+			want: Envelope{Type: "user.registered.v2", SchemaVersion: 2, ID: "evt-1", Source: "iam", OccurredAt: &occurredAt, Payload: []byte(`{"userId":"u1"}`)},
+		},
+		{
+			name: "envelope without type gets the default type",
+			data: `{"schema_version":1,"id":"evt-2","payload":{"userId":"u1"}}`,
+			want: Envelope{Type: "user.registered", SchemaVersion: 1, ID: "evt-2", Payload: []byte(`{"userId":"u1"}`)},
+		},
+		{
+			name: "structured cloud event",
+			data: `{"specversion":"1.0","type":"user.registered","source":"iam","id":"evt-3","time":"2024-03-15T10:30:00Z","schemaversion":3,"data":{"userId":"u1"}}`,
+			want: Envelope{Type: "user.registered", SchemaVersion: 3, ID: "evt-3", Source: "iam", OccurredAt: &occurredAt, Payload: []byte(`{"userId":"u1"}`)},
+		},
+		{name: "unsupported schema version zero", data: `{"schema_version":0,"id":"evt-4","payload":{}}`, invalid: true},
+		{name: "negative schema version", data: `{"schema_version":-1,"id":"evt-5","payload":{}}`, invalid: true},
+		{name: "envelope without payload", data: `{"schema_version":1,"id":"evt-6","payload":null}`, invalid: true},
+		{name: "not a JSON object", data: `not json`, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := ParseEnvelope([]byte(tt.data), "user.registered")
+			if tt.invalid {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", envelope)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEnvelope: %v", err)
+			}
+			assertEnvelope(t, envelope, tt.want)
+		})
+	}
+}
+
+func TestParseMessageReadsBinaryCloudEvents(t *testing.T) {
+	occurredAt := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	headers := map[string]string{
+		"ce_specversion":   "1.0",
+		"ce_type":          "user.registered",
+		"ce_source":        "iam",
+		"ce_id":            "evt-1",
+		"ce_time":          "2024-03-15T10:30:00Z",
+		"ce_schemaversion": "2",
+		"content-type":     "application/json",
+	}
+
+	envelope, err := ParseMessage([]byte(`{"userId":"u1"}`), headers, "default")
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+	assertEnvelope(t, envelope, Envelope{Type: "user.registered", SchemaVersion: 2, ID: "evt-1", Source: "iam", OccurredAt: &occurredAt, Payload: []byte(`{"userId":"u1"}`)})
+
+	headers["ce_schemaversion"] = "two"
+	if _, err := ParseMessage([]byte(`{"userId":"u1"}`), headers, "default"); err == nil {
+		t.Fatalf("expected error for invalid schema version header")
+	}
+}
+
+func TestParseMessageWithoutCloudEventHeadersParsesEnvelope(t *testing.T) {
+	envelope, err := ParseMessage([]byte(`{"userId":"u1"}`), map[string]string{"trace-id": "abc"}, "user.registered")
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+	assertEnvelope(t, envelope, Envelope{Type: "user.registered", SchemaVersion: LegacySchemaVersion, Payload: []byte(`{"userId":"u1"}`)})
+}
+
+// assertEnvelope compara un envelope con el esperado
+func assertEnvelope(t *testing.T, got *Envelope, want Envelope) {
+	t.Helper()
+
+	if got.Type != want.Type || got.SchemaVersion != want.SchemaVersion || got.ID != want.ID || got.Source != want.Source {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if string(got.Payload) != string(want.Payload) {
+		t.Fatalf("expected payload %s, got %s", want.Payload, got.Payload)
+	}
+	if (got.OccurredAt == nil) != (want.OccurredAt == nil) || (got.OccurredAt != nil && !got.OccurredAt.Equal(*want.OccurredAt)) {
+		t.Fatalf("expected occurred at %v, got %v", want.OccurredAt, got.OccurredAt)
+	}
+}
@@ -0,0 +1,220 @@
+package events
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ExecutionAnalyticsEventType es el tipo de los eventos de ejecución de código
+const ExecutionAnalyticsEventType = "execution.analytics"
+
+// ExecutionAnalyticsUpdatedEventType es el tipo de los eventos de re-evaluación o cambio de estado de una
+// ejecución. Traen la ejecución completa y reemplazan a la guardada si son más recientes.
+const ExecutionAnalyticsUpdatedEventType = "execution.analytics.updated"
+
+// ExecutionAnalyticsSchemaVersion es la versión de esquema que entiende ExecutionAnalyticsEvent
+const ExecutionAnalyticsSchemaVersion = 1
+
+// executionAnalyticsUpcasters migra payloads antiguos a ExecutionAnalyticsSchemaVersion.
+// Al publicar una nueva versión se incrementa la versión actual y se registra el upcaster de la anterior.
+var executionAnalyticsUpcasters = NewUpcasterChain(ExecutionAnalyticsEventType, ExecutionAnalyticsSchemaVersion)
+
+// ExecutionAnalyticsEvent representa el payload del evento de ejecución de código
+type ExecutionAnalyticsEvent struct {
+	ExecutionID     string            `json:"execution_id"`
+	ChallengeID     string            `json:"challenge_id"`
+	CodeVersionID   string            `json:"code_version_id"`
+	StudentID       string            `json:"student_id"`
+	Language        string            `json:"language"`
+	LanguageVersion string            `json:"language_version,omitempty"` // Versión del runtime, tiene prioridad sobre la del registro
+	Status          string            `json:"status"`
+	Timestamp       time.Time         `json:"timestamp"`
+	ExecutionTimeMs int64             `json:"execution_time_ms"`
+	ExitCode        int               `json:"exit_code"`
+	TotalTests      int               `json:"total_tests"`
+	PassedTests     int               `json:"passed_tests"`
+	FailedTests     int               `json:"failed_tests"`
+	Success         bool              `json:"success"`
+	Outcome         string            `json:"outcome,omitempty"` // Resultado del juez (accepted, compile_error, WA, TLE...)
+	TestResults     []TestResultEvent `json:"test_results"`
+	ServerInstance  string            `json:"server_instance,omitempty"`
+	Revision        int64             `json:"revision,omitempty"`  // Aumenta con cada re-evaluación del juez
+	JudgedAt        *time.Time        `json:"judged_at,omitempty"` // Fecha del resultado
+}
+
+// TestResultEvent representa un resultado de test en el evento
+type TestResultEvent struct {
+	TestID         string   `json:"test_id"`
+	TestName       string   `json:"test_name"`
+	Passed         bool     `json:"passed"`
+	ErrorMessage   string   `json:"error_message,omitempty"`
+	DurationMs     *int64   `json:"duration_ms,omitempty"`
+	Visibility     string   `json:"visibility,omitempty"` // public (por defecto) o hidden
+	Weight         *float64 `json:"weight,omitempty"`     // Puntos del test, 1 por defecto
+	Points         *float64 `json:"points,omitempty"`     // Alias de weight
+	ExpectedOutput string   `json:"expected_output,omitempty"`
+	ActualOutput   string   `json:"actual_output,omitempty"`
+}
+
+// DecodeExecutionAnalytics migra el payload del envelope a la versión actual y lo convierte a dominio.
+// El id y el occurred_at del envelope (o del CloudEvent) se asignan al aggregate. La versión del resultado
+// es la revision del payload y su judged_at o, en su defecto, el occurred_at del envelope o el timestamp.
+// El lenguaje se normaliza con languages.
+func DecodeExecutionAnalytics(envelope *Envelope, languages *valueobjects.LanguageRegistry) (*aggregates.ExecutionAnalytics, error) {
+	payload, err := executionAnalyticsUpcasters.Upcast(envelope.SchemaVersion, envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var event ExecutionAnalyticsEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("error unmarshaling event: %w", err)
+	}
+
+	// El occurred_at del envelope se usa si el payload no trae su propio timestamp
+	if event.Timestamp.IsZero() && envelope.OccurredAt != nil {
+		event.Timestamp = *envelope.OccurredAt
+	}
+	if event.JudgedAt == nil && envelope.OccurredAt != nil {
+		event.JudgedAt = envelope.OccurredAt
+	}
+
+	log.Printf("Processing execution analytics event v%d: ExecutionID=%s, ChallengeID=%s, StudentID=%s",
+		envelope.SchemaVersion, event.ExecutionID, event.ChallengeID, event.StudentID)
+
+	execution, err := event.ToDomain(languages)
+	if err != nil {
+		return nil, fmt.Errorf("error converting event to domain: %w", err)
+	}
+	execution.SetEventID(envelope.ID)
+
+	return execution, nil
+}
+
+// ToDomain convierte el evento a un aggregate de dominio. Los lenguajes fuera del registro se guardan como
+// valueobjects.LanguageOther con el valor recibido.
+func (e *ExecutionAnalyticsEvent) ToDomain(languages *valueobjects.LanguageRegistry) (*aggregates.ExecutionAnalytics, error) {
+	// Crear value objects
+	executionID, err := valueobjects.NewExecutionID(e.ExecutionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid execution ID: %w", err)
+	}
+
+	challengeID, err := valueobjects.NewChallengeID(e.ChallengeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid challenge ID: %w", err)
+	}
+
+	studentID, err := valueobjects.NewStudentID(e.StudentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid student ID: %w", err)
+	}
+
+	language, err := languages.Resolve(e.Language)
+	if err != nil {
+		return nil, fmt.Errorf("invalid programming language: %w", err)
+	}
+	if !language.IsRecognized() {
+		log.Printf("Warning: language %q is not in the language registry, saving execution %s as %s", e.Language, e.ExecutionID, valueobjects.LanguageOther)
+	}
+	if e.LanguageVersion != "" {
+		language = valueobjects.RestoreProgrammingLanguage(language.Value(), language.Family(), e.LanguageVersion, language.Raw())
+	}
+
+	status, err := valueobjects.NewExecutionStatus(e.Status)
+	if err != nil {
+		return nil, fmt.Errorf("invalid execution status: %w", err)
+	}
+
+	// Crear aggregate
+	execution, err := aggregates.NewExecutionAnalytics(
+		executionID,
+		challengeID,
+		e.CodeVersionID,
+		studentID,
+		language,
+		status,
+		e.Timestamp,
+		e.ExecutionTimeMs,
+		e.ExitCode,
+		e.TotalTests,
+		e.PassedTests,
+		e.FailedTests,
+		e.Success,
+		e.ServerInstance,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating execution analytics aggregate: %w", err)
+	}
+
+	// Sin outcome, con uno desconocido o con uno que contradice success, se mantiene el derivado del estado,
+	// el exit code y los tests: success es el campo del que dependen los KPIs
+	if e.Outcome != "" {
+		outcome, err := valueobjects.NewExecutionOutcome(e.Outcome)
+		if err != nil {
+			log.Printf("Warning: unknown outcome %q in execution %s, using derived outcome %s", e.Outcome, e.ExecutionID, execution.Outcome())
+		} else if err := execution.SetOutcome(outcome); err != nil {
+			log.Printf("Warning: %v in execution %s, using derived outcome %s", err, e.ExecutionID, execution.Outcome())
+		}
+	}
+
+	var judgedAt time.Time
+	if e.JudgedAt != nil {
+		judgedAt = *e.JudgedAt
+	}
+	execution.SetVersion(e.Revision, judgedAt)
+
+	// Agregar test results
+	for _, tr := range e.TestResults {
+		testID, err := valueobjects.NewTestID(tr.TestID)
+		if err != nil {
+			log.Printf("Warning: invalid test ID %s, skipping test result", tr.TestID)
+			continue
+		}
+
+		testResult := entities.NewTestResult(
+			testID,
+			tr.TestName,
+			tr.Passed,
+			tr.ErrorMessage,
+		)
+		tr.applyDetails(testResult)
+		execution.AddTestResult(testResult)
+	}
+
+	return execution, nil
+}
+
+// applyDetails agrega al test result la duración, la visibilidad, el peso y las salidas del evento.
+// Los valores inválidos se descartan con una advertencia y el test mantiene los valores por defecto.
+func (t *TestResultEvent) applyDetails(testResult *entities.TestResult) {
+	if t.DurationMs != nil {
+		if err := testResult.SetDuration(*t.DurationMs); err != nil {
+			log.Printf("Warning: invalid duration %d for test %s: %v", *t.DurationMs, t.TestID, err)
+		}
+	}
+
+	visibility, err := valueobjects.NewTestVisibility(t.Visibility)
+	if err != nil {
+		log.Printf("Warning: invalid visibility %q for test %s, using %s", t.Visibility, t.TestID, valueobjects.TestVisibilityPublic)
+	} else {
+		testResult.SetVisibility(visibility)
+	}
+
+	weight := t.Weight
+	if weight == nil {
+		weight = t.Points
+	}
+	if weight != nil {
+		if err := testResult.SetWeight(*weight); err != nil {
+			log.Printf("Warning: invalid weight %v for test %s: %v", *weight, t.TestID, err)
+		}
+	}
+
+	testResult.SetOutputs(t.ExpectedOutput, t.ActualOutput)
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTimeWindowUnsupported se retorna si la fuente no conoce el timestamp de sus mensajes
+var ErrTimeWindowUnsupported = errors.New("message source does not support time windows")
+
+// Message es un mensaje de eventos leído de una MessageSource, independiente del broker
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+	Timestamp time.Time // Cero si la fuente no lo conoce
+}
+
+// PartitionRange es el rango de offsets [Start, End) de una partición
+type PartitionRange struct {
+	Partition int32
+	Start     int64
+	End       int64
+}
+
+// MessageSource es el origen de los mensajes de eventos: Kafka, archivos JSONL o un canal en memoria.
+// Los offsets son posiciones dentro de cada partición y crecen con cada mensaje.
+type MessageSource interface {
+	// Partitions retorna el rango de offsets disponible en cada partición del tópico. Con from o to, el rango
+	// se limita a los mensajes publicados en [from, to); retorna ErrTimeWindowUnsupported si la fuente no lo permite.
+	Partitions(ctx context.Context, topic string, from, to *time.Time) ([]PartitionRange, error)
+	// Read lee una partición en orden desde offset, o desde el offset más antiguo si offset ya no está disponible
+	Read(ctx context.Context, topic string, partition int32, offset int64) (MessageReader, error)
+}
+
+// MessageReader entrega los mensajes de una partición
+type MessageReader interface {
+	// Messages entrega los mensajes en orden. Se cierra al llegar al final de una fuente acotada (archivos);
+	// las fuentes en vivo (Kafka, memoria) esperan mensajes nuevos hasta Close.
+	Messages() <-chan *Message
+	// Errors entrega los errores de lectura
+	Errors() <-chan error
+	Close() error
+}
@@ -0,0 +1,20 @@
+package events
+
+import (
+	"context"
+	"errors"
+)
+
+// PayloadDecoder convierte el valor serializado de un mensaje (JSON, Avro, Protobuf...)
+// al JSON canónico que entienden los decoders de eventos.
+// Los errores que pueden resolverse reintentando (por ejemplo, el Schema Registry no disponible)
+// implementan Temporary() bool.
+type PayloadDecoder interface {
+	Decode(ctx context.Context, data []byte) ([]byte, error)
+}
+
+// IsTemporary indica si un error se declara temporal: reintentar puede resolverlo
+func IsTemporary(err error) bool {
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
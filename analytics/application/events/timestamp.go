@@ -0,0 +1,156 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// epochMillisThreshold separa los epoch en segundos de los epoch en milisegundos:
+// 1e11 segundos es el año 5138, mientras que 1e11 milisegundos es 1973
+const epochMillisThreshold = 1e11
+
+// localTimeLayouts son los formatos ISO-8601 aceptados sin offset, interpretados en la zona de origen
+var localTimeLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// offsetTimeLayouts son los formatos ISO-8601 aceptados con offset
+var offsetTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04Z07:00",
+}
+
+// TimestampPolicy define cómo interpretar las fechas de los eventos producidos por otros servicios
+type TimestampPolicy struct {
+	Location     *time.Location // Zona de las fechas sin offset (array de LocalDateTime o ISO-8601 local). nil = UTC
+	MaxClockSkew time.Duration  // Tolerancia para fechas en el futuro por diferencias de reloj con el productor
+}
+
+// tolerateSkew lleva al instante actual una fecha apenas en el futuro por diferencias de reloj.
+// Las fechas más allá de la tolerancia se retornan sin cambios para que el dominio las rechace.
+func (p TimestampPolicy) tolerateSkew(t time.Time) time.Time {
+	now := time.Now()
+	if t.After(now) && t.Sub(now) <= p.MaxClockSkew {
+		log.Printf("Occurred date %s is %s ahead of local clock, using current time", t.Format(time.RFC3339Nano), t.Sub(now))
+		return now
+	}
+	return t
+}
+
+// ParseTimestamp convierte una fecha de un payload JSON a time.Time. Acepta:
+//   - el array de LocalDateTime de Java [year, month, day, hour, minute, second, nano]
+//   - strings ISO-8601 con offset, o sin offset (interpretados en location)
+//   - epoch en segundos (con decimales) o milisegundos, como número o string
+func ParseTimestamp(raw json.RawMessage, location *time.Location) (time.Time, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return time.Time{}, errors.New("timestamp is empty")
+	}
+
+	switch raw[0] {
+	case '[':
+		var arr []int
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp array: %w", err)
+		}
+		return ParseLocalDateTimeArray(arr, location)
+	case '"':
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp string: %w", err)
+		}
+		return parseTimestampString(value, location)
+	default:
+		return parseEpoch(string(raw))
+	}
+}
+
+// ParseLocalDateTimeArray convierte el array [year, month, day, hour, minute, second, nano] a time.Time en location
+func ParseLocalDateTimeArray(arr []int, location *time.Location) (time.Time, error) {
+	if len(arr) < 6 {
+		return time.Time{}, fmt.Errorf("invalid timestamp array, expected at least 6 elements, got %d", len(arr))
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	// Nano es opcional (puede ser el 7mo elemento)
+	nano := 0
+	if len(arr) >= 7 {
+		nano = arr[6]
+	}
+
+	return time.Date(arr[0], time.Month(arr[1]), arr[2], arr[3], arr[4], arr[5], nano, location), nil
+}
+
+// parseTimestampString interpreta un string ISO-8601 o un epoch serializado como string
+func parseTimestampString(value string, location *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, errors.New("timestamp is empty")
+	}
+
+	// ZonedDateTime de Java agrega la zona entre corchetes: 2024-01-02T03:04:05+01:00[Europe/Madrid]
+	if i := strings.IndexByte(value, '['); i > 0 && strings.HasSuffix(value, "]") {
+		value = value[:i]
+	}
+
+	for _, layout := range offsetTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := parseEpoch(value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported timestamp format %q", value)
+}
+
+// parseEpoch interpreta un epoch en segundos (con decimales, como Instant de Java) o en milisegundos
+func parseEpoch(value string) (time.Time, error) {
+	whole, fraction, hasFraction := strings.Cut(value, ".")
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unsupported timestamp format %q", value)
+	}
+
+	if !hasFraction {
+		if seconds >= epochMillisThreshold || seconds <= -epochMillisThreshold {
+			return time.UnixMilli(seconds).UTC(), nil
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	// Fracción de segundo con hasta 9 dígitos
+	if fraction == "" || len(fraction) > 9 {
+		return time.Time{}, fmt.Errorf("unsupported timestamp format %q", value)
+	}
+	nanos, err := strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unsupported timestamp format %q", value)
+	}
+	if strings.HasPrefix(whole, "-") {
+		nanos = -nanos
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
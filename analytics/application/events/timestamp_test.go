@@ -0,0 +1,154 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	lima := time.FixedZone("Lima", -5*60*60)
+
+	tests := []struct {
+		name     string
+		raw      string
+		location *time.Location
+		want     time.Time
+	}{
+		{"local date time array in UTC", `[2024, 3, 15, 10, 30, 45]`, nil, time.Date(2024, 3, 15, 10, 30, 45, 0, time.UTC)},
+		{"local date time array with nanos in zone", `[2024, 3, 15, 10, 30, 45, 123000000]`, lima, time.Date(2024, 3, 15, 15, 30, 45, 123000000, time.UTC)},
+		{"RFC3339 with offset", `"2024-03-15T10:30:45+02:00"`, lima, time.Date(2024, 3, 15, 8, 30, 45, 0, time.UTC)},
+		{"RFC3339 in UTC", `"2024-03-15T10:30:45.5Z"`, nil, time.Date(2024, 3, 15, 10, 30, 45, 500000000, time.UTC)},
+		{"offset without colon", `"2024-03-15T10:30:45-0500"`, nil, time.Date(2024, 3, 15, 15, 30, 45, 0, time.UTC)},
+		{"zoned date time suffix", `"2024-03-15T10:30:45+01:00[Europe/Madrid]"`, nil, time.Date(2024, 3, 15, 9, 30, 45, 0, time.UTC)},
+		{"local string in zone", `"2024-03-15T10:30:45"`, lima, time.Date(2024, 3, 15, 15, 30, 45, 0, time.UTC)},
+		{"date only", `"2024-03-15"`, nil, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"epoch seconds", `1710498645`, nil, time.Date(2024, 3, 15, 10, 30, 45, 0, time.UTC)},
+		{"epoch milliseconds", `1710498645123`, nil, time.Date(2024, 3, 15, 10, 30, 45, 123000000, time.UTC)},
+		{"fractional epoch seconds", `1710498645.25`, nil, time.Date(2024, 3, 15, 10, 30, 45, 250000000, time.UTC)},
+		{"epoch as string", `"1710498645"`, nil, time.Date(2024, 3, 15, 10, 30, 45, 0, time.UTC)},
+		{"negative epoch seconds", `-86400`, nil, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"negative fractional epoch", `-1.5`, nil, time.Date(1969, 12, 31, 23, 59, 58, 500000000, time.UTC)},
+		{"negative epoch milliseconds", `-100000000000`, nil, time.UnixMilli(-100000000000).UTC()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimestamp(json.RawMessage(tt.raw), tt.location)
+			if err != nil {
+				t.Fatalf("ParseTimestamp(%s): %v", tt.raw, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("ParseTimestamp(%s) = %s, want %s", tt.raw, got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimestampRejectsInvalidValues(t *testing.T) {
+	tests := []string{
+		``,
+		`null`,
+		`[2024, 3, 15]`,
+		`"not a date"`,
+		`"2024-03-15T10:30:45+01:00[Europe/Madrid"`,
+		`1710498645.1234567890`,
+		`1710498645.`,
+		`true`,
+	}
+
+	for _, raw := range tests {
+		if got, err := ParseTimestamp(json.RawMessage(raw), nil); err == nil {
+			t.Errorf("ParseTimestamp(%s) = %s, expected error", raw, got)
+		}
+	}
+}
+
+func TestTolerateSkew(t *testing.T) {
+	policy := TimestampPolicy{MaxClockSkew: 5 * time.Minute}
+	past := time.Date(2024, 3, 15, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		offset  time.Duration
+		clamped bool
+	}{
+		{"in the past", -time.Hour, false},
+		{"within skew", 4 * time.Minute, true},
+		{"beyond skew", 6 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := time.Now().Add(tt.offset)
+			got := policy.tolerateSkew(value)
+
+			if tt.clamped {
+				if got.After(time.Now()) {
+					t.Fatalf("expected %s to be clamped to now, got %s", value, got)
+				}
+				return
+			}
+			if !got.Equal(value) {
+				t.Fatalf("expected %s unchanged, got %s", value, got)
+			}
+		})
+	}
+
+	if got := (TimestampPolicy{}).tolerateSkew(past); !got.Equal(past) {
+		t.Fatalf("expected past date unchanged without skew, got %s", got)
+	}
+}
+
+func TestDecodeUserRegistrationOccurredDate(t *testing.T) {
+	messageTime := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	envelopeTime := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	policy := TimestampPolicy{MaxClockSkew: 5 * time.Minute}
+
+	tests := []struct {
+		name        string
+		occurredOn  string
+		occurredAt  *time.Time
+		messageTime time.Time
+		want        time.Time
+	}{
+		{"occurredOn in payload", `,"occurredOn":[2024,3,13,8,0,0]`, &envelopeTime, messageTime, time.Date(2024, 3, 13, 8, 0, 0, 0, time.UTC)},
+		{"envelope occurred_at", ``, &envelopeTime, messageTime, envelopeTime},
+		{"message time", ``, nil, messageTime, messageTime},
+		{"null occurredOn falls back to message time", `,"occurredOn":null`, nil, messageTime, messageTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope := &Envelope{
+				Type:          UserRegisteredEventType,
+				SchemaVersion: UserRegisteredSchemaVersion,
+				ID:            "event-1",
+				OccurredAt:    tt.occurredAt,
+				Payload: json.RawMessage(`{"userId":"6f1c2d3e-4b5a-4c6d-8e9f-0a1b2c3d4e5f",` +
+					`"profileId":"7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d","username":"ada"` + tt.occurredOn + `}`),
+			}
+
+			userReg, err := DecodeUserRegistration(envelope, tt.messageTime, policy)
+			if err != nil {
+				t.Fatalf("DecodeUserRegistration: %v", err)
+			}
+			if !userReg.RegisteredAt().Equal(tt.want) {
+				t.Fatalf("expected registered at %s, got %s", tt.want, userReg.RegisteredAt())
+			}
+		})
+	}
+}
+
+func TestDecodeUserRegistrationWithoutOccurredDate(t *testing.T) {
+	envelope := &Envelope{
+		Type:          UserRegisteredEventType,
+		SchemaVersion: UserRegisteredSchemaVersion,
+		Payload: json.RawMessage(`{"userId":"6f1c2d3e-4b5a-4c6d-8e9f-0a1b2c3d4e5f",` +
+			`"profileId":"7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d","username":"ada"}`),
+	}
+
+	if _, err := DecodeUserRegistration(envelope, time.Time{}, TimestampPolicy{}); err == nil {
+		t.Fatalf("expected error without occurredOn, occurred_at or message time")
+	}
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Upcaster migra un payload desde una versión de esquema a la siguiente
+type Upcaster func(payload map[string]interface{}) (map[string]interface{}, error)
+
+// UpcasterChain migra payloads de versiones anteriores hasta la versión actual de un tipo de evento
+type UpcasterChain struct {
+	eventType      string
+	currentVersion int
+	upcasters      map[int]Upcaster // versión origen -> upcaster hacia versión origen+1
+}
+
+// NewUpcasterChain crea una cadena vacía para un tipo de evento
+func NewUpcasterChain(eventType string, currentVersion int) *UpcasterChain {
+	return &UpcasterChain{
+		eventType:      eventType,
+		currentVersion: currentVersion,
+		upcasters:      make(map[int]Upcaster),
+	}
+}
+
+// Register agrega el upcaster que migra desde fromVersion hacia fromVersion+1
+func (c *UpcasterChain) Register(fromVersion int, upcaster Upcaster) *UpcasterChain {
+	c.upcasters[fromVersion] = upcaster
+	return c
+}
+
+// CurrentVersion retorna la versión de esquema que entiende el mapeo a dominio
+func (c *UpcasterChain) CurrentVersion() int {
+	return c.currentVersion
+}
+
+// Upcast migra el payload desde version hasta la versión actual.
+// Las versiones más nuevas que la actual no pueden interpretarse y se rechazan.
+func (c *UpcasterChain) Upcast(version int, payload json.RawMessage) (json.RawMessage, error) {
+	if version > c.currentVersion {
+		return nil, fmt.Errorf("unsupported schema version %d for %s (current version is %d)",
+			version, c.eventType, c.currentVersion)
+	}
+	if version == c.currentVersion {
+		return payload, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("error unmarshaling %s v%d payload: %w", c.eventType, version, err)
+	}
+
+	for v := version; v < c.currentVersion; v++ {
+		upcaster, ok := c.upcasters[v]
+		if !ok {
+			return nil, fmt.Errorf("no upcaster registered for %s from v%d to v%d", c.eventType, v, v+1)
+		}
+
+		var err error
+		fields, err = upcaster(fields)
+		if err != nil {
+			return nil, fmt.Errorf("error upcasting %s from v%d to v%d: %w", c.eventType, v, v+1, err)
+		}
+	}
+
+	upcasted, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling upcasted %s payload: %w", c.eventType, err)
+	}
+	return upcasted, nil
+}
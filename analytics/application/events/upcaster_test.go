@@ -0,0 +1,100 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// renameField crea un upcaster que renombra un campo del payload
+func renameField(from, to string) Upcaster {
+	return func(payload map[string]interface{}) (map[string]interface{}, error) {
+		payload[to] = payload[from]
+		delete(payload, from)
+		return payload, nil
+	}
+}
+
+func TestUpcasterChainUpcast(t *testing.T) {
+	tests := []struct {
+		name    string
+		chain   *UpcasterChain
+		version int
+		payload string
+		want    string
+		err     string
+	}{
+		{
+			name:    "current version is returned unchanged",
+			chain:   NewUpcasterChain("user.registered", 1),
+			version: 1,
+			payload: `{"user_id":"u1"}`,
+			want:    `{"user_id":"u1"}`,
+		},
+		{
+			name:    "v0 to v1 upcaster is applied",
+			chain:   NewUpcasterChain("user.registered", 1).Register(0, renameField("user_id", "userId")),
+			version: 0,
+			payload: `{"user_id":"u1"}`,
+			want:    `{"userId":"u1"}`,
+		},
+		{
+			name: "upcasters are chained up to the current version",
+			chain: NewUpcasterChain("user.registered", 3).
+				Register(1, renameField("user_id", "userId")).
+				Register(2, renameField("userId", "id")),
+			version: 1,
+			payload: `{"user_id":"u1"}`,
+			want:    `{"id":"u1"}`,
+		},
+		{
+			name:    "future version is rejected",
+			chain:   NewUpcasterChain("user.registered", 1),
+			version: 2,
+			payload: `{"userId":"u1"}`,
+			err:     "unsupported schema version 2",
+		},
+		{
+			name:    "missing upcaster is rejected",
+			chain:   NewUpcasterChain("user.registered", 3).Register(1, renameField("user_id", "userId")),
+			version: 1,
+			payload: `{"user_id":"u1"}`,
+			err:     "no upcaster registered for user.registered from v2 to v3",
+		},
+		{
+			name: "upcaster error is returned",
+			chain: NewUpcasterChain("user.registered", 1).Register(0, func(map[string]interface{}) (map[string]interface{}, error) {
+				return nil, errors.New("missing user_id")
+			}),
+			version: 0,
+			payload: `{}`,
+			err:     "missing user_id",
+		},
+		{
+			name:    "invalid legacy payload is rejected",
+			chain:   NewUpcasterChain("user.registered", 1).Register(0, renameField("user_id", "userId")),
+			version: 0,
+			payload: `[1, 2]`,
+			err:     "error unmarshaling user.registered v0 payload",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.chain.Upcast(tt.version, json.RawMessage(tt.payload))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Upcast: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
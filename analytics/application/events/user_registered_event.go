@@ -0,0 +1,107 @@
+package events
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// UserRegisteredEventType es el tipo de los eventos de registro de usuarios
+const UserRegisteredEventType = "iam.user.registered"
+
+// UserRegisteredSchemaVersion es la versión de esquema que entiende UserRegisteredEvent
+const UserRegisteredSchemaVersion = 1
+
+// userRegisteredUpcasters migra payloads antiguos a UserRegisteredSchemaVersion
+var userRegisteredUpcasters = NewUpcasterChain(UserRegisteredEventType, UserRegisteredSchemaVersion)
+
+// UserRegisteredEvent representa el payload del evento de registro de usuarios
+type UserRegisteredEvent struct {
+	UserID     string          `json:"userId"`
+	ProfileID  string          `json:"profileId"`
+	Username   string          `json:"username"`
+	ProfileURL *string         `json:"profileUrl"`
+	OccurredOn json.RawMessage `json:"occurredOn"` // Array de LocalDateTime, ISO-8601 o epoch (ver ParseTimestamp)
+}
+
+// DecodeUserRegistration migra el payload del envelope a la versión actual y lo convierte a dominio.
+// El id del envelope (o del CloudEvent) se asigna al aggregate. Si el payload no trae occurredOn, la fecha
+// de registro es el occurred_at del envelope o, en su defecto, messageTime (el timestamp del mensaje de Kafka).
+func DecodeUserRegistration(envelope *Envelope, messageTime time.Time, policy TimestampPolicy) (*aggregates.UserRegistrationAnalytics, error) {
+	payload, err := userRegisteredUpcasters.Upcast(envelope.SchemaVersion, envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var event UserRegisteredEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("error unmarshaling user registration event: %w", err)
+	}
+
+	log.Printf("Processing user registration event v%d: UserID=%s, Username=%s, ProfileID=%s",
+		envelope.SchemaVersion, event.UserID, event.Username, event.ProfileID)
+
+	fallbackOccurredAt := envelope.OccurredAt
+	if fallbackOccurredAt == nil && !messageTime.IsZero() {
+		fallbackOccurredAt = &messageTime
+	}
+
+	userReg, err := event.ToDomain(fallbackOccurredAt, policy)
+	if err != nil {
+		return nil, fmt.Errorf("error converting user registration event to domain: %w", err)
+	}
+	userReg.SetEventID(envelope.ID)
+
+	return userReg, nil
+}
+
+// ToDomain convierte el evento a un aggregate de dominio.
+// fallbackOccurredAt se usa como fecha de registro si el payload no trae occurredOn.
+// Las fechas apenas en el futuro (dentro de policy.MaxClockSkew) se llevan al instante actual.
+func (e *UserRegisteredEvent) ToDomain(fallbackOccurredAt *time.Time, policy TimestampPolicy) (*aggregates.UserRegistrationAnalytics, error) {
+	// Crear value objects
+	userID, err := valueobjects.NewUserID(e.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	profileID, err := valueobjects.NewProfileID(e.ProfileID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile ID: %w", err)
+	}
+
+	var registeredAt time.Time
+	if !e.HasOccurredOn() && fallbackOccurredAt != nil {
+		registeredAt = *fallbackOccurredAt
+	} else {
+		registeredAt, err = ParseTimestamp(e.OccurredOn, policy.Location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid occurred date: %w", err)
+		}
+	}
+	registeredAt = policy.tolerateSkew(registeredAt).UTC()
+
+	// Crear aggregate
+	userReg, err := aggregates.NewUserRegistrationAnalytics(
+		userID,
+		profileID,
+		e.Username,
+		e.ProfileURL,
+		registeredAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating user registration analytics aggregate: %w", err)
+	}
+
+	return userReg, nil
+}
+
+// HasOccurredOn indica si el payload trae la fecha del evento
+func (e *UserRegisteredEvent) HasOccurredOn() bool {
+	raw := bytes.TrimSpace(e.OccurredOn)
+	return len(raw) > 0 && !bytes.Equal(raw, []byte("null"))
+}
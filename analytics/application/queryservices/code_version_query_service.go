@@ -0,0 +1,154 @@
+package queryservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"time"
+)
+
+// Tendencias de un estudiante entre versiones de código
+const (
+	VersionTrendProductive       = "productive"        // Las versiones mejoran los tests aprobados más veces de las que empeoran
+	VersionTrendThrashing        = "thrashing"         // Los retrocesos igualan o superan a las mejoras
+	VersionTrendStalled          = "stalled"           // Los tests aprobados no cambian entre versiones
+	VersionTrendInsufficientData = "insufficient_data" // Menos de dos versiones ejecutadas
+)
+
+// CodeVersionTimelineEntry es una versión del timeline con su última ejecución y la variación respecto
+// de la versión ejecutada anterior
+type CodeVersionTimelineEntry struct {
+	CodeVersionID        string     `json:"code_version_id"`
+	VersionNumber        int        `json:"version_number"` // 0 si no se recibió el evento de la versión
+	ParentVersionID      string     `json:"parent_version_id,omitempty"`
+	LinesChanged         int        `json:"lines_changed"`
+	CreatedAt            *time.Time `json:"created_at,omitempty"`
+	Executions           int64      `json:"executions"`
+	LastExecutedAt       *time.Time `json:"last_executed_at,omitempty"`
+	PassedTests          *int       `json:"passed_tests,omitempty"` // Campos de la última ejecución, nil sin ejecuciones
+	TotalTests           *int       `json:"total_tests,omitempty"`
+	ExitCode             *int       `json:"exit_code,omitempty"`
+	ExecutionTimeMs      *int64     `json:"execution_time_ms,omitempty"`
+	Success              *bool      `json:"success,omitempty"`
+	PassedTestsDelta     *int       `json:"passed_tests_delta,omitempty"`
+	ExecutionTimeDeltaMs *int64     `json:"execution_time_delta_ms,omitempty"`
+}
+
+// CodeVersionTimelineSummary resume cómo evoluciona el estudiante entre versiones ejecutadas
+type CodeVersionTimelineSummary struct {
+	Versions            int    `json:"versions"`
+	ExecutedVersions    int    `json:"executed_versions"`
+	Improvements        int    `json:"improvements"`
+	Regressions         int    `json:"regressions"`
+	Unchanged           int    `json:"unchanged"`
+	BestPassedTests     int    `json:"best_passed_tests"`
+	FirstPassingVersion *int   `json:"first_passing_version,omitempty"` // Posición (desde 1) de la primera versión exitosa
+	Trend               string `json:"trend"`
+}
+
+// CodeVersionTimeline es la evolución de las versiones de un estudiante en un challenge
+type CodeVersionTimeline struct {
+	StudentID   string                     `json:"student_id"`
+	ChallengeID string                     `json:"challenge_id"`
+	Versions    []CodeVersionTimelineEntry `json:"versions"`
+	Summary     CodeVersionTimelineSummary `json:"summary"`
+}
+
+// CodeVersionQueryService maneja consultas sobre las versiones de código
+type CodeVersionQueryService struct {
+	repository repositories.CodeVersionRepository
+}
+
+// NewCodeVersionQueryService crea una nueva instancia del servicio
+func NewCodeVersionQueryService(repository repositories.CodeVersionRepository) *CodeVersionQueryService {
+	return &CodeVersionQueryService{
+		repository: repository,
+	}
+}
+
+// GetTimeline obtiene el timeline de versiones de un estudiante en un challenge
+func (s *CodeVersionQueryService) GetTimeline(ctx context.Context, studentID valueobjects.StudentID, challengeID valueobjects.ChallengeID) (*CodeVersionTimeline, error) {
+	entries, err := s.repository.GetTimeline(ctx, studentID, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &CodeVersionTimeline{
+		StudentID:   studentID.Value(),
+		ChallengeID: challengeID.Value(),
+		Versions:    make([]CodeVersionTimelineEntry, 0, len(entries)),
+		Summary:     CodeVersionTimelineSummary{Versions: len(entries)},
+	}
+
+	var previous *repositories.CodeVersionTimelineEntry
+	for i := range entries {
+		entry := entries[i]
+		response := CodeVersionTimelineEntry{
+			CodeVersionID:   entry.CodeVersionID,
+			VersionNumber:   entry.VersionNumber,
+			ParentVersionID: entry.ParentVersionID,
+			LinesChanged:    entry.LinesChanged,
+			CreatedAt:       entry.CreatedAt,
+			Executions:      entry.Executions,
+		}
+
+		if entry.Executions > 0 {
+			response.LastExecutedAt = entry.LastExecutedAt
+			response.PassedTests = &entry.PassedTests
+			response.TotalTests = &entry.TotalTests
+			response.ExitCode = &entry.ExitCode
+			response.ExecutionTimeMs = &entry.ExecutionTimeMs
+			response.Success = &entry.Success
+			summarizeVersion(&timeline.Summary, &response, &entry, previous, i+1)
+			previous = &entries[i]
+		}
+
+		timeline.Versions = append(timeline.Versions, response)
+	}
+
+	timeline.Summary.Trend = versionTrend(timeline.Summary)
+	return timeline, nil
+}
+
+// summarizeVersion compara una versión ejecutada con la anterior y la acumula en el resumen
+func summarizeVersion(summary *CodeVersionTimelineSummary, response *CodeVersionTimelineEntry, entry, previous *repositories.CodeVersionTimelineEntry, position int) {
+	summary.ExecutedVersions++
+	if entry.PassedTests > summary.BestPassedTests {
+		summary.BestPassedTests = entry.PassedTests
+	}
+	if entry.Success && summary.FirstPassingVersion == nil {
+		summary.FirstPassingVersion = &position
+	}
+
+	if previous == nil {
+		return
+	}
+
+	passedDelta := entry.PassedTests - previous.PassedTests
+	timeDelta := entry.ExecutionTimeMs - previous.ExecutionTimeMs
+	response.PassedTestsDelta = &passedDelta
+	response.ExecutionTimeDeltaMs = &timeDelta
+
+	switch {
+	case passedDelta > 0:
+		summary.Improvements++
+	case passedDelta < 0:
+		summary.Regressions++
+	default:
+		summary.Unchanged++
+	}
+}
+
+// versionTrend clasifica la evolución del estudiante a partir del resumen
+func versionTrend(summary CodeVersionTimelineSummary) string {
+	switch {
+	case summary.ExecutedVersions < 2:
+		return VersionTrendInsufficientData
+	case summary.Improvements == 0 && summary.Regressions == 0:
+		return VersionTrendStalled
+	case summary.Improvements > summary.Regressions:
+		return VersionTrendProductive
+	default:
+		return VersionTrendThrashing
+	}
+}
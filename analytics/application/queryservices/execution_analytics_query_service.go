@@ -11,13 +11,15 @@ import (
 
 // ExecutionAnalyticsQueryService maneja consultas para ExecutionAnalytics
 type ExecutionAnalyticsQueryService struct {
-	repository repositories.ExecutionAnalyticsRepository
+	repository          repositories.ExecutionAnalyticsRepository
+	challengeRepository repositories.ChallengeRepository
 }
 
 // NewExecutionAnalyticsQueryService crea una nueva instancia del servicio
-func NewExecutionAnalyticsQueryService(repository repositories.ExecutionAnalyticsRepository) *ExecutionAnalyticsQueryService {
+func NewExecutionAnalyticsQueryService(repository repositories.ExecutionAnalyticsRepository, challengeRepository repositories.ChallengeRepository) *ExecutionAnalyticsQueryService {
 	return &ExecutionAnalyticsQueryService{
-		repository: repository,
+		repository:          repository,
+		challengeRepository: challengeRepository,
 	}
 }
 
@@ -31,6 +33,27 @@ func (s *ExecutionAnalyticsQueryService) GetByExecutionID(ctx context.Context, e
 	return s.repository.FindByExecutionID(ctx, id)
 }
 
+// GetExecutionHistory obtiene la versión vigente de una ejecución y las versiones que reemplazó.
+// Retorna nil si la ejecución no existe.
+func (s *ExecutionAnalyticsQueryService) GetExecutionHistory(ctx context.Context, executionID string) (*aggregates.ExecutionAnalytics, []repositories.ExecutionRevision, error) {
+	id, err := valueobjects.NewExecutionID(executionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid execution ID: %w", err)
+	}
+
+	execution, err := s.repository.FindByExecutionID(ctx, id)
+	if err != nil || execution == nil {
+		return nil, nil, err
+	}
+
+	history, err := s.repository.FindHistoryByExecutionID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return execution, history, nil
+}
+
 // GetByStudentID obtiene todas las ejecuciones de un estudiante
 func (s *ExecutionAnalyticsQueryService) GetByStudentID(ctx context.Context, studentID string, page, pageSize int) ([]*aggregates.ExecutionAnalytics, error) {
 	id, err := valueobjects.NewStudentID(studentID)
@@ -69,6 +92,56 @@ func (s *ExecutionAnalyticsQueryService) GetStudentSuccessRate(ctx context.Conte
 	return s.repository.GetSuccessRateByStudent(ctx, id)
 }
 
+// GetStudentOutcomes cuenta las ejecuciones de un estudiante por resultado
+func (s *ExecutionAnalyticsQueryService) GetStudentOutcomes(ctx context.Context, studentID string) (repositories.OutcomeBreakdown, error) {
+	id, err := valueobjects.NewStudentID(studentID)
+	if err != nil {
+		return repositories.OutcomeBreakdown{}, fmt.Errorf("invalid student ID: %w", err)
+	}
+
+	return s.repository.GetOutcomeBreakdownByStudent(ctx, id)
+}
+
+// GetChallengeOutcomes cuenta las ejecuciones de un challenge por resultado
+func (s *ExecutionAnalyticsQueryService) GetChallengeOutcomes(ctx context.Context, challengeID string) (repositories.OutcomeBreakdown, error) {
+	id, err := valueobjects.NewChallengeID(challengeID)
+	if err != nil {
+		return repositories.OutcomeBreakdown{}, fmt.Errorf("invalid challenge ID: %w", err)
+	}
+
+	return s.repository.GetOutcomeBreakdownByChallenge(ctx, id)
+}
+
+// GetStudentAverageScore obtiene el puntaje ponderado promedio de un estudiante
+func (s *ExecutionAnalyticsQueryService) GetStudentAverageScore(ctx context.Context, studentID string) (float64, error) {
+	id, err := valueobjects.NewStudentID(studentID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid student ID: %w", err)
+	}
+
+	return s.repository.GetAverageScoreByStudent(ctx, id)
+}
+
+// GetChallengeAverageScore obtiene el puntaje ponderado promedio de un challenge
+func (s *ExecutionAnalyticsQueryService) GetChallengeAverageScore(ctx context.Context, challengeID string) (float64, error) {
+	id, err := valueobjects.NewChallengeID(challengeID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid challenge ID: %w", err)
+	}
+
+	return s.repository.GetAverageScoreByChallenge(ctx, id)
+}
+
+// GetChallengeTestStats obtiene las estadísticas de cada test de un challenge
+func (s *ExecutionAnalyticsQueryService) GetChallengeTestStats(ctx context.Context, challengeID string) ([]repositories.TestStats, error) {
+	id, err := valueobjects.NewChallengeID(challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid challenge ID: %w", err)
+	}
+
+	return s.repository.GetTestStatsByChallenge(ctx, id)
+}
+
 // GetChallengeSuccessRate obtiene la tasa de éxito de un challenge
 func (s *ExecutionAnalyticsQueryService) GetChallengeSuccessRate(ctx context.Context, challengeID string) (float64, error) {
 	id, err := valueobjects.NewChallengeID(challengeID)
@@ -89,19 +162,39 @@ func (s *ExecutionAnalyticsQueryService) GetChallengeAverageExecutionTime(ctx co
 	return s.repository.GetAverageExecutionTimeByChallenge(ctx, id)
 }
 
+// GetChallenge obtiene un challenge del catálogo. Retorna nil si aún no se recibió ningún evento del challenge.
+func (s *ExecutionAnalyticsQueryService) GetChallenge(ctx context.Context, challengeID string) (*aggregates.Challenge, error) {
+	id, err := valueobjects.NewChallengeID(challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid challenge ID: %w", err)
+	}
+
+	return s.challengeRepository.FindByChallengeID(ctx, id)
+}
+
 // GetDailyStats obtiene estadísticas diarias
-func (s *ExecutionAnalyticsQueryService) GetDailyStats(ctx context.Context, startDate, endDate time.Time) ([]repositories.DailyStats, error) {
-	return s.repository.GetDailyExecutionStats(ctx, startDate, endDate)
+func (s *ExecutionAnalyticsQueryService) GetDailyStats(ctx context.Context, startDate, endDate time.Time, filter repositories.ChallengeFilter) ([]repositories.DailyStats, error) {
+	return s.repository.GetDailyExecutionStats(ctx, startDate, endDate, filter)
 }
 
 // GetLanguageStats obtiene estadísticas por lenguaje
-func (s *ExecutionAnalyticsQueryService) GetLanguageStats(ctx context.Context, startDate, endDate time.Time) ([]repositories.LanguageStats, error) {
-	return s.repository.GetLanguageUsageStats(ctx, startDate, endDate)
+func (s *ExecutionAnalyticsQueryService) GetLanguageStats(ctx context.Context, startDate, endDate time.Time, filter repositories.ChallengeFilter) ([]repositories.LanguageStats, error) {
+	return s.repository.GetLanguageUsageStats(ctx, startDate, endDate, filter)
+}
+
+// GetUnrecognizedLanguageStats obtiene los lenguajes recibidos que no están en el registro de lenguajes
+func (s *ExecutionAnalyticsQueryService) GetUnrecognizedLanguageStats(ctx context.Context, startDate, endDate time.Time) ([]repositories.UnrecognizedLanguageStats, error) {
+	return s.repository.GetUnrecognizedLanguageStats(ctx, startDate, endDate)
+}
+
+// GetDifficultyStats obtiene estadísticas por dificultad de challenge
+func (s *ExecutionAnalyticsQueryService) GetDifficultyStats(ctx context.Context, startDate, endDate time.Time, filter repositories.ChallengeFilter) ([]repositories.DifficultyStats, error) {
+	return s.repository.GetDifficultyStats(ctx, startDate, endDate, filter)
 }
 
 // GetTopFailedChallenges obtiene los challenges con más fallos
-func (s *ExecutionAnalyticsQueryService) GetTopFailedChallenges(ctx context.Context, limit int) ([]repositories.ChallengeStats, error) {
-	return s.repository.GetTopFailedChallenges(ctx, limit)
+func (s *ExecutionAnalyticsQueryService) GetTopFailedChallenges(ctx context.Context, limit int, filter repositories.ChallengeFilter) ([]repositories.ChallengeStats, error) {
+	return s.repository.GetTopFailedChallenges(ctx, limit, filter)
 }
 
 // GetStudentExecutionCount obtiene el conteo de ejecuciones de un estudiante
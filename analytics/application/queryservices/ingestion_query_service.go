@@ -0,0 +1,117 @@
+package queryservices
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Estados de la ingesta
+const (
+	IngestionStatusUp      = "UP"
+	IngestionStatusLagging = "LAGGING"
+)
+
+// PartitionIngestionStatus es el estado de ingesta de una partición
+type PartitionIngestionStatus struct {
+	Partition          int32      `json:"partition"`
+	Paused             bool       `json:"paused"`
+	CommittedOffset    int64      `json:"committed_offset"` // -1 si el grupo no ha confirmado offsets
+	HighWaterMark      int64      `json:"high_water_mark"`
+	Lag                int64      `json:"lag"`
+	LastProcessedAt    *time.Time `json:"last_processed_at,omitempty"`
+	LastEventTimestamp *time.Time `json:"last_event_timestamp,omitempty"`
+	MessagesPerSecond  float64    `json:"messages_per_second"`
+	ProcessedCount     int64      `json:"processed_count"`
+	ErrorCount         int64      `json:"error_count"`
+}
+
+// TopicIngestionStatus es el estado de ingesta de un tópico
+type TopicIngestionStatus struct {
+	Topic             string                     `json:"topic"`
+	Consumer          string                     `json:"consumer"`
+	Lag               int64                      `json:"lag"`
+	MessagesPerSecond float64                    `json:"messages_per_second"`
+	ProcessedCount    int64                      `json:"processed_count"`
+	ErrorCount        int64                      `json:"error_count"`
+	Partitions        []PartitionIngestionStatus `json:"partitions"`
+}
+
+// IngestionStatus es el estado de ingesta del consumer group
+type IngestionStatus struct {
+	Status     string                 `json:"status"`
+	GroupID    string                 `json:"group_id"`
+	TotalLag   int64                  `json:"total_lag"`
+	Topics     []TopicIngestionStatus `json:"topics"`
+	ObservedAt time.Time              `json:"observed_at"`
+}
+
+// ConsumerGroupMember es un miembro del consumer group con las particiones que tiene asignadas
+type ConsumerGroupMember struct {
+	MemberID    string             `json:"member_id"`
+	ClientID    string             `json:"client_id"`
+	ClientHost  string             `json:"client_host"`
+	Local       bool               `json:"local"` // Miembro de esta instancia
+	Assignments map[string][]int32 `json:"assignments"`
+}
+
+// ConsumerGroupStatus es la membresía del consumer group y el estado de control de esta instancia
+type ConsumerGroupStatus struct {
+	GroupID       string                `json:"group_id"`
+	State         string                `json:"state"`
+	Protocol      string                `json:"protocol"`
+	Members       []ConsumerGroupMember `json:"members"`
+	Paused        map[string][]int32    `json:"paused"`         // Particiones pausadas en esta instancia
+	PendingResets map[string][]int32    `json:"pending_resets"` // Particiones con un reseteo de offset por aplicar
+	ObservedAt    time.Time             `json:"observed_at"`
+}
+
+// IngestionStatusProvider obtiene los offsets y métricas de ingesta del broker y del consumidor
+type IngestionStatusProvider interface {
+	IngestionStatus(ctx context.Context) (*IngestionStatus, error)
+	ConsumerGroup(ctx context.Context) (*ConsumerGroupStatus, error)
+}
+
+// IngestionQueryService maneja consultas sobre el estado de la ingesta de eventos
+type IngestionQueryService struct {
+	provider     IngestionStatusProvider
+	lagThreshold int64
+}
+
+// NewIngestionQueryService crea una nueva instancia del servicio.
+// lagThreshold es el lag total a partir del cual la ingesta se reporta como LAGGING.
+func NewIngestionQueryService(provider IngestionStatusProvider, lagThreshold int64) *IngestionQueryService {
+	return &IngestionQueryService{
+		provider:     provider,
+		lagThreshold: lagThreshold,
+	}
+}
+
+// GetIngestionStatus obtiene el estado de ingesta por tópico y partición
+func (s *IngestionQueryService) GetIngestionStatus(ctx context.Context) (*IngestionStatus, error) {
+	status, err := s.provider.IngestionStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting ingestion status: %w", err)
+	}
+
+	status.TotalLag = 0
+	for _, topic := range status.Topics {
+		status.TotalLag += topic.Lag
+	}
+
+	status.Status = IngestionStatusUp
+	if status.TotalLag > s.lagThreshold {
+		status.Status = IngestionStatusLagging
+	}
+
+	return status, nil
+}
+
+// GetConsumerGroup obtiene los miembros del consumer group y sus particiones asignadas
+func (s *IngestionQueryService) GetConsumerGroup(ctx context.Context) (*ConsumerGroupStatus, error) {
+	group, err := s.provider.ConsumerGroup(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting consumer group: %w", err)
+	}
+	return group, nil
+}
@@ -0,0 +1,56 @@
+package queryservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"time"
+)
+
+// Estados del outbox
+const (
+	OutboxStatusUp       = "UP"
+	OutboxStatusDegraded = "DEGRADED" // Hay eventos apartados que no se publicarán sin intervención
+)
+
+// OutboxStatus es el estado del outbox de eventos de dominio
+type OutboxStatus struct {
+	Status          string     `json:"status"`
+	Pending         int64      `json:"pending"`
+	Failed          int64      `json:"failed"` // Eventos apartados por agotar los intentos de publicación
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+	OldestFailedAt  *time.Time `json:"oldest_failed_at,omitempty"`
+	ObservedAt      time.Time  `json:"observed_at"`
+}
+
+// OutboxQueryService maneja las consultas del estado del outbox
+type OutboxQueryService struct {
+	repository repositories.OutboxRepository
+}
+
+// NewOutboxQueryService crea una nueva instancia del servicio
+func NewOutboxQueryService(repository repositories.OutboxRepository) *OutboxQueryService {
+	return &OutboxQueryService{
+		repository: repository,
+	}
+}
+
+// GetStatus obtiene la cantidad de eventos pendientes y apartados como fallidos
+func (s *OutboxQueryService) GetStatus(ctx context.Context) (*OutboxStatus, error) {
+	stats, err := s.repository.GetStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &OutboxStatus{
+		Status:          OutboxStatusUp,
+		Pending:         stats.Pending,
+		Failed:          stats.Failed,
+		OldestPendingAt: stats.OldestPendingAt,
+		OldestFailedAt:  stats.OldestFailedAt,
+		ObservedAt:      time.Now(),
+	}
+	if stats.Failed > 0 {
+		status.Status = OutboxStatusDegraded
+	}
+	return status, nil
+}
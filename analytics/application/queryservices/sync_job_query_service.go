@@ -0,0 +1,38 @@
+package queryservices
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+)
+
+// SyncJobQueryService maneja las consultas de jobs de sincronización
+type SyncJobQueryService struct {
+	repository     repositories.SyncJobRepository
+	reconciliation repositories.ReconciliationRepository
+}
+
+// NewSyncJobQueryService crea una nueva instancia del servicio
+func NewSyncJobQueryService(repository repositories.SyncJobRepository, reconciliation repositories.ReconciliationRepository) *SyncJobQueryService {
+	return &SyncJobQueryService{
+		repository:     repository,
+		reconciliation: reconciliation,
+	}
+}
+
+// GetJob obtiene un job por ID
+func (s *SyncJobQueryService) GetJob(ctx context.Context, id string) (*aggregates.SyncJob, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+// GetRecentJobs obtiene los últimos jobs creados
+func (s *SyncJobQueryService) GetRecentJobs(ctx context.Context, limit int) ([]*aggregates.SyncJob, error) {
+	return s.repository.FindRecent(ctx, limit)
+}
+
+// GetReport obtiene las diferencias encontradas por un job, opcionalmente filtradas por tipo
+func (s *SyncJobQueryService) GetReport(ctx context.Context, id string, kind *valueobjects.ReconciliationKind, limit, offset int) ([]*entities.ReconciliationItem, error) {
+	return s.reconciliation.FindItems(ctx, id, kind, limit, offset)
+}
@@ -0,0 +1,162 @@
+package aggregates
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Challenge es el aggregate root que representa un challenge del catálogo (dimensión de los KPIs).
+// Se materializa a partir de los eventos de creación, actualización y archivado del servicio de challenges.
+type Challenge struct {
+	challengeID valueobjects.ChallengeID
+	eventID     string
+	change      valueobjects.ChallengeChange // Cambio que informa el evento de origen
+	title       string
+	difficulty  string // Normalizada en minúsculas (easy, medium, hard...)
+	tags        []string
+	courseID    string
+	testCount   int
+	archived    bool
+	occurredAt  time.Time // Fecha del evento: versiona los cambios para descartar eventos desordenados
+}
+
+// NewChallenge crea un challenge a partir de un cambio del catálogo. Los eventos de archivado
+// pueden no traer los atributos del challenge.
+func NewChallenge(
+	challengeID valueobjects.ChallengeID,
+	change valueobjects.ChallengeChange,
+	title string,
+	difficulty string,
+	tags []string,
+	courseID string,
+	testCount int,
+	occurredAt time.Time,
+) (*Challenge, error) {
+	title = strings.TrimSpace(title)
+	if title == "" && change != valueobjects.ChallengeArchived {
+		return nil, errors.New("challenge title cannot be empty")
+	}
+
+	if testCount < 0 {
+		return nil, errors.New("challenge test count cannot be negative")
+	}
+
+	if occurredAt.IsZero() {
+		return nil, errors.New("challenge event date cannot be empty")
+	}
+
+	return &Challenge{
+		challengeID: challengeID,
+		change:      change,
+		title:       title,
+		difficulty:  strings.ToLower(strings.TrimSpace(difficulty)),
+		tags:        normalizeTags(tags),
+		courseID:    strings.TrimSpace(courseID),
+		testCount:   testCount,
+		archived:    change == valueobjects.ChallengeArchived,
+		occurredAt:  occurredAt,
+	}, nil
+}
+
+// RestoreChallenge reconstruye un challenge guardado
+func RestoreChallenge(
+	challengeID valueobjects.ChallengeID,
+	eventID string,
+	title string,
+	difficulty string,
+	tags []string,
+	courseID string,
+	testCount int,
+	archived bool,
+	occurredAt time.Time,
+) *Challenge {
+	change := valueobjects.ChallengeUpdated
+	if archived {
+		change = valueobjects.ChallengeArchived
+	}
+	return &Challenge{
+		challengeID: challengeID,
+		eventID:     eventID,
+		change:      change,
+		title:       title,
+		difficulty:  difficulty,
+		tags:        tags,
+		courseID:    courseID,
+		testCount:   testCount,
+		archived:    archived,
+		occurredAt:  occurredAt,
+	}
+}
+
+// normalizeTags pasa las etiquetas a minúsculas y elimina las vacías y repetidas
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// ChangesAttributes indica si el cambio trae los atributos del challenge (título, dificultad, etiquetas...)
+func (c *Challenge) ChangesAttributes() bool {
+	return c.change != valueobjects.ChallengeArchived || c.title != ""
+}
+
+// ChangesStatus indica si el cambio modifica el estado del challenge (activo o archivado)
+func (c *Challenge) ChangesStatus() bool {
+	return c.change != valueobjects.ChallengeUpdated
+}
+
+// SetEventID asocia el aggregate con el ID del evento que lo originó
+func (c *Challenge) SetEventID(eventID string) {
+	c.eventID = eventID
+}
+
+// Getters
+func (c *Challenge) ChallengeID() valueobjects.ChallengeID {
+	return c.challengeID
+}
+
+func (c *Challenge) EventID() string {
+	return c.eventID
+}
+
+func (c *Challenge) Change() valueobjects.ChallengeChange {
+	return c.change
+}
+
+func (c *Challenge) Title() string {
+	return c.title
+}
+
+func (c *Challenge) Difficulty() string {
+	return c.difficulty
+}
+
+func (c *Challenge) Tags() []string {
+	return c.tags
+}
+
+func (c *Challenge) CourseID() string {
+	return c.courseID
+}
+
+func (c *Challenge) TestCount() int {
+	return c.testCount
+}
+
+func (c *Challenge) Archived() bool {
+	return c.archived
+}
+
+func (c *Challenge) OccurredAt() time.Time {
+	return c.occurredAt
+}
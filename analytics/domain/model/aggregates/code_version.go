@@ -0,0 +1,95 @@
+package aggregates
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"errors"
+	"time"
+)
+
+// CodeVersion es el aggregate root que representa una versión del código de un estudiante en un challenge.
+// Las ejecuciones referencian la versión que ejecutaron por su ID.
+type CodeVersion struct {
+	codeVersionID   valueobjects.CodeVersionID
+	eventID         string
+	challengeID     valueobjects.ChallengeID
+	studentID       valueobjects.StudentID
+	versionNumber   int
+	parentVersionID *valueobjects.CodeVersionID // nil = primera versión
+	linesChanged    int                         // Líneas modificadas respecto de la versión padre
+	createdAt       time.Time
+}
+
+// NewCodeVersion crea una nueva versión de código
+func NewCodeVersion(
+	codeVersionID valueobjects.CodeVersionID,
+	challengeID valueobjects.ChallengeID,
+	studentID valueobjects.StudentID,
+	versionNumber int,
+	parentVersionID *valueobjects.CodeVersionID,
+	linesChanged int,
+	createdAt time.Time,
+) (*CodeVersion, error) {
+	if versionNumber < 1 {
+		return nil, errors.New("code version number must be at least 1")
+	}
+
+	if parentVersionID != nil && parentVersionID.Equals(codeVersionID) {
+		return nil, errors.New("code version cannot be its own parent")
+	}
+
+	if linesChanged < 0 {
+		return nil, errors.New("lines changed cannot be negative")
+	}
+
+	if createdAt.IsZero() {
+		return nil, errors.New("code version date cannot be empty")
+	}
+
+	return &CodeVersion{
+		codeVersionID:   codeVersionID,
+		challengeID:     challengeID,
+		studentID:       studentID,
+		versionNumber:   versionNumber,
+		parentVersionID: parentVersionID,
+		linesChanged:    linesChanged,
+		createdAt:       createdAt,
+	}, nil
+}
+
+// SetEventID asocia el aggregate con el ID del evento que lo originó
+func (v *CodeVersion) SetEventID(eventID string) {
+	v.eventID = eventID
+}
+
+// Getters
+func (v *CodeVersion) CodeVersionID() valueobjects.CodeVersionID {
+	return v.codeVersionID
+}
+
+func (v *CodeVersion) EventID() string {
+	return v.eventID
+}
+
+func (v *CodeVersion) ChallengeID() valueobjects.ChallengeID {
+	return v.challengeID
+}
+
+func (v *CodeVersion) StudentID() valueobjects.StudentID {
+	return v.studentID
+}
+
+func (v *CodeVersion) VersionNumber() int {
+	return v.versionNumber
+}
+
+func (v *CodeVersion) ParentVersionID() *valueobjects.CodeVersionID {
+	return v.parentVersionID
+}
+
+func (v *CodeVersion) LinesChanged() int {
+	return v.linesChanged
+}
+
+func (v *CodeVersion) CreatedAt() time.Time {
+	return v.createdAt
+}
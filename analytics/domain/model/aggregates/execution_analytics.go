@@ -4,12 +4,17 @@ import (
 	"github.com/nanab/analytics-service/analytics/domain/model/entities"
 	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
 	"errors"
+	"fmt"
 	"time"
 )
 
+// ErrOutcomeContradictsSuccess se retorna si el resultado del juez no coincide con el éxito de la ejecución
+var ErrOutcomeContradictsSuccess = errors.New("execution outcome contradicts success")
+
 // ExecutionAnalytics es el aggregate root que representa el análisis de una ejecución
 type ExecutionAnalytics struct {
 	id              uint
+	eventID         string // ID del evento de origen (CloudEvents id o envelope id)
 	executionID     valueobjects.ExecutionID
 	challengeID     valueobjects.ChallengeID
 	codeVersionID   string
@@ -23,8 +28,11 @@ type ExecutionAnalytics struct {
 	passedTests     int
 	failedTests     int
 	success         bool
+	outcome         valueobjects.ExecutionOutcome // Resultado según el juez, o derivado del estado y los tests
 	serverInstance  string
 	testResults     []*entities.TestResult
+	revision        int64     // Versión del resultado: aumenta con cada re-evaluación del juez
+	judgedAt        time.Time // Fecha del resultado: desempata versiones con la misma revisión
 	createdAt       time.Time
 	updatedAt       time.Time
 }
@@ -74,8 +82,10 @@ func NewExecutionAnalytics(
 		passedTests:     passedTests,
 		failedTests:     failedTests,
 		success:         success,
+		outcome:         valueobjects.DeriveExecutionOutcome(status, exitCode, totalTests, passedTests, success),
 		serverInstance:  serverInstance,
 		testResults:     make([]*entities.TestResult, 0),
+		judgedAt:        timestamp,
 		createdAt:       now,
 		updatedAt:       now,
 	}, nil
@@ -93,6 +103,41 @@ func (e *ExecutionAnalytics) SetTestResults(testResults []*entities.TestResult)
 	e.updatedAt = time.Now()
 }
 
+// SetEventID asocia el aggregate con el ID del evento que lo originó
+func (e *ExecutionAnalytics) SetEventID(eventID string) {
+	e.eventID = eventID
+}
+
+// SetOutcome establece el resultado enviado por el juez en lugar del derivado. El resultado debe coincidir
+// con success: solo una ejecución exitosa es accepted.
+func (e *ExecutionAnalytics) SetOutcome(outcome valueobjects.ExecutionOutcome) error {
+	if outcome.IsAccepted() != e.success {
+		return fmt.Errorf("%w: outcome %s with success=%t", ErrOutcomeContradictsSuccess, outcome, e.success)
+	}
+	e.outcome = outcome
+	return nil
+}
+
+// SetVersion establece la versión del resultado. Sin fecha se mantiene la de la ejecución.
+func (e *ExecutionAnalytics) SetVersion(revision int64, judgedAt time.Time) {
+	e.revision = revision
+	if !judgedAt.IsZero() {
+		e.judgedAt = judgedAt
+	}
+}
+
+// Supersedes indica si este resultado reemplaza al guardado de la misma ejecución: gana la mayor revisión y,
+// con la misma revisión, la fecha de evaluación más reciente. Un evento nunca se reemplaza a sí mismo.
+func (e *ExecutionAnalytics) Supersedes(stored *ExecutionAnalytics) bool {
+	if e.eventID != "" && e.eventID == stored.eventID {
+		return false
+	}
+	if e.revision != stored.revision {
+		return e.revision > stored.revision
+	}
+	return e.judgedAt.After(stored.judgedAt)
+}
+
 // CalculateSuccessRate calcula el porcentaje de éxito
 func (e *ExecutionAnalytics) CalculateSuccessRate() float64 {
 	if e.totalTests == 0 {
@@ -101,6 +146,25 @@ func (e *ExecutionAnalytics) CalculateSuccessRate() float64 {
 	return (float64(e.passedTests) / float64(e.totalTests)) * 100.0
 }
 
+// Points retorna los puntos obtenidos y los posibles según el peso de cada test result
+func (e *ExecutionAnalytics) Points() (earned, total float64) {
+	for _, testResult := range e.testResults {
+		earned += testResult.EarnedPoints()
+		total += testResult.Weight()
+	}
+	return earned, total
+}
+
+// WeightedScore calcula el puntaje ponderado por el peso de los tests (0 a 100). Sin test results con peso
+// se usa el porcentaje de tests aprobados.
+func (e *ExecutionAnalytics) WeightedScore() float64 {
+	earned, total := e.Points()
+	if total == 0 {
+		return e.CalculateSuccessRate()
+	}
+	return (earned / total) * 100.0
+}
+
 // IsSlowExecution indica si la ejecución fue lenta (>5000ms)
 func (e *ExecutionAnalytics) IsSlowExecution() bool {
 	return e.executionTimeMs > 5000
@@ -116,6 +180,10 @@ func (e *ExecutionAnalytics) ID() uint {
 	return e.id
 }
 
+func (e *ExecutionAnalytics) EventID() string {
+	return e.eventID
+}
+
 func (e *ExecutionAnalytics) ExecutionID() valueobjects.ExecutionID {
 	return e.executionID
 }
@@ -168,6 +236,10 @@ func (e *ExecutionAnalytics) Success() bool {
 	return e.success
 }
 
+func (e *ExecutionAnalytics) Outcome() valueobjects.ExecutionOutcome {
+	return e.outcome
+}
+
 func (e *ExecutionAnalytics) ServerInstance() string {
 	return e.serverInstance
 }
@@ -176,6 +248,14 @@ func (e *ExecutionAnalytics) TestResults() []*entities.TestResult {
 	return e.testResults
 }
 
+func (e *ExecutionAnalytics) Revision() int64 {
+	return e.revision
+}
+
+func (e *ExecutionAnalytics) JudgedAt() time.Time {
+	return e.judgedAt
+}
+
 func (e *ExecutionAnalytics) CreatedAt() time.Time {
 	return e.createdAt
 }
@@ -0,0 +1,90 @@
+package aggregates
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+)
+
+// StudentProgress es el avance del estudiante incluyendo una ejecución recién guardada
+type StudentProgress struct {
+	FirstSolve          bool // Es la primera ejecución exitosa del estudiante en el challenge
+	Attempts            int  // Ejecuciones del estudiante en el challenge hasta esta, inclusive
+	SolvedChallenges    int  // Challenges distintos resueltos por el estudiante hasta esta ejecución
+	ConsecutiveFailures int  // Ejecuciones fallidas seguidas del estudiante que terminan en esta
+	SolveRevoked        bool // Una re-evaluación dejó al estudiante sin ejecuciones exitosas en el challenge
+}
+
+// DerivedEventPolicy define cuándo una ejecución genera eventos de dominio
+type DerivedEventPolicy struct {
+	Milestones                []int // Cantidades de challenges resueltos que se notifican como hito
+	AtRiskConsecutiveFailures int   // Fallos seguidos a partir de los cuales el estudiante está en riesgo (0 = desactivado)
+}
+
+// DerivedEvents retorna los eventos de dominio que genera la ejecución dado el avance del estudiante.
+// El evento de riesgo se genera solo al alcanzar el umbral, no en cada fallo posterior.
+func (e *ExecutionAnalytics) DerivedEvents(progress StudentProgress, policy DerivedEventPolicy) []*entities.DomainEvent {
+	derived := make([]*entities.DomainEvent, 0)
+	studentID := e.studentID.Value()
+
+	if e.success && progress.FirstSolve {
+		derived = append(derived, entities.NewDomainEvent(valueobjects.DomainEventChallengeFirstSolved, studentID, e.timestamp, map[string]interface{}{
+			"student_id":   studentID,
+			"challenge_id": e.challengeID.Value(),
+			"execution_id": e.executionID.Value(),
+			"language":     e.language.Value(),
+			"attempts":     progress.Attempts,
+			"solved_at":    e.timestamp,
+		}))
+
+		for _, milestone := range policy.Milestones {
+			if progress.SolvedChallenges == milestone {
+				derived = append(derived, entities.NewDomainEvent(valueobjects.DomainEventStudentMilestoneReached, studentID, e.timestamp, map[string]interface{}{
+					"student_id":        studentID,
+					"milestone":         milestone,
+					"solved_challenges": progress.SolvedChallenges,
+					"challenge_id":      e.challengeID.Value(),
+					"execution_id":      e.executionID.Value(),
+					"reached_at":        e.timestamp,
+				}))
+				break
+			}
+		}
+	}
+
+	if !e.success && policy.AtRiskConsecutiveFailures > 0 && progress.ConsecutiveFailures == policy.AtRiskConsecutiveFailures {
+		derived = append(derived, entities.NewDomainEvent(valueobjects.DomainEventStudentAtRisk, studentID, e.timestamp, map[string]interface{}{
+			"student_id":           studentID,
+			"consecutive_failures": progress.ConsecutiveFailures,
+			"challenge_id":         e.challengeID.Value(),
+			"execution_id":         e.executionID.Value(),
+			"detected_at":          e.timestamp,
+		}))
+	}
+
+	return derived
+}
+
+// RejudgedEvents retorna los eventos de dominio que genera una re-evaluación que reemplazó a previous.
+// Solo un cambio de success genera eventos: si la ejecución pasa a exitosa se evalúa como una ejecución nueva
+// (primera resolución e hitos); si pasa a fallida puede alcanzar el umbral de riesgo y, si el estudiante se
+// queda sin ejecuciones exitosas en el challenge, se publica solve_revoked para invalidar el first_solved ya
+// emitido. Los hitos y avisos de riesgo ya publicados no se retiran.
+func (e *ExecutionAnalytics) RejudgedEvents(previous *ExecutionAnalytics, progress StudentProgress, policy DerivedEventPolicy) []*entities.DomainEvent {
+	if previous == nil || previous.success == e.success {
+		return []*entities.DomainEvent{}
+	}
+
+	derived := make([]*entities.DomainEvent, 0)
+	if !e.success && progress.SolveRevoked {
+		studentID := e.studentID.Value()
+		derived = append(derived, entities.NewDomainEvent(valueobjects.DomainEventChallengeSolveRevoked, studentID, e.judgedAt, map[string]interface{}{
+			"student_id":        studentID,
+			"challenge_id":      e.challengeID.Value(),
+			"execution_id":      e.executionID.Value(),
+			"revision":          e.revision,
+			"solved_challenges": progress.SolvedChallenges,
+			"revoked_at":        e.judgedAt,
+		}))
+	}
+	return append(derived, e.DerivedEvents(progress, policy)...)
+}
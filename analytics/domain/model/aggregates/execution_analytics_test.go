@@ -0,0 +1,90 @@
+package aggregates
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestExecution crea una ejecución con el éxito y los tests indicados
+func newTestExecution(t *testing.T, success bool, passedTests int) *ExecutionAnalytics {
+	t.Helper()
+
+	status := valueobjects.StatusFailed
+	if success {
+		status = valueobjects.StatusCompleted
+	}
+
+	execution, err := NewExecutionAnalytics(
+		valueobjects.ExecutionID{},
+		valueobjects.ChallengeID{},
+		"",
+		valueobjects.StudentID{},
+		valueobjects.RestoreProgrammingLanguage("python", "python", "3.12", "python"),
+		status,
+		time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC),
+		120,
+		0,
+		3,
+		passedTests,
+		3-passedTests,
+		success,
+		"judge-1",
+	)
+	if err != nil {
+		t.Fatalf("NewExecutionAnalytics: %v", err)
+	}
+	return execution
+}
+
+func TestSetOutcome(t *testing.T) {
+	tests := []struct {
+		name        string
+		success     bool
+		passedTests int
+		outcome     valueobjects.ExecutionOutcome
+		contradicts bool
+	}{
+		{"accepted on success", true, 3, valueobjects.OutcomeAccepted, false},
+		{"wrong answer on failure", false, 0, valueobjects.OutcomeWrongAnswer, false},
+		{"time limit on failure", false, 1, valueobjects.OutcomeTimeLimitExceeded, false},
+		{"internal error on failure", false, 0, valueobjects.OutcomeInternalError, false},
+		{"accepted on failure", false, 3, valueobjects.OutcomeAccepted, true},
+		{"wrong answer on success", true, 3, valueobjects.OutcomeWrongAnswer, true},
+		{"partial pass on success", true, 3, valueobjects.OutcomePartialPass, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execution := newTestExecution(t, tt.success, tt.passedTests)
+			derived := execution.Outcome()
+
+			err := execution.SetOutcome(tt.outcome)
+			if tt.contradicts {
+				if !errors.Is(err, ErrOutcomeContradictsSuccess) {
+					t.Fatalf("expected ErrOutcomeContradictsSuccess, got %v", err)
+				}
+				if execution.Outcome() != derived {
+					t.Fatalf("expected derived outcome %s to be kept, got %s", derived, execution.Outcome())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetOutcome: %v", err)
+			}
+			if execution.Outcome() != tt.outcome {
+				t.Fatalf("expected %s, got %s", tt.outcome, execution.Outcome())
+			}
+		})
+	}
+}
+
+func TestNewExecutionAnalyticsDerivesOutcome(t *testing.T) {
+	if outcome := newTestExecution(t, true, 3).Outcome(); outcome != valueobjects.OutcomeAccepted {
+		t.Fatalf("expected accepted, got %s", outcome)
+	}
+	if outcome := newTestExecution(t, false, 2).Outcome(); outcome != valueobjects.OutcomePartialPass {
+		t.Fatalf("expected partial_pass, got %s", outcome)
+	}
+}
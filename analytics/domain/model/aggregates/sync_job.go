@@ -0,0 +1,358 @@
+package aggregates
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSyncJobTransition se retorna al intentar un cambio de estado no permitido
+var ErrInvalidSyncJobTransition = errors.New("invalid sync job transition")
+
+// ErrInvalidReplayWindow se retorna si la ventana de tiempo de un replay no es válida
+var ErrInvalidReplayWindow = errors.New("invalid replay window")
+
+// SyncJob es el aggregate root que representa una sincronización de un tópico de Kafka hacia la base de datos.
+// Guarda el avance por partición para poder retomarse tras un reinicio o una cancelación.
+// Un replay sincroniza solo los mensajes publicados en una ventana de tiempo.
+// En modo dry-run no escribe datos: compara el tópico con la base de datos y genera un reporte de reconciliación.
+type SyncJob struct {
+	id           string
+	target       valueobjects.SyncTarget
+	topic        string
+	from         *time.Time // Inicio de la ventana del replay (nil = desde el offset más antiguo)
+	to           *time.Time // Fin de la ventana del replay (nil = hasta el high-water mark)
+	dryRun       bool
+	status       valueobjects.SyncJobStatus
+	checkpoints  []*entities.SyncCheckpoint
+	synced       int64 // Eventos guardados
+	skipped      int64 // Eventos que ya existían
+	invalid      int64 // Mensajes que no pudieron decodificarse o validarse
+	missing      int64 // Dry-run: eventos que no están en la base de datos
+	orphaned     int64 // Dry-run: registros de la base de datos sin evento en el tópico
+	errorMessage string
+	createdAt    time.Time
+	startedAt    *time.Time
+	finishedAt   *time.Time
+	updatedAt    time.Time
+}
+
+// NewSyncJob crea un nuevo job de sincronización pendiente
+func NewSyncJob(target valueobjects.SyncTarget, topic string, dryRun bool) (*SyncJob, error) {
+	if topic == "" {
+		return nil, errors.New("sync job topic cannot be empty")
+	}
+
+	now := time.Now()
+	return &SyncJob{
+		id:          uuid.New().String(),
+		target:      target,
+		topic:       topic,
+		dryRun:      dryRun,
+		status:      valueobjects.SyncJobPending,
+		checkpoints: make([]*entities.SyncCheckpoint, 0),
+		createdAt:   now,
+		updatedAt:   now,
+	}, nil
+}
+
+// NewReplayJob crea un job que reprocesa los mensajes publicados desde from y, si se indica, antes de to
+func NewReplayJob(target valueobjects.SyncTarget, topic string, from time.Time, to *time.Time, dryRun bool) (*SyncJob, error) {
+	if from.IsZero() {
+		return nil, fmt.Errorf("%w: start time cannot be empty", ErrInvalidReplayWindow)
+	}
+	if to != nil && !to.After(from) {
+		return nil, fmt.Errorf("%w: end time must be after start time", ErrInvalidReplayWindow)
+	}
+	if from.After(time.Now()) {
+		return nil, fmt.Errorf("%w: start time cannot be in the future", ErrInvalidReplayWindow)
+	}
+
+	job, err := NewSyncJob(target, topic, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	job.from = &from
+	job.to = to
+	return job, nil
+}
+
+// RestoreSyncJob reconstruye un job guardado
+func RestoreSyncJob(
+	id string,
+	target valueobjects.SyncTarget,
+	topic string,
+	from *time.Time,
+	to *time.Time,
+	dryRun bool,
+	status valueobjects.SyncJobStatus,
+	checkpoints []*entities.SyncCheckpoint,
+	synced int64,
+	skipped int64,
+	invalid int64,
+	missing int64,
+	orphaned int64,
+	errorMessage string,
+	createdAt time.Time,
+	startedAt *time.Time,
+	finishedAt *time.Time,
+	updatedAt time.Time,
+) *SyncJob {
+	return &SyncJob{
+		id:           id,
+		target:       target,
+		topic:        topic,
+		from:         from,
+		to:           to,
+		dryRun:       dryRun,
+		status:       status,
+		checkpoints:  checkpoints,
+		synced:       synced,
+		skipped:      skipped,
+		invalid:      invalid,
+		missing:      missing,
+		orphaned:     orphaned,
+		errorMessage: errorMessage,
+		createdAt:    createdAt,
+		startedAt:    startedAt,
+		finishedAt:   finishedAt,
+		updatedAt:    updatedAt,
+	}
+}
+
+// Start inicia el job con el rango de offsets de cada partición
+func (j *SyncJob) Start(checkpoints []*entities.SyncCheckpoint) error {
+	if j.status != valueobjects.SyncJobPending {
+		return fmt.Errorf("%w: cannot start a %s job", ErrInvalidSyncJobTransition, j.status)
+	}
+
+	now := time.Now()
+	j.checkpoints = checkpoints
+	j.status = valueobjects.SyncJobRunning
+	j.startedAt = &now
+	j.updatedAt = now
+	return nil
+}
+
+// Resume retoma un job fallido, cancelado o interrumpido desde sus checkpoints
+func (j *SyncJob) Resume() error {
+	switch j.status {
+	case valueobjects.SyncJobFailed, valueobjects.SyncJobCancelled, valueobjects.SyncJobRunning:
+	default:
+		return fmt.Errorf("%w: cannot resume a %s job", ErrInvalidSyncJobTransition, j.status)
+	}
+
+	j.status = valueobjects.SyncJobRunning
+	j.errorMessage = ""
+	j.finishedAt = nil
+	j.updatedAt = time.Now()
+	return nil
+}
+
+// RecordSynced registra un evento guardado y avanza el checkpoint de su partición
+func (j *SyncJob) RecordSynced(partition int32, offset int64) {
+	j.synced++
+	j.advance(partition, offset+1)
+}
+
+// RecordSkipped registra un evento que ya existía y avanza el checkpoint de su partición
+func (j *SyncJob) RecordSkipped(partition int32, offset int64) {
+	j.skipped++
+	j.advance(partition, offset+1)
+}
+
+// RecordInvalid registra un mensaje inválido y avanza el checkpoint de su partición
+func (j *SyncJob) RecordInvalid(partition int32, offset int64) {
+	j.invalid++
+	j.advance(partition, offset+1)
+}
+
+// RecordMissing registra un evento que no está en la base de datos (dry-run) y avanza el checkpoint de su partición
+func (j *SyncJob) RecordMissing(partition int32, offset int64) {
+	j.missing++
+	j.advance(partition, offset+1)
+}
+
+// RecordOrphaned registra la cantidad de registros de la base de datos sin evento en el tópico (dry-run)
+func (j *SyncJob) RecordOrphaned(count int64) {
+	j.orphaned = count
+	j.updatedAt = time.Now()
+}
+
+// ReportsOrphans indica si el job busca registros sin evento: solo un dry-run del tópico completo puede hacerlo
+func (j *SyncJob) ReportsOrphans() bool {
+	return j.dryRun && !j.IsReplay()
+}
+
+// CompletePartition marca una partición como sincronizada aunque no se haya llegado a su último offset
+// (por ejemplo, si ya se leyó un mensaje posterior y los anteriores fueron compactados)
+func (j *SyncJob) CompletePartition(partition int32) {
+	if checkpoint := j.Checkpoint(partition); checkpoint != nil {
+		j.advance(partition, checkpoint.EndOffset())
+	}
+}
+
+// StopPartition da una partición por terminada en el offset al que llegó. Si faltaban offsets por leer
+// (por ejemplo, si la fuente dejó de entregar mensajes), la partición queda incompleta y los offsets
+// sin leer se cuentan en Unread.
+func (j *SyncJob) StopPartition(partition int32) {
+	if checkpoint := j.Checkpoint(partition); checkpoint != nil {
+		checkpoint.Stop()
+	}
+	j.updatedAt = time.Now()
+}
+
+// advance mueve el checkpoint de una partición
+func (j *SyncJob) advance(partition int32, nextOffset int64) {
+	if checkpoint := j.Checkpoint(partition); checkpoint != nil {
+		checkpoint.Advance(nextOffset)
+	}
+	j.updatedAt = time.Now()
+}
+
+// Complete marca el job como completado
+func (j *SyncJob) Complete() error {
+	return j.finish(valueobjects.SyncJobCompleted, "")
+}
+
+// Fail marca el job como fallido
+func (j *SyncJob) Fail(cause error) error {
+	return j.finish(valueobjects.SyncJobFailed, cause.Error())
+}
+
+// Cancel marca el job como cancelado
+func (j *SyncJob) Cancel() error {
+	return j.finish(valueobjects.SyncJobCancelled, "")
+}
+
+// finish termina un job activo
+func (j *SyncJob) finish(status valueobjects.SyncJobStatus, errorMessage string) error {
+	if !j.status.IsActive() {
+		return fmt.Errorf("%w: job is already %s", ErrInvalidSyncJobTransition, j.status)
+	}
+
+	now := time.Now()
+	j.status = status
+	j.errorMessage = errorMessage
+	j.finishedAt = &now
+	j.updatedAt = now
+	return nil
+}
+
+// Checkpoint retorna el checkpoint de una partición, o nil si no existe
+func (j *SyncJob) Checkpoint(partition int32) *entities.SyncCheckpoint {
+	for _, checkpoint := range j.checkpoints {
+		if checkpoint.Partition() == partition {
+			return checkpoint
+		}
+	}
+	return nil
+}
+
+// IsReplay indica si el job sincroniza solo una ventana de tiempo
+func (j *SyncJob) IsReplay() bool {
+	return j.from != nil
+}
+
+// Progress retorna el porcentaje de offsets sincronizados
+func (j *SyncJob) Progress() float64 {
+	if j.status == valueobjects.SyncJobCompleted && j.Unread() == 0 {
+		return 100.0
+	}
+
+	var total, done int64
+	for _, checkpoint := range j.checkpoints {
+		total += checkpoint.Total()
+		done += checkpoint.Done()
+	}
+	if total == 0 {
+		return 0.0
+	}
+	return (float64(done) / float64(total)) * 100.0
+}
+
+// Unread retorna la cantidad de offsets que quedaron sin leer en particiones incompletas
+func (j *SyncJob) Unread() int64 {
+	var unread int64
+	for _, checkpoint := range j.checkpoints {
+		unread += checkpoint.Unread()
+	}
+	return unread
+}
+
+// Getters
+func (j *SyncJob) ID() string {
+	return j.id
+}
+
+func (j *SyncJob) Target() valueobjects.SyncTarget {
+	return j.target
+}
+
+func (j *SyncJob) Topic() string {
+	return j.topic
+}
+
+func (j *SyncJob) From() *time.Time {
+	return j.from
+}
+
+func (j *SyncJob) To() *time.Time {
+	return j.to
+}
+
+func (j *SyncJob) DryRun() bool {
+	return j.dryRun
+}
+
+func (j *SyncJob) Status() valueobjects.SyncJobStatus {
+	return j.status
+}
+
+func (j *SyncJob) Checkpoints() []*entities.SyncCheckpoint {
+	return j.checkpoints
+}
+
+func (j *SyncJob) Synced() int64 {
+	return j.synced
+}
+
+func (j *SyncJob) Skipped() int64 {
+	return j.skipped
+}
+
+func (j *SyncJob) Invalid() int64 {
+	return j.invalid
+}
+
+func (j *SyncJob) Missing() int64 {
+	return j.missing
+}
+
+func (j *SyncJob) Orphaned() int64 {
+	return j.orphaned
+}
+
+func (j *SyncJob) ErrorMessage() string {
+	return j.errorMessage
+}
+
+func (j *SyncJob) CreatedAt() time.Time {
+	return j.createdAt
+}
+
+func (j *SyncJob) StartedAt() *time.Time {
+	return j.startedAt
+}
+
+func (j *SyncJob) FinishedAt() *time.Time {
+	return j.finishedAt
+}
+
+func (j *SyncJob) UpdatedAt() time.Time {
+	return j.updatedAt
+}
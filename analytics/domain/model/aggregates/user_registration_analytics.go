@@ -9,6 +9,7 @@ import (
 // UserRegistrationAnalytics es el aggregate root que representa el análisis de un registro de usuario en la comunidad
 type UserRegistrationAnalytics struct {
 	id           uint
+	eventID      string // ID del evento de origen (CloudEvents id o envelope id)
 	userID       valueobjects.UserID
 	profileID    valueobjects.ProfileID
 	username     string
@@ -47,6 +48,11 @@ func NewUserRegistrationAnalytics(
 	}, nil
 }
 
+// SetEventID asocia el aggregate con el ID del evento que lo originó
+func (u *UserRegistrationAnalytics) SetEventID(eventID string) {
+	u.eventID = eventID
+}
+
 // HasProfileURL indica si el usuario tiene URL de perfil
 func (u *UserRegistrationAnalytics) HasProfileURL() bool {
 	return u.profileURL != nil && *u.profileURL != ""
@@ -57,6 +63,10 @@ func (u *UserRegistrationAnalytics) ID() uint {
 	return u.id
 }
 
+func (u *UserRegistrationAnalytics) EventID() string {
+	return u.eventID
+}
+
 func (u *UserRegistrationAnalytics) UserID() valueobjects.UserID {
 	return u.userID
 }
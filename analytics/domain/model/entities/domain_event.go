@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainEvent es un hecho derivado de los datos ingeridos (por ejemplo, un estudiante resolvió un challenge
+// por primera vez) que se publica para que otros servicios reaccionen sin consultar la API
+type DomainEvent struct {
+	id         string
+	eventType  valueobjects.DomainEventType
+	key        string // Clave de partición al publicar (ID del estudiante): mantiene el orden por estudiante
+	occurredAt time.Time
+	data       map[string]interface{}
+}
+
+// NewDomainEvent crea un evento de dominio con un ID nuevo
+func NewDomainEvent(eventType valueobjects.DomainEventType, key string, occurredAt time.Time, data map[string]interface{}) *DomainEvent {
+	return &DomainEvent{
+		id:         uuid.New().String(),
+		eventType:  eventType,
+		key:        key,
+		occurredAt: occurredAt,
+		data:       data,
+	}
+}
+
+// ID retorna el ID del evento, que los consumidores usan para descartar duplicados
+func (e *DomainEvent) ID() string {
+	return e.id
+}
+
+// Type retorna el tipo del evento
+func (e *DomainEvent) Type() valueobjects.DomainEventType {
+	return e.eventType
+}
+
+// Key retorna la clave de partición del evento
+func (e *DomainEvent) Key() string {
+	return e.key
+}
+
+// OccurredAt retorna cuándo ocurrió el hecho
+func (e *DomainEvent) OccurredAt() time.Time {
+	return e.occurredAt
+}
+
+// Data retorna los datos del evento
+func (e *DomainEvent) Data() map[string]interface{} {
+	return e.data
+}
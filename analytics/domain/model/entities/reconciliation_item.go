@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+)
+
+// ReconciliationItem es una diferencia entre un tópico y la base de datos encontrada por un job de sincronización
+type ReconciliationItem struct {
+	kind      valueobjects.ReconciliationKind
+	key       string // ID de negocio del evento o registro (execution_id, user_id); vacío si no pudo leerse
+	partition *int32 // Posición del evento en el tópico (nil para registros sin evento)
+	offset    *int64
+	reason    string
+}
+
+// NewReconciliationItem crea una diferencia encontrada en un mensaje del tópico
+func NewReconciliationItem(kind valueobjects.ReconciliationKind, key string, partition int32, offset int64, reason string) *ReconciliationItem {
+	return &ReconciliationItem{
+		kind:      kind,
+		key:       key,
+		partition: &partition,
+		offset:    &offset,
+		reason:    reason,
+	}
+}
+
+// RestoreReconciliationItem reconstruye una diferencia guardada
+func RestoreReconciliationItem(kind valueobjects.ReconciliationKind, key string, partition *int32, offset *int64, reason string) *ReconciliationItem {
+	return &ReconciliationItem{
+		kind:      kind,
+		key:       key,
+		partition: partition,
+		offset:    offset,
+		reason:    reason,
+	}
+}
+
+// Kind retorna el tipo de diferencia
+func (r *ReconciliationItem) Kind() valueobjects.ReconciliationKind {
+	return r.kind
+}
+
+// Key retorna el ID de negocio del evento o registro
+func (r *ReconciliationItem) Key() string {
+	return r.key
+}
+
+// Partition retorna la partición del evento
+func (r *ReconciliationItem) Partition() *int32 {
+	return r.partition
+}
+
+// Offset retorna el offset del evento
+func (r *ReconciliationItem) Offset() *int64 {
+	return r.offset
+}
+
+// Reason retorna el motivo de la diferencia
+func (r *ReconciliationItem) Reason() string {
+	return r.reason
+}
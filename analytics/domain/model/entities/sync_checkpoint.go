@@ -0,0 +1,104 @@
+package entities
+
+// SyncCheckpoint es el avance de un job de sincronización en una partición.
+// El job termina la partición al llegar a endOffset, el high-water mark al iniciar el job.
+// Una partición incompleta se dio por terminada antes de llegar a endOffset: nextOffset queda donde se detuvo.
+type SyncCheckpoint struct {
+	partition   int32
+	startOffset int64
+	nextOffset  int64
+	endOffset   int64
+	incomplete  bool
+}
+
+// NewSyncCheckpoint crea un checkpoint que parte en startOffset
+func NewSyncCheckpoint(partition int32, startOffset, endOffset int64) *SyncCheckpoint {
+	return &SyncCheckpoint{
+		partition:   partition,
+		startOffset: startOffset,
+		nextOffset:  startOffset,
+		endOffset:   endOffset,
+	}
+}
+
+// RestoreSyncCheckpoint reconstruye un checkpoint guardado
+func RestoreSyncCheckpoint(partition int32, startOffset, nextOffset, endOffset int64, incomplete bool) *SyncCheckpoint {
+	return &SyncCheckpoint{
+		partition:   partition,
+		startOffset: startOffset,
+		nextOffset:  nextOffset,
+		endOffset:   endOffset,
+		incomplete:  incomplete,
+	}
+}
+
+// Advance mueve el checkpoint al offset del siguiente mensaje a leer
+func (c *SyncCheckpoint) Advance(nextOffset int64) {
+	if nextOffset > c.nextOffset {
+		c.nextOffset = nextOffset
+	}
+}
+
+// Stop da la partición por terminada en nextOffset. Si no llegó a endOffset, queda marcada como incompleta.
+func (c *SyncCheckpoint) Stop() {
+	if c.nextOffset < c.endOffset {
+		c.incomplete = true
+	}
+}
+
+// Partition retorna la partición
+func (c *SyncCheckpoint) Partition() int32 {
+	return c.partition
+}
+
+// StartOffset retorna el offset en que comenzó la sincronización
+func (c *SyncCheckpoint) StartOffset() int64 {
+	return c.startOffset
+}
+
+// NextOffset retorna el offset del siguiente mensaje a leer
+func (c *SyncCheckpoint) NextOffset() int64 {
+	return c.nextOffset
+}
+
+// EndOffset retorna el offset en que termina la sincronización
+func (c *SyncCheckpoint) EndOffset() int64 {
+	return c.endOffset
+}
+
+// IsDone indica si la partición ya fue sincronizada o se dio por terminada
+func (c *SyncCheckpoint) IsDone() bool {
+	return c.nextOffset >= c.endOffset || c.incomplete
+}
+
+// IsIncomplete indica si la partición se dio por terminada antes de llegar a endOffset
+func (c *SyncCheckpoint) IsIncomplete() bool {
+	return c.incomplete
+}
+
+// Unread retorna la cantidad de offsets que quedaron sin leer en una partición incompleta
+func (c *SyncCheckpoint) Unread() int64 {
+	if !c.incomplete || c.nextOffset >= c.endOffset {
+		return 0
+	}
+	return c.endOffset - c.nextOffset
+}
+
+// Total retorna la cantidad de offsets a sincronizar
+func (c *SyncCheckpoint) Total() int64 {
+	if c.endOffset < c.startOffset {
+		return 0
+	}
+	return c.endOffset - c.startOffset
+}
+
+// Done retorna la cantidad de offsets ya sincronizados
+func (c *SyncCheckpoint) Done() int64 {
+	if c.nextOffset >= c.endOffset {
+		return c.Total()
+	}
+	if c.nextOffset < c.startOffset {
+		return 0
+	}
+	return c.nextOffset - c.startOffset
+}
@@ -2,17 +2,31 @@ package entities
 
 import (
 	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"errors"
+	"unicode/utf8"
 )
 
+// MaxTestOutputLength es la cantidad máxima de caracteres que se guardan de la salida esperada y la obtenida
+const MaxTestOutputLength = 1000
+
+// DefaultTestWeight es el peso de un test que no lo informa
+const DefaultTestWeight = 1.0
+
 // TestResult representa el resultado de un test individual
 type TestResult struct {
-	testID       valueobjects.TestID
-	testName     string
-	passed       bool
-	errorMessage string
+	testID          valueobjects.TestID
+	testName        string
+	passed          bool
+	errorMessage    string
+	durationMs      *int64 // nil si el juez no informa la duración
+	visibility      valueobjects.TestVisibility
+	weight          float64 // Puntos que otorga el test si pasa
+	expectedOutput  string
+	actualOutput    string
+	outputTruncated bool // Alguna de las salidas superaba MaxTestOutputLength
 }
 
-// NewTestResult crea una nueva instancia de TestResult
+// NewTestResult crea una nueva instancia de TestResult, público y con DefaultTestWeight
 func NewTestResult(
 	testID valueobjects.TestID,
 	testName string,
@@ -24,7 +38,63 @@ func NewTestResult(
 		testName:     testName,
 		passed:       passed,
 		errorMessage: errorMessage,
+		visibility:   valueobjects.TestVisibilityPublic,
+		weight:       DefaultTestWeight,
+	}
+}
+
+// SetDuration establece la duración del test
+func (t *TestResult) SetDuration(durationMs int64) error {
+	if durationMs < 0 {
+		return errors.New("test duration cannot be negative")
+	}
+	t.durationMs = &durationMs
+	return nil
+}
+
+// SetVisibility establece si el test es público u oculto
+func (t *TestResult) SetVisibility(visibility valueobjects.TestVisibility) {
+	t.visibility = visibility
+}
+
+// SetWeight establece los puntos que otorga el test
+func (t *TestResult) SetWeight(weight float64) error {
+	if weight < 0 {
+		return errors.New("test weight cannot be negative")
+	}
+	t.weight = weight
+	return nil
+}
+
+// SetOutputs establece la salida esperada y la obtenida, truncadas a MaxTestOutputLength caracteres
+func (t *TestResult) SetOutputs(expectedOutput, actualOutput string) {
+	var expectedTruncated, actualTruncated bool
+	t.expectedOutput, expectedTruncated = truncateOutput(expectedOutput)
+	t.actualOutput, actualTruncated = truncateOutput(actualOutput)
+	t.outputTruncated = t.outputTruncated || expectedTruncated || actualTruncated
+}
+
+// RestoreOutputs establece salidas ya truncadas (desde persistencia)
+func (t *TestResult) RestoreOutputs(expectedOutput, actualOutput string, truncated bool) {
+	t.expectedOutput = expectedOutput
+	t.actualOutput = actualOutput
+	t.outputTruncated = truncated
+}
+
+// truncateOutput recorta una salida a MaxTestOutputLength caracteres sin cortar caracteres multibyte
+func truncateOutput(output string) (string, bool) {
+	if utf8.RuneCountInString(output) <= MaxTestOutputLength {
+		return output, false
 	}
+
+	count := 0
+	for i := range output {
+		if count == MaxTestOutputLength {
+			return output[:i], true
+		}
+		count++
+	}
+	return output, false
 }
 
 // TestID retorna el ID del test
@@ -51,3 +121,41 @@ func (t *TestResult) ErrorMessage() string {
 func (t *TestResult) HasError() bool {
 	return t.errorMessage != ""
 }
+
+// DurationMs retorna la duración del test, nil si no se conoce
+func (t *TestResult) DurationMs() *int64 {
+	return t.durationMs
+}
+
+// Visibility retorna si el test es público u oculto
+func (t *TestResult) Visibility() valueobjects.TestVisibility {
+	return t.visibility
+}
+
+// Weight retorna los puntos que otorga el test
+func (t *TestResult) Weight() float64 {
+	return t.weight
+}
+
+// EarnedPoints retorna los puntos obtenidos: el peso si el test pasó, 0 si no
+func (t *TestResult) EarnedPoints() float64 {
+	if t.passed {
+		return t.weight
+	}
+	return 0
+}
+
+// ExpectedOutput retorna la salida esperada (truncada)
+func (t *TestResult) ExpectedOutput() string {
+	return t.expectedOutput
+}
+
+// ActualOutput retorna la salida obtenida (truncada)
+func (t *TestResult) ActualOutput() string {
+	return t.actualOutput
+}
+
+// OutputTruncated indica si alguna de las salidas se truncó
+func (t *TestResult) OutputTruncated() bool {
+	return t.outputTruncated
+}
@@ -0,0 +1,34 @@
+package valueobjects
+
+import "errors"
+
+// ChallengeChange es el tipo de cambio del catálogo de challenges que informa un evento
+type ChallengeChange string
+
+const (
+	ChallengeCreated  ChallengeChange = "created"
+	ChallengeUpdated  ChallengeChange = "updated"
+	ChallengeArchived ChallengeChange = "archived"
+)
+
+// NewChallengeChange crea y valida un ChallengeChange
+func NewChallengeChange(value string) (ChallengeChange, error) {
+	change := ChallengeChange(value)
+
+	switch change {
+	case ChallengeCreated, ChallengeUpdated, ChallengeArchived:
+		return change, nil
+	default:
+		return "", errors.New("invalid challenge change")
+	}
+}
+
+// String implementa Stringer
+func (c ChallengeChange) String() string {
+	return string(c)
+}
+
+// Value retorna el valor del ChallengeChange
+func (c ChallengeChange) Value() string {
+	return string(c)
+}
@@ -0,0 +1,40 @@
+package valueobjects
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// CodeVersionID representa el identificador único de una versión del código enviado por un estudiante
+type CodeVersionID struct {
+	value string
+}
+
+// NewCodeVersionID crea un nuevo CodeVersionID validando que sea un UUID válido
+func NewCodeVersionID(value string) (CodeVersionID, error) {
+	if value == "" {
+		return CodeVersionID{}, errors.New("code version ID cannot be empty")
+	}
+
+	if _, err := uuid.Parse(value); err != nil {
+		return CodeVersionID{}, errors.New("invalid code version ID format: must be a valid UUID")
+	}
+
+	return CodeVersionID{value: value}, nil
+}
+
+// Value retorna el valor del CodeVersionID
+func (c CodeVersionID) Value() string {
+	return c.value
+}
+
+// Equals compara dos CodeVersionIDs
+func (c CodeVersionID) Equals(other CodeVersionID) bool {
+	return c.value == other.value
+}
+
+// String implementa Stringer
+func (c CodeVersionID) String() string {
+	return c.value
+}
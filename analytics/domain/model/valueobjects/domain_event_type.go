@@ -0,0 +1,36 @@
+package valueobjects
+
+import "errors"
+
+// DomainEventType identifica un hecho derivado que el servicio publica para otros servicios
+type DomainEventType string
+
+const (
+	DomainEventChallengeFirstSolved    DomainEventType = "analytics.challenge.first_solved"
+	DomainEventStudentMilestoneReached DomainEventType = "analytics.student.milestone_reached"
+	DomainEventStudentAtRisk           DomainEventType = "analytics.student.at_risk"
+	DomainEventChallengeSolveRevoked   DomainEventType = "analytics.challenge.solve_revoked"
+)
+
+// NewDomainEventType crea y valida un DomainEventType
+func NewDomainEventType(value string) (DomainEventType, error) {
+	eventType := DomainEventType(value)
+
+	switch eventType {
+	case DomainEventChallengeFirstSolved, DomainEventStudentMilestoneReached, DomainEventStudentAtRisk,
+		DomainEventChallengeSolveRevoked:
+		return eventType, nil
+	default:
+		return "", errors.New("invalid domain event type")
+	}
+}
+
+// String implementa Stringer
+func (t DomainEventType) String() string {
+	return string(t)
+}
+
+// Value retorna el valor del DomainEventType
+func (t DomainEventType) Value() string {
+	return string(t)
+}
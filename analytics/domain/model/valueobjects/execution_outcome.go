@@ -0,0 +1,123 @@
+package valueobjects
+
+import (
+	"errors"
+	"strings"
+)
+
+// ExecutionOutcome representa el resultado de una ejecución según el juez
+type ExecutionOutcome string
+
+const (
+	OutcomeAccepted            ExecutionOutcome = "accepted"
+	OutcomeCompileError        ExecutionOutcome = "compile_error"
+	OutcomeRuntimeError        ExecutionOutcome = "runtime_error"
+	OutcomeWrongAnswer         ExecutionOutcome = "wrong_answer"
+	OutcomeTimeLimitExceeded   ExecutionOutcome = "time_limit_exceeded"
+	OutcomeMemoryLimitExceeded ExecutionOutcome = "memory_limit_exceeded"
+	OutcomeOutputLimitExceeded ExecutionOutcome = "output_limit_exceeded"
+	OutcomePartialPass         ExecutionOutcome = "partial_pass"
+	OutcomeInternalError       ExecutionOutcome = "internal_error"
+)
+
+// Códigos de salida de un proceso terminado por una señal (128 + número de señal)
+const (
+	exitCodeSignalBase = 128
+	exitCodeSIGKILL    = 137 // El OOM killer termina el proceso al superar el límite de memoria
+	exitCodeSIGXCPU    = 152 // Límite de tiempo de CPU
+	exitCodeSIGXFSZ    = 153 // Límite de tamaño de salida
+)
+
+// executionOutcomeAliases son las abreviaturas habituales de los jueces
+var executionOutcomeAliases = map[string]ExecutionOutcome{
+	"ac":                OutcomeAccepted,
+	"ok":                OutcomeAccepted,
+	"ce":                OutcomeCompileError,
+	"compilation_error": OutcomeCompileError,
+	"re":                OutcomeRuntimeError,
+	"rte":               OutcomeRuntimeError,
+	"wa":                OutcomeWrongAnswer,
+	"tle":               OutcomeTimeLimitExceeded,
+	"time_limit":        OutcomeTimeLimitExceeded,
+	"mle":               OutcomeMemoryLimitExceeded,
+	"memory_limit":      OutcomeMemoryLimitExceeded,
+	"ole":               OutcomeOutputLimitExceeded,
+	"output_limit":      OutcomeOutputLimitExceeded,
+	"partial":           OutcomePartialPass,
+	"ie":                OutcomeInternalError,
+	"judge_error":       OutcomeInternalError,
+}
+
+// ExecutionOutcomes retorna todos los resultados posibles, en el orden en que se reportan
+func ExecutionOutcomes() []ExecutionOutcome {
+	return []ExecutionOutcome{
+		OutcomeAccepted,
+		OutcomeCompileError,
+		OutcomeRuntimeError,
+		OutcomeWrongAnswer,
+		OutcomeTimeLimitExceeded,
+		OutcomeMemoryLimitExceeded,
+		OutcomeOutputLimitExceeded,
+		OutcomePartialPass,
+		OutcomeInternalError,
+	}
+}
+
+// NewExecutionOutcome crea y valida un ExecutionOutcome. Acepta las abreviaturas de los jueces (AC, CE, WA, TLE...).
+func NewExecutionOutcome(value string) (ExecutionOutcome, error) {
+	normalized := strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(value)))
+	if outcome, ok := executionOutcomeAliases[normalized]; ok {
+		return outcome, nil
+	}
+
+	for _, outcome := range ExecutionOutcomes() {
+		if ExecutionOutcome(normalized) == outcome {
+			return outcome, nil
+		}
+	}
+	return "", errors.New("invalid execution outcome")
+}
+
+// DeriveExecutionOutcome infiere el resultado de una ejecución cuando el juez no lo envía:
+//   - exitosa: accepted
+//   - estado timeout o SIGXCPU: time_limit_exceeded; SIGKILL: memory_limit_exceeded; SIGXFSZ: output_limit_exceeded
+//   - sin tests ejecutados y con estado error o código de salida distinto de 0: compile_error
+//   - estado error o terminada por otra señal (segfault, abort): runtime_error
+//   - con algún test aprobado: partial_pass; si no, wrong_answer
+//
+// internal_error solo se asigna si el juez lo envía.
+func DeriveExecutionOutcome(status ExecutionStatus, exitCode, totalTests, passedTests int, success bool) ExecutionOutcome {
+	switch {
+	case success:
+		return OutcomeAccepted
+	case status == StatusTimeout || exitCode == exitCodeSIGXCPU:
+		return OutcomeTimeLimitExceeded
+	case exitCode == exitCodeSIGKILL:
+		return OutcomeMemoryLimitExceeded
+	case exitCode == exitCodeSIGXFSZ:
+		return OutcomeOutputLimitExceeded
+	case totalTests == 0 && (status == StatusError || exitCode != 0):
+		return OutcomeCompileError
+	case status == StatusError || exitCode > exitCodeSignalBase:
+		return OutcomeRuntimeError
+	case passedTests > 0 && passedTests < totalTests:
+		return OutcomePartialPass
+	default:
+		return OutcomeWrongAnswer
+	}
+}
+
+// String implementa Stringer
+func (o ExecutionOutcome) String() string {
+	return string(o)
+}
+
+// Value retorna el valor del ExecutionOutcome
+func (o ExecutionOutcome) Value() string {
+	return string(o)
+}
+
+// IsAccepted indica si la ejecución resolvió el challenge
+func (o ExecutionOutcome) IsAccepted() bool {
+	return o == OutcomeAccepted
+}
@@ -0,0 +1,83 @@
+package valueobjects
+
+import "testing"
+
+func TestDeriveExecutionOutcome(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      ExecutionStatus
+		exitCode    int
+		totalTests  int
+		passedTests int
+		success     bool
+		want        ExecutionOutcome
+	}{
+		{"success", StatusCompleted, 0, 3, 3, true, OutcomeAccepted},
+		{"timeout status", StatusTimeout, 0, 3, 1, false, OutcomeTimeLimitExceeded},
+		{"SIGXCPU", StatusFailed, 152, 3, 0, false, OutcomeTimeLimitExceeded},
+		{"SIGKILL", StatusFailed, 137, 3, 0, false, OutcomeMemoryLimitExceeded},
+		{"SIGXFSZ", StatusFailed, 153, 3, 0, false, OutcomeOutputLimitExceeded},
+		{"SIGKILL without tests is not a compile error", StatusError, 137, 0, 0, false, OutcomeMemoryLimitExceeded},
+		{"error without tests", StatusError, 0, 0, 0, false, OutcomeCompileError},
+		{"exit code without tests", StatusFailed, 1, 0, 0, false, OutcomeCompileError},
+		{"segfault without tests is a compile error", StatusFailed, 139, 0, 0, false, OutcomeCompileError},
+		{"error with tests", StatusError, 0, 3, 1, false, OutcomeRuntimeError},
+		{"segfault with tests", StatusFailed, 139, 3, 1, false, OutcomeRuntimeError},
+		{"abort with tests", StatusFailed, 134, 3, 0, false, OutcomeRuntimeError},
+		{"some tests passed", StatusFailed, 1, 3, 2, false, OutcomePartialPass},
+		{"no tests passed", StatusFailed, 1, 3, 0, false, OutcomeWrongAnswer},
+		{"all tests passed without success", StatusCompleted, 0, 3, 3, false, OutcomeWrongAnswer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DeriveExecutionOutcome(tt.status, tt.exitCode, tt.totalTests, tt.passedTests, tt.success)
+			if got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewExecutionOutcome(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ExecutionOutcome
+		invalid bool
+	}{
+		{value: "accepted", want: OutcomeAccepted},
+		{value: "AC", want: OutcomeAccepted},
+		{value: " ok ", want: OutcomeAccepted},
+		{value: "CE", want: OutcomeCompileError},
+		{value: "Compilation Error", want: OutcomeCompileError},
+		{value: "RTE", want: OutcomeRuntimeError},
+		{value: "wa", want: OutcomeWrongAnswer},
+		{value: "TLE", want: OutcomeTimeLimitExceeded},
+		{value: "time-limit", want: OutcomeTimeLimitExceeded},
+		{value: "MLE", want: OutcomeMemoryLimitExceeded},
+		{value: "Output Limit Exceeded", want: OutcomeOutputLimitExceeded},
+		{value: "partial", want: OutcomePartialPass},
+		{value: "judge-error", want: OutcomeInternalError},
+		{value: "internal_error", want: OutcomeInternalError},
+		{value: "", invalid: true},
+		{value: "pending", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NewExecutionOutcome(tt.value)
+			if tt.invalid {
+				if err == nil {
+					t.Fatalf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewExecutionOutcome(%q): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
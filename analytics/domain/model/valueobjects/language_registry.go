@@ -0,0 +1,131 @@
+package valueobjects
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// versionedLanguagePattern separa un lenguaje con versión pegada, por ejemplo: java17, go1.22, node-20, python 3.12
+var versionedLanguagePattern = regexp.MustCompile(`^(.*?)[\s_@:-]*v?(\d+(?:\.\d+)*)$`)
+
+// LanguageDefinition describe un lenguaje del registro
+type LanguageDefinition struct {
+	Name    string   // Nombre canónico con el que se guardan las ejecuciones
+	Aliases []string // Valores que envía el ejecutor para el lenguaje (py, python3, c++...)
+	Family  string   // Familia para agrupar lenguajes (jvm, c, python...)
+	Version string   // Versión del runtime por defecto (opcional)
+}
+
+// DefaultLanguageDefinitions retorna los lenguajes predefinidos del registro
+func DefaultLanguageDefinitions() []LanguageDefinition {
+	return []LanguageDefinition{
+		{Name: "c", Aliases: []string{"c"}, Family: "c"},
+		{Name: "cpp", Aliases: []string{"cpp", "c++", "cxx", "cc"}, Family: "c"},
+		{Name: "csharp", Aliases: []string{"csharp", "c#", "cs", "dotnet"}, Family: "dotnet"},
+		{Name: "java", Aliases: []string{"java"}, Family: "jvm"},
+		{Name: "kotlin", Aliases: []string{"kotlin", "kt"}, Family: "jvm"},
+		{Name: "python", Aliases: []string{"python", "py", "python3", "py3"}, Family: "python"},
+		{Name: "javascript", Aliases: []string{"javascript", "js", "node", "nodejs"}, Family: "javascript"},
+		{Name: "typescript", Aliases: []string{"typescript", "ts"}, Family: "javascript"},
+		{Name: "go", Aliases: []string{"go", "golang"}, Family: "go"},
+		{Name: "rust", Aliases: []string{"rust", "rs"}, Family: "rust"},
+	}
+}
+
+// MergeLanguageDefinitions agrega overrides a base. Un lenguaje de overrides con el mismo nombre que
+// uno de base lo reemplaza.
+func MergeLanguageDefinitions(base, overrides []LanguageDefinition) []LanguageDefinition {
+	merged := make([]LanguageDefinition, 0, len(base)+len(overrides))
+	replaced := make(map[string]bool, len(overrides))
+	for _, definition := range overrides {
+		replaced[normalizeLanguage(definition.Name)] = true
+	}
+	for _, definition := range base {
+		if !replaced[normalizeLanguage(definition.Name)] {
+			merged = append(merged, definition)
+		}
+	}
+	return append(merged, overrides...)
+}
+
+// LanguageRegistry normaliza los lenguajes que envía el ejecutor a su nombre canónico
+type LanguageRegistry struct {
+	definitions []LanguageDefinition
+	byAlias     map[string]int
+}
+
+// NewLanguageRegistry crea y valida un registro de lenguajes. El nombre canónico es también un alias
+// y un alias no puede pertenecer a dos lenguajes.
+func NewLanguageRegistry(definitions []LanguageDefinition) (*LanguageRegistry, error) {
+	registry := &LanguageRegistry{
+		definitions: make([]LanguageDefinition, 0, len(definitions)),
+		byAlias:     make(map[string]int),
+	}
+
+	for _, definition := range definitions {
+		definition.Name = normalizeLanguage(definition.Name)
+		if definition.Name == "" {
+			return nil, errors.New("language name is required")
+		}
+		if definition.Name == LanguageOther {
+			return nil, fmt.Errorf("language name %q is reserved", LanguageOther)
+		}
+
+		index := len(registry.definitions)
+		aliases := make([]string, 0, len(definition.Aliases)+1)
+		for _, alias := range append([]string{definition.Name}, definition.Aliases...) {
+			alias = normalizeLanguage(alias)
+			if alias == "" {
+				continue
+			}
+			if other, ok := registry.byAlias[alias]; ok {
+				if other == index {
+					continue
+				}
+				return nil, fmt.Errorf("language alias %q is used by %s and %s", alias, registry.definitions[other].Name, definition.Name)
+			}
+			registry.byAlias[alias] = index
+			aliases = append(aliases, alias)
+		}
+		definition.Aliases = aliases
+		registry.definitions = append(registry.definitions, definition)
+	}
+
+	return registry, nil
+}
+
+// Resolve normaliza el lenguaje recibido. Los valores fuera del registro se guardan como LanguageOther
+// con el valor original; una versión pegada al alias (java17, go1.22) se usa como versión del runtime.
+func (r *LanguageRegistry) Resolve(raw string) (ProgrammingLanguage, error) {
+	raw = strings.TrimSpace(raw)
+	value := normalizeLanguage(raw)
+	if value == "" {
+		return ProgrammingLanguage{}, errors.New("programming language is required")
+	}
+
+	if index, ok := r.byAlias[value]; ok {
+		definition := r.definitions[index]
+		return RestoreProgrammingLanguage(definition.Name, definition.Family, definition.Version, raw), nil
+	}
+
+	if match := versionedLanguagePattern.FindStringSubmatch(value); match != nil {
+		if index, ok := r.byAlias[match[1]]; ok {
+			definition := r.definitions[index]
+			return RestoreProgrammingLanguage(definition.Name, definition.Family, match[2], raw), nil
+		}
+	}
+
+	return RestoreProgrammingLanguage(LanguageOther, "", "", raw), nil
+}
+
+// Definitions retorna los lenguajes del registro
+func (r *LanguageRegistry) Definitions() []LanguageDefinition {
+	return r.definitions
+}
+
+// normalizeLanguage lleva un nombre o alias a minúsculas sin espacios en los extremos
+func normalizeLanguage(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
@@ -1,37 +1,55 @@
 package valueobjects
 
-import "errors"
-
-// ProgrammingLanguage representa los lenguajes de programación soportados
-type ProgrammingLanguage string
-
-const (
-	LanguageCpp        ProgrammingLanguage = "cpp"
-	LanguageJava       ProgrammingLanguage = "java"
-	LanguagePython     ProgrammingLanguage = "python"
-	LanguageJavaScript ProgrammingLanguage = "javascript"
-	LanguageGo         ProgrammingLanguage = "go"
-	LanguageRust       ProgrammingLanguage = "rust"
-)
-
-// NewProgrammingLanguage crea y valida un ProgrammingLanguage
-func NewProgrammingLanguage(value string) (ProgrammingLanguage, error) {
-	lang := ProgrammingLanguage(value)
-
-	switch lang {
-	case LanguageCpp, LanguageJava, LanguagePython, LanguageJavaScript, LanguageGo, LanguageRust:
-		return lang, nil
-	default:
-		return "", errors.New("invalid programming language")
+// LanguageOther es el nombre canónico de los lenguajes que no están en el registro
+const LanguageOther = "other"
+
+// ProgrammingLanguage representa el lenguaje de una ejecución normalizado con el registro de lenguajes
+type ProgrammingLanguage struct {
+	name    string // Nombre canónico, LanguageOther si no está en el registro
+	family  string // Familia del lenguaje (jvm, c, python...), vacía para LanguageOther
+	version string // Versión del runtime, vacía si no se conoce
+	raw     string // Valor recibido en el evento
+}
+
+// RestoreProgrammingLanguage reconstruye un ProgrammingLanguage ya normalizado (desde persistencia)
+func RestoreProgrammingLanguage(name, family, version, raw string) ProgrammingLanguage {
+	if raw == "" {
+		raw = name
+	}
+	return ProgrammingLanguage{
+		name:    name,
+		family:  family,
+		version: version,
+		raw:     raw,
 	}
 }
 
 // String implementa Stringer
 func (p ProgrammingLanguage) String() string {
-	return string(p)
+	return p.name
 }
 
-// Value retorna el valor del ProgrammingLanguage
+// Value retorna el nombre canónico del lenguaje
 func (p ProgrammingLanguage) Value() string {
-	return string(p)
+	return p.name
+}
+
+// Family retorna la familia del lenguaje
+func (p ProgrammingLanguage) Family() string {
+	return p.family
+}
+
+// Version retorna la versión del runtime
+func (p ProgrammingLanguage) Version() string {
+	return p.version
+}
+
+// Raw retorna el valor recibido en el evento
+func (p ProgrammingLanguage) Raw() string {
+	return p.raw
+}
+
+// IsRecognized indica si el lenguaje está en el registro
+func (p ProgrammingLanguage) IsRecognized() bool {
+	return p.name != LanguageOther
 }
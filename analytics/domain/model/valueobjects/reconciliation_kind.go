@@ -0,0 +1,35 @@
+package valueobjects
+
+import "errors"
+
+// ReconciliationKind representa el tipo de diferencia encontrada entre un tópico y la base de datos
+type ReconciliationKind string
+
+const (
+	ReconciliationMissing  ReconciliationKind = "missing"  // Evento sin registro en la base de datos
+	ReconciliationOrphaned ReconciliationKind = "orphaned" // Registro sin evento en el tópico
+	ReconciliationInvalid  ReconciliationKind = "invalid"  // Evento que no pasa la validación de dominio
+	ReconciliationUnread   ReconciliationKind = "unread"   // Rango de offsets que el job no llegó a leer
+)
+
+// NewReconciliationKind crea y valida un ReconciliationKind
+func NewReconciliationKind(value string) (ReconciliationKind, error) {
+	kind := ReconciliationKind(value)
+
+	switch kind {
+	case ReconciliationMissing, ReconciliationOrphaned, ReconciliationInvalid, ReconciliationUnread:
+		return kind, nil
+	default:
+		return "", errors.New("invalid reconciliation kind")
+	}
+}
+
+// String implementa Stringer
+func (k ReconciliationKind) String() string {
+	return string(k)
+}
+
+// Value retorna el valor del ReconciliationKind
+func (k ReconciliationKind) Value() string {
+	return string(k)
+}
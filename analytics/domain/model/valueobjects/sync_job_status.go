@@ -0,0 +1,41 @@
+package valueobjects
+
+import "errors"
+
+// SyncJobStatus representa el estado de un job de sincronización
+type SyncJobStatus string
+
+const (
+	SyncJobPending   SyncJobStatus = "pending"
+	SyncJobRunning   SyncJobStatus = "running"
+	SyncJobCompleted SyncJobStatus = "completed"
+	SyncJobFailed    SyncJobStatus = "failed"
+	SyncJobCancelled SyncJobStatus = "cancelled"
+)
+
+// NewSyncJobStatus crea y valida un SyncJobStatus
+func NewSyncJobStatus(value string) (SyncJobStatus, error) {
+	status := SyncJobStatus(value)
+
+	switch status {
+	case SyncJobPending, SyncJobRunning, SyncJobCompleted, SyncJobFailed, SyncJobCancelled:
+		return status, nil
+	default:
+		return "", errors.New("invalid sync job status")
+	}
+}
+
+// String implementa Stringer
+func (s SyncJobStatus) String() string {
+	return string(s)
+}
+
+// Value retorna el valor del SyncJobStatus
+func (s SyncJobStatus) Value() string {
+	return string(s)
+}
+
+// IsActive indica si el job está pendiente o en ejecución
+func (s SyncJobStatus) IsActive() bool {
+	return s == SyncJobPending || s == SyncJobRunning
+}
@@ -0,0 +1,35 @@
+package valueobjects
+
+import "errors"
+
+// SyncTarget identifica los datos que sincroniza un job (y el tópico del que se leen)
+type SyncTarget string
+
+const (
+	SyncTargetExecutionAnalytics SyncTarget = "execution_analytics"
+	SyncTargetUserRegistration   SyncTarget = "user_registration"
+	SyncTargetChallenge          SyncTarget = "challenge"
+	SyncTargetCodeVersion        SyncTarget = "code_version"
+)
+
+// NewSyncTarget crea y valida un SyncTarget
+func NewSyncTarget(value string) (SyncTarget, error) {
+	target := SyncTarget(value)
+
+	switch target {
+	case SyncTargetExecutionAnalytics, SyncTargetUserRegistration, SyncTargetChallenge, SyncTargetCodeVersion:
+		return target, nil
+	default:
+		return "", errors.New("invalid sync target")
+	}
+}
+
+// String implementa Stringer
+func (s SyncTarget) String() string {
+	return string(s)
+}
+
+// Value retorna el valor del SyncTarget
+func (s SyncTarget) Value() string {
+	return string(s)
+}
@@ -0,0 +1,43 @@
+package valueobjects
+
+import (
+	"errors"
+	"strings"
+)
+
+// TestVisibility indica si el estudiante ve el test antes de enviar su solución
+type TestVisibility string
+
+const (
+	TestVisibilityPublic TestVisibility = "public"
+	TestVisibilityHidden TestVisibility = "hidden"
+)
+
+// NewTestVisibility crea y valida un TestVisibility. Sin valor el test es público.
+func NewTestVisibility(value string) (TestVisibility, error) {
+	visibility := TestVisibility(strings.ToLower(strings.TrimSpace(value)))
+
+	switch visibility {
+	case "":
+		return TestVisibilityPublic, nil
+	case TestVisibilityPublic, TestVisibilityHidden:
+		return visibility, nil
+	default:
+		return "", errors.New("invalid test visibility")
+	}
+}
+
+// String implementa Stringer
+func (v TestVisibility) String() string {
+	return string(v)
+}
+
+// Value retorna el valor del TestVisibility
+func (v TestVisibility) Value() string {
+	return string(v)
+}
+
+// IsHidden indica si el test es oculto
+func (v TestVisibility) IsHidden() bool {
+	return v == TestVisibilityHidden
+}
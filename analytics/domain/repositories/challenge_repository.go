@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"context"
+)
+
+// ChallengeRepository define el contrato para el repositorio del catálogo de challenges
+type ChallengeRepository interface {
+	// Save aplica un cambio del catálogo. Los atributos y el estado se versionan por separado con la fecha
+	// del evento: un evento más antiguo que el último aplicado no los modifica. Retorna si aplicó algún cambio.
+	Save(ctx context.Context, challenge *aggregates.Challenge) (bool, error)
+
+	// FindByChallengeID busca un challenge del catálogo
+	FindByChallengeID(ctx context.Context, challengeID valueobjects.ChallengeID) (*aggregates.Challenge, error)
+}
+
+// ChallengeFilter filtra los KPIs de ejecuciones por los atributos del challenge. Con algún filtro,
+// las ejecuciones de challenges que no están en el catálogo quedan fuera.
+type ChallengeFilter struct {
+	Difficulty string
+	CourseID   string
+	Tag        string
+	Archived   *bool // nil = activos y archivados
+}
+
+// IsEmpty indica si el filtro no restringe ningún atributo
+func (f ChallengeFilter) IsEmpty() bool {
+	return f.Difficulty == "" && f.CourseID == "" && f.Tag == "" && f.Archived == nil
+}
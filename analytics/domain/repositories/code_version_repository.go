@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"context"
+	"time"
+)
+
+// CodeVersionRepository define el contrato para el repositorio de versiones de código
+type CodeVersionRepository interface {
+	// Save guarda una versión de código. Las versiones no cambian: si ya existe no la modifica y retorna false.
+	Save(ctx context.Context, version *aggregates.CodeVersion) (bool, error)
+
+	// Exists indica si la versión de código ya está guardada
+	Exists(ctx context.Context, codeVersionID valueobjects.CodeVersionID) (bool, error)
+
+	// GetTimeline obtiene las versiones de un estudiante en un challenge con el resultado de sus ejecuciones,
+	// ordenadas por fecha. Incluye las versiones ejecutadas de las que no se recibió el evento.
+	GetTimeline(ctx context.Context, studentID valueobjects.StudentID, challengeID valueobjects.ChallengeID) ([]CodeVersionTimelineEntry, error)
+}
+
+// CodeVersionTimelineEntry representa una versión de código y su última ejecución
+type CodeVersionTimelineEntry struct {
+	CodeVersionID   string
+	VersionNumber   int    // 0 = no se recibió el evento de la versión
+	ParentVersionID string // Vacío en la primera versión
+	LinesChanged    int
+	CreatedAt       *time.Time // nil = no se recibió el evento de la versión
+	Executions      int64      // 0 = versión sin ejecuciones; los campos siguientes no aplican
+	FirstExecutedAt *time.Time
+	LastExecutedAt  *time.Time
+	PassedTests     int
+	TotalTests      int
+	ExitCode        int
+	ExecutionTimeMs int64
+	Success         bool
+}
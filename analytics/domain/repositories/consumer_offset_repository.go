@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+)
+
+// ConsumerOffsetRepository define el contrato para el repositorio de offsets de Kafka guardados en la base de datos
+type ConsumerOffsetRepository interface {
+	// Save guarda el offset de una partición
+	Save(ctx context.Context, offset ConsumerOffset) error
+
+	// FindByGroupAndTopic retorna los offsets guardados de un tópico, por partición
+	FindByGroupAndTopic(ctx context.Context, groupID, topic string) (map[int32]int64, error)
+}
+
+// ConsumerOffset es la posición de un consumer group en una partición.
+// Offset es el del siguiente mensaje a leer, como en los commits de Kafka.
+type ConsumerOffset struct {
+	GroupID   string
+	Topic     string
+	Partition int32
+	Offset    int64
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+)
+
+// sqlStateError es implementado por los errores de PostgreSQL (pgconn.PgError)
+type sqlStateError interface {
+	SQLState() string
+}
+
+// IsDataError indica si un error de un repositorio se debe a los datos recibidos y no se resolverá
+// reintentando: errores de datos (clase 22) y de integridad (clase 23) de PostgreSQL
+func IsDataError(err error) bool {
+	var pgErr sqlStateError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	state := pgErr.SQLState()
+	return strings.HasPrefix(state, "22") || strings.HasPrefix(state, "23")
+}
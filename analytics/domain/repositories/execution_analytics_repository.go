@@ -9,12 +9,24 @@ import (
 
 // ExecutionAnalyticsRepository define el contrato para el repositorio
 type ExecutionAnalyticsRepository interface {
-	// Save guarda o actualiza un ExecutionAnalytics
-	Save(ctx context.Context, execution *aggregates.ExecutionAnalytics) error
+	// Save guarda o actualiza un ExecutionAnalytics como SaveBatch
+	Save(ctx context.Context, execution *aggregates.ExecutionAnalytics) (SaveResult, error)
+
+	// SaveBatch guarda un lote de ExecutionAnalytics en una sola transacción. Las ejecuciones existentes se
+	// reemplazan, junto con sus test results, solo si la versión recibida es más reciente (ver Supersedes);
+	// la versión reemplazada se guarda en el historial.
+	SaveBatch(ctx context.Context, executions []*aggregates.ExecutionAnalytics) (SaveResult, error)
+
+	// SaveBatchWithOffset guarda un lote como SaveBatch y, en la misma transacción, el offset del consumidor.
+	// Si la transacción falla no se guarda ninguno de los dos.
+	SaveBatchWithOffset(ctx context.Context, executions []*aggregates.ExecutionAnalytics, offset ConsumerOffset) (SaveResult, error)
 
 	// FindByExecutionID busca por ID de ejecución
 	FindByExecutionID(ctx context.Context, executionID valueobjects.ExecutionID) (*aggregates.ExecutionAnalytics, error)
 
+	// FindHistoryByExecutionID obtiene las versiones reemplazadas de una ejecución, de la más antigua a la más reciente
+	FindHistoryByExecutionID(ctx context.Context, executionID valueobjects.ExecutionID) ([]ExecutionRevision, error)
+
 	// FindByStudentID busca todas las ejecuciones de un estudiante
 	FindByStudentID(ctx context.Context, studentID valueobjects.StudentID, limit, offset int) ([]*aggregates.ExecutionAnalytics, error)
 
@@ -33,20 +45,121 @@ type ExecutionAnalyticsRepository interface {
 	// GetSuccessRateByStudent obtiene tasa de éxito por estudiante
 	GetSuccessRateByStudent(ctx context.Context, studentID valueobjects.StudentID) (float64, error)
 
+	// GetOutcomeBreakdownByStudent cuenta las ejecuciones de un estudiante por resultado
+	GetOutcomeBreakdownByStudent(ctx context.Context, studentID valueobjects.StudentID) (OutcomeBreakdown, error)
+
+	// GetOutcomeBreakdownByChallenge cuenta las ejecuciones de un challenge por resultado
+	GetOutcomeBreakdownByChallenge(ctx context.Context, challengeID valueobjects.ChallengeID) (OutcomeBreakdown, error)
+
+	// GetAverageScoreByStudent obtiene el puntaje ponderado promedio de un estudiante
+	GetAverageScoreByStudent(ctx context.Context, studentID valueobjects.StudentID) (float64, error)
+
+	// GetAverageScoreByChallenge obtiene el puntaje ponderado promedio de un challenge
+	GetAverageScoreByChallenge(ctx context.Context, challengeID valueobjects.ChallengeID) (float64, error)
+
+	// GetTestStatsByChallenge obtiene las estadísticas de cada test de un challenge
+	GetTestStatsByChallenge(ctx context.Context, challengeID valueobjects.ChallengeID) ([]TestStats, error)
+
 	// GetSuccessRateByChallenge obtiene tasa de éxito por challenge
 	GetSuccessRateByChallenge(ctx context.Context, challengeID valueobjects.ChallengeID) (float64, error)
 
 	// GetAverageExecutionTimeByChallenge obtiene tiempo promedio de ejecución por challenge
 	GetAverageExecutionTimeByChallenge(ctx context.Context, challengeID valueobjects.ChallengeID) (float64, error)
 
-	// GetDailyExecutionStats obtiene estadísticas diarias de ejecuciones
-	GetDailyExecutionStats(ctx context.Context, startDate, endDate time.Time) ([]DailyStats, error)
+	// GetDailyExecutionStats obtiene estadísticas diarias de ejecuciones de los challenges que cumplen el filtro
+	GetDailyExecutionStats(ctx context.Context, startDate, endDate time.Time, filter ChallengeFilter) ([]DailyStats, error)
+
+	// GetLanguageUsageStats obtiene estadísticas de uso de lenguajes de los challenges que cumplen el filtro
+	GetLanguageUsageStats(ctx context.Context, startDate, endDate time.Time, filter ChallengeFilter) ([]LanguageStats, error)
+
+	// GetUnrecognizedLanguageStats obtiene los lenguajes recibidos que no están en el registro, por valor recibido
+	GetUnrecognizedLanguageStats(ctx context.Context, startDate, endDate time.Time) ([]UnrecognizedLanguageStats, error)
+
+	// GetDifficultyStats obtiene estadísticas por dificultad de los challenges del catálogo que cumplen el filtro
+	GetDifficultyStats(ctx context.Context, startDate, endDate time.Time, filter ChallengeFilter) ([]DifficultyStats, error)
+
+	// GetTopFailedChallenges obtiene los challenges con más fallos, con sus atributos del catálogo
+	GetTopFailedChallenges(ctx context.Context, limit int, filter ChallengeFilter) ([]ChallengeStats, error)
+}
+
+// SaveResult resume cómo se aplicó un lote de ejecuciones
+type SaveResult struct {
+	Inserted int // Ejecuciones nuevas
+	Updated  int // Ejecuciones existentes reemplazadas por una versión más reciente
+	Stale    int // Versiones iguales o anteriores a la guardada: duplicados o actualizaciones desordenadas
+
+	// Cómo se aplicó cada ejecución del lote, en el orden recibido
+	Outcomes []SaveOutcome
+}
+
+// SaveOutcome es cómo se aplicó una ejecución de un lote
+type SaveOutcome string
 
-	// GetLanguageUsageStats obtiene estadísticas de uso de lenguajes
-	GetLanguageUsageStats(ctx context.Context, startDate, endDate time.Time) ([]LanguageStats, error)
+const (
+	SaveOutcomeInserted SaveOutcome = "inserted"
+	SaveOutcomeUpdated  SaveOutcome = "updated"
+	SaveOutcomeStale    SaveOutcome = "stale"
+)
+
+// ExecutionRevision es una versión reemplazada de una ejecución, guardada para auditoría
+type ExecutionRevision struct {
+	EventID           string
+	Revision          int64
+	JudgedAt          time.Time
+	Status            string
+	ExecutionTimeMs   int64
+	ExitCode          int
+	TotalTests        int
+	PassedTests       int
+	FailedTests       int
+	Success           bool
+	Outcome           string
+	Score             *float64 // nil en versiones guardadas antes del puntaje ponderado
+	TestResults       []ExecutionRevisionTest
+	ReplacedByEventID string
+	ReplacedAt        time.Time
+}
+
+// ExecutionRevisionTest es un test result de una versión reemplazada
+type ExecutionRevisionTest struct {
+	TestID          string   `json:"test_id"`
+	TestName        string   `json:"test_name"`
+	Passed          bool     `json:"passed"`
+	ErrorMessage    string   `json:"error_message,omitempty"`
+	DurationMs      *int64   `json:"duration_ms,omitempty"`
+	Visibility      string   `json:"visibility,omitempty"`
+	Weight          *float64 `json:"weight,omitempty"` // nil en versiones guardadas antes de los pesos
+	ExpectedOutput  string   `json:"expected_output,omitempty"`
+	ActualOutput    string   `json:"actual_output,omitempty"`
+	OutputTruncated bool     `json:"output_truncated,omitempty"`
+}
 
-	// GetTopFailedChallenges obtiene los challenges con más fallos
-	GetTopFailedChallenges(ctx context.Context, limit int) ([]ChallengeStats, error)
+// OutcomeBreakdown cuenta ejecuciones por resultado (valueobjects.ExecutionOutcome)
+type OutcomeBreakdown struct {
+	Accepted            int64
+	CompileError        int64
+	RuntimeError        int64
+	WrongAnswer         int64
+	TimeLimitExceeded   int64
+	MemoryLimitExceeded int64
+	OutputLimitExceeded int64
+	PartialPass         int64
+	InternalError       int64
+}
+
+// ByOutcome retorna el conteo de cada resultado
+func (b OutcomeBreakdown) ByOutcome() map[valueobjects.ExecutionOutcome]int64 {
+	return map[valueobjects.ExecutionOutcome]int64{
+		valueobjects.OutcomeAccepted:            b.Accepted,
+		valueobjects.OutcomeCompileError:        b.CompileError,
+		valueobjects.OutcomeRuntimeError:        b.RuntimeError,
+		valueobjects.OutcomeWrongAnswer:         b.WrongAnswer,
+		valueobjects.OutcomeTimeLimitExceeded:   b.TimeLimitExceeded,
+		valueobjects.OutcomeMemoryLimitExceeded: b.MemoryLimitExceeded,
+		valueobjects.OutcomeOutputLimitExceeded: b.OutputLimitExceeded,
+		valueobjects.OutcomePartialPass:         b.PartialPass,
+		valueobjects.OutcomeInternalError:       b.InternalError,
+	}
 }
 
 // DailyStats representa estadísticas diarias
@@ -56,19 +169,59 @@ type DailyStats struct {
 	SuccessfulExecs int64
 	FailedExecs     int64
 	AvgExecTime     float64
+	OutcomeBreakdown
 }
 
 // LanguageStats representa estadísticas por lenguaje
 type LanguageStats struct {
 	Language        string
+	Family          string
 	TotalExecutions int64
 	SuccessRate     float64
+	OutcomeBreakdown
 }
 
-// ChallengeStats representa estadísticas por challenge
+// UnrecognizedLanguageStats representa las ejecuciones de un lenguaje fuera del registro
+type UnrecognizedLanguageStats struct {
+	Raw             string
+	TotalExecutions int64
+	LastSeenAt      time.Time
+}
+
+// ChallengeStats representa estadísticas por challenge. Los atributos del catálogo quedan vacíos
+// si el challenge aún no se recibió del servicio de challenges.
 type ChallengeStats struct {
 	ChallengeID     string
+	Title           string
+	Difficulty      string
+	CourseID        string
+	Tags            []string
+	Archived        bool
+	TotalExecutions int64
+	SuccessRate     float64
+	AvgExecTime     float64
+	OutcomeBreakdown
+}
+
+// TestStats representa estadísticas de un test de un challenge. FailedWithPublicPassed cuenta las
+// ejecuciones en las que el test, si es oculto, falló aunque pasaron todos los tests públicos.
+type TestStats struct {
+	TestID                 string
+	TestName               string
+	Visibility             string
+	Weight                 float64
+	Executions             int64
+	PassRate               float64
+	AvgDurationMs          *float64 // nil si el juez no informa la duración del test
+	FailedWithPublicPassed int64
+}
+
+// DifficultyStats representa estadísticas por dificultad de challenge
+type DifficultyStats struct {
+	Difficulty      string
+	Challenges      int64
 	TotalExecutions int64
 	SuccessRate     float64
 	AvgExecTime     float64
+	OutcomeBreakdown
 }
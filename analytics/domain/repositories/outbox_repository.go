@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// OutboxRepository define el contrato para el outbox de eventos de dominio. Los eventos se escriben en la
+// misma transacción que los datos que los originan y un relay los publica después.
+type OutboxRepository interface {
+	// PublishPending toma hasta limit eventos pendientes en orden de creación y llama a publish con cada uno.
+	// Los publicados se marcan como tales; ante el primer error se registra el intento y se detiene para no
+	// alterar el orden, salvo que el evento llegue a maxAttempts intentos (0 = sin límite): entonces se aparta
+	// como fallido y se sigue con los siguientes. Retorna los publicados y los apartados en la pasada.
+	// Solo una instancia publica a la vez: si otra tiene el lote, retorna 0 sin error.
+	PublishPending(ctx context.Context, limit int, maxAttempts int, publish func(event *OutboxEvent) error) (OutboxPublishResult, error)
+
+	// GetStats obtiene la cantidad de eventos pendientes y apartados como fallidos
+	GetStats(ctx context.Context) (*OutboxStats, error)
+
+	// DeletePublishedBefore elimina los eventos publicados antes de la fecha indicada
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// OutboxEvent es un evento de dominio pendiente o ya publicado
+type OutboxEvent struct {
+	ID          uint
+	EventID     string
+	Type        string
+	Key         string
+	Payload     []byte // Datos del evento en JSON
+	OccurredAt  time.Time
+	CreatedAt   time.Time
+	PublishedAt *time.Time
+	Attempts    int
+	LastError   string
+	FailedAt    *time.Time // Apartado tras agotar los intentos (nil = pendiente o publicado)
+}
+
+// OutboxPublishResult es el resultado de una pasada de publicación
+type OutboxPublishResult struct {
+	Published int
+	Failed    int // Eventos apartados por agotar los intentos
+}
+
+// OutboxStats es el estado del outbox
+type OutboxStats struct {
+	Pending         int64
+	Failed          int64
+	OldestPendingAt *time.Time
+	OldestFailedAt  *time.Time
+}
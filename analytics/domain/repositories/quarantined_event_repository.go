@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// QuarantinedEventRepository define el contrato para el repositorio de eventos en cuarentena
+type QuarantinedEventRepository interface {
+	// Save guarda un evento que no pudo ser procesado
+	Save(ctx context.Context, event *QuarantinedEvent) error
+}
+
+// QuarantinedEvent representa un mensaje de Kafka que no pudo ser procesado
+type QuarantinedEvent struct {
+	ID              uint
+	Topic           string
+	Partition       int32
+	Offset          int64
+	Key             string
+	Payload         []byte
+	Error           string
+	Attempts        int
+	DeadLetterTopic string
+	QuarantinedAt   time.Time
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"context"
+)
+
+// ReconciliationRepository define el contrato para el repositorio de reportes de reconciliación de los jobs de sincronización
+type ReconciliationRepository interface {
+	// SaveItems guarda las diferencias encontradas por un job. Las ya guardadas (mismo mensaje) se ignoran.
+	SaveItems(ctx context.Context, jobID string, items []*entities.ReconciliationItem) error
+
+	// SaveSeenKeys registra los IDs de negocio de los eventos leídos del tópico por un job
+	SaveSeenKeys(ctx context.Context, jobID string, keys []string) error
+
+	// RecordOrphans guarda como diferencias los registros del target cuyo ID no fue leído del tópico.
+	// Retorna la cantidad de registros encontrados.
+	RecordOrphans(ctx context.Context, jobID string, target valueobjects.SyncTarget) (int64, error)
+
+	// DeleteSeenKeys elimina los IDs leídos por un job
+	DeleteSeenKeys(ctx context.Context, jobID string) error
+
+	// FindItems busca las diferencias de un job, opcionalmente filtradas por tipo
+	FindItems(ctx context.Context, jobID string, kind *valueobjects.ReconciliationKind, limit, offset int) ([]*entities.ReconciliationItem, error)
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"context"
+	"errors"
+)
+
+// ErrSyncJobAlreadyActive se retorna si ya hay un job pendiente o en ejecución para el mismo target
+var ErrSyncJobAlreadyActive = errors.New("a sync job is already active for this target")
+
+// SyncJobRepository define el contrato para el repositorio de jobs de sincronización
+type SyncJobRepository interface {
+	// Create guarda un nuevo job. Retorna ErrSyncJobAlreadyActive si ya hay uno activo para su target.
+	Create(ctx context.Context, job *aggregates.SyncJob) error
+
+	// Save actualiza el estado, los contadores y los checkpoints de un job.
+	// Retorna ErrSyncJobAlreadyActive si al reactivarlo ya hay otro activo para su target.
+	Save(ctx context.Context, job *aggregates.SyncJob) error
+
+	// FindByID busca un job por ID
+	FindByID(ctx context.Context, id string) (*aggregates.SyncJob, error)
+
+	// FindActive busca los jobs pendientes o en ejecución
+	FindActive(ctx context.Context) ([]*aggregates.SyncJob, error)
+
+	// FindRecent busca los últimos jobs creados
+	FindRecent(ctx context.Context, limit int) ([]*aggregates.SyncJob, error)
+}
@@ -1,11 +1,13 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -33,14 +35,92 @@ type Config struct {
 		SaslMechanism    string
 		SaslUsername     string
 		SaslPassword     string
+		// TLS (SSL y SASL_SSL): CA propia y certificado de cliente para mTLS
+		TLSCAFile             string
+		TLSCertFile           string
+		TLSKeyFile            string
+		TLSServerName         string
+		TLSInsecureSkipVerify bool
+		// OAUTHBEARER: client credentials contra OAuthTokenURL o un token fijo
+		OAuthToken        string
+		OAuthTokenURL     string
+		OAuthClientID     string
+		OAuthClientSecret string
+		OAuthScopes       []string
 		// Azure Event Hub specific settings
 		RequestTimeoutMs int
 		SessionTimeoutMs int
 		EnableAutoCommit bool
+		// Dead-letter para mensajes que no pudieron procesarse
+		DeadLetterEnabled bool
+		DeadLetterTopic   string
+		// Enviar al dead-letter los errores transitorios que agotaron los reintentos (por defecto se reintenta la sesión)
+		DeadLetterTransientErrors bool
+		// Reintentos de errores transitorios
+		RetryMaxAttempts      int
+		RetryInitialBackoffMs int
+		RetryMaxBackoffMs     int
+		RetryMaxElapsedMs     int
+		// Micro-lotes de ingesta de ejecuciones
+		BatchSize      int
+		BatchTimeoutMs int
+		// Workers por partición: procesan en paralelo claves distintas (1 = secuencial)
+		Workers int
+		// Formato de serialización por tópico (json, avro, protobuf)
+		TopicFormats map[string]string
+		// Tipos de evento (CloudEvents type) de ejecuciones de código
+		EventTypes []string
+		// Lag total a partir del cual la ingesta se reporta como LAGGING
+		LagWarningThreshold int
+		// Offset inicial de un consumer group nuevo: newest u oldest
+		InitialOffset string
+		// Dónde se guardan los offsets: kafka (commits del consumer group) o postgres (junto con los datos)
+		OffsetStorage string
 	}
 	KafkaUserRegistration struct {
-		Topic   string
-		GroupID string
+		Topic      string
+		EventTypes []string
+		// Fechas de registro: zona de las fechas sin offset y tolerancia a diferencias de reloj con IAM
+		Timezone       *time.Location
+		MaxClockSkewMs int
+		// Consumer group propio que usaba el tópico antes de compartir KAFKA_GROUP_ID (vacío = no migrar offsets)
+		LegacyGroupID string
+	}
+	// Catálogo de challenges: dimensión de los KPIs de ejecuciones (vacío = no se consume)
+	KafkaChallenge struct {
+		Topic      string
+		EventTypes []string
+	}
+	// Versiones de código de los estudiantes: timeline de mejoras entre envíos (vacío = no se consume)
+	KafkaCodeVersion struct {
+		Topic      string
+		EventTypes []string
+	}
+	// Registro de lenguajes: se agrega a los lenguajes predefinidos y reemplaza a los del mismo nombre
+	Languages struct {
+		RegistryFile string
+		Definitions  []LanguageConfig
+	}
+	MessageSource struct {
+		Type  string            // kafka (por defecto) o file
+		Files map[string]string // Archivo JSONL por tópico (Type = file)
+	}
+	// Eventos de dominio derivados (outbox) publicados en Kafka
+	Outbox struct {
+		Enabled                   bool
+		Topic                     string
+		BatchSize                 int
+		PollIntervalMs            int
+		RetentionHours            int // 0 = conservar los eventos publicados
+		MaxAttempts               int // 0 = reintentar sin límite
+		Milestones                []int
+		AtRiskConsecutiveFailures int
+	}
+	SchemaRegistry struct {
+		URL       string
+		Username  string
+		Password  string
+		TimeoutMs int
 	}
 	ServiceDiscovery struct {
 		URL         string
@@ -50,6 +130,14 @@ type Config struct {
 	}
 }
 
+// LanguageConfig es un lenguaje del archivo LANGUAGE_REGISTRY_FILE
+type LanguageConfig struct {
+	Name    string   `json:"name"`              // Nombre canónico
+	Aliases []string `json:"aliases"`           // Valores que envía el ejecutor (py, python3, c++...)
+	Family  string   `json:"family"`            // Familia del lenguaje (jvm, c, python...)
+	Version string   `json:"version,omitempty"` // Versión del runtime por defecto
+}
+
 // Load carga la configuración desde variables de entorno
 func Load() (*Config, error) {
 	// Intentar cargar .env si existe
@@ -78,9 +166,21 @@ func Load() (*Config, error) {
 	config.Kafka.GroupID = getEnv("KAFKA_GROUP_ID", "analytics-consumer-group")
 	config.Kafka.Topic = getEnv("KAFKA_TOPIC", "execution.analytics")
 
-	// Security configuration for Azure Event Hub
-	config.Kafka.SecurityProtocol = getEnv("KAFKA_SECURITY_PROTOCOL", "PLAINTEXT")
-	config.Kafka.SaslMechanism = getEnv("KAFKA_SASL_MECHANISM", "PLAIN")
+	// Security configuration: PLAINTEXT, SSL, SASL_PLAINTEXT o SASL_SSL (Azure Event Hub)
+	config.Kafka.SecurityProtocol = strings.ToUpper(getEnv("KAFKA_SECURITY_PROTOCOL", "PLAINTEXT"))
+	switch config.Kafka.SecurityProtocol {
+	case "PLAINTEXT", "SSL", "SASL_PLAINTEXT", "SASL_SSL":
+	default:
+		return nil, fmt.Errorf("invalid KAFKA_SECURITY_PROTOCOL %q: must be PLAINTEXT, SSL, SASL_PLAINTEXT or SASL_SSL", config.Kafka.SecurityProtocol)
+	}
+
+	// Mecanismo SASL: PLAIN (Azure Event Hub), SCRAM-SHA-256, SCRAM-SHA-512 u OAUTHBEARER
+	config.Kafka.SaslMechanism = strings.ToUpper(getEnv("KAFKA_SASL_MECHANISM", "PLAIN"))
+	switch config.Kafka.SaslMechanism {
+	case "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512", "OAUTHBEARER":
+	default:
+		return nil, fmt.Errorf("invalid KAFKA_SASL_MECHANISM %q: must be PLAIN, SCRAM-SHA-256, SCRAM-SHA-512 or OAUTHBEARER", config.Kafka.SaslMechanism)
+	}
 
 	// Azure Event Hub connection configuration
 	// For Azure Event Hub, username is always "$ConnectionString"
@@ -89,14 +189,138 @@ func Load() (*Config, error) {
 	// Password is the full connection string for Azure Event Hub
 	config.Kafka.SaslPassword = getEnv("KAFKA_SASL_PASSWORD", "")
 
+	// TLS propio (Kafka self-hosted): CA, certificado y clave de cliente en formato PEM
+	config.Kafka.TLSCAFile = getEnv("KAFKA_TLS_CA_FILE", "")
+	config.Kafka.TLSCertFile = getEnv("KAFKA_TLS_CERT_FILE", "")
+	config.Kafka.TLSKeyFile = getEnv("KAFKA_TLS_KEY_FILE", "")
+	config.Kafka.TLSServerName = getEnv("KAFKA_TLS_SERVER_NAME", "")
+	config.Kafka.TLSInsecureSkipVerify = getEnvAsBool("KAFKA_TLS_INSECURE_SKIP_VERIFY", false)
+
+	// OAUTHBEARER: con KAFKA_OAUTH_TOKEN_URL se usa el flujo client credentials; si no, el token fijo
+	config.Kafka.OAuthToken = getEnv("KAFKA_OAUTH_TOKEN", "")
+	config.Kafka.OAuthTokenURL = getEnv("KAFKA_OAUTH_TOKEN_URL", "")
+	config.Kafka.OAuthClientID = getEnv("KAFKA_OAUTH_CLIENT_ID", "")
+	config.Kafka.OAuthClientSecret = getEnv("KAFKA_OAUTH_CLIENT_SECRET", "")
+	config.Kafka.OAuthScopes = getEnvAsSlice("KAFKA_OAUTH_SCOPES", nil)
+
 	// Azure Event Hub specific timeouts
 	config.Kafka.RequestTimeoutMs = getEnvAsInt("KAFKA_REQUEST_TIMEOUT_MS", 60000)
 	config.Kafka.SessionTimeoutMs = getEnvAsInt("KAFKA_SESSION_TIMEOUT_MS", 60000)
 	config.Kafka.EnableAutoCommit = getEnvAsBool("KAFKA_ENABLE_AUTO_COMMIT", true)
 
+	// Offset inicial de un consumer group sin offsets confirmados
+	config.Kafka.InitialOffset = strings.ToLower(getEnv("KAFKA_INITIAL_OFFSET", "newest"))
+	if config.Kafka.InitialOffset != "newest" && config.Kafka.InitialOffset != "oldest" {
+		return nil, fmt.Errorf("invalid KAFKA_INITIAL_OFFSET %q: must be newest or oldest", config.Kafka.InitialOffset)
+	}
+
+	// Con "postgres" los offsets se guardan en la misma transacción que los datos ingeridos
+	config.Kafka.OffsetStorage = strings.ToLower(getEnv("KAFKA_OFFSET_STORAGE", "kafka"))
+	if config.Kafka.OffsetStorage != "kafka" && config.Kafka.OffsetStorage != "postgres" {
+		return nil, fmt.Errorf("invalid KAFKA_OFFSET_STORAGE %q: must be kafka or postgres", config.Kafka.OffsetStorage)
+	}
+
+	// Dead-letter topic y cuarentena para mensajes inválidos
+	config.Kafka.DeadLetterEnabled = getEnvAsBool("KAFKA_DEAD_LETTER_ENABLED", false)
+	config.Kafka.DeadLetterTopic = getEnv("KAFKA_DEAD_LETTER_TOPIC", "analytics.dead-letter")
+	config.Kafka.DeadLetterTransientErrors = getEnvAsBool("KAFKA_DEAD_LETTER_TRANSIENT_ERRORS", false)
+
+	// Reintentos con backoff exponencial para errores transitorios (base de datos, red)
+	config.Kafka.RetryMaxAttempts = getEnvAsInt("KAFKA_RETRY_MAX_ATTEMPTS", 10)
+	config.Kafka.RetryInitialBackoffMs = getEnvAsInt("KAFKA_RETRY_INITIAL_BACKOFF_MS", 500)
+	config.Kafka.RetryMaxBackoffMs = getEnvAsInt("KAFKA_RETRY_MAX_BACKOFF_MS", 30000)
+	config.Kafka.RetryMaxElapsedMs = getEnvAsInt("KAFKA_RETRY_MAX_ELAPSED_MS", 120000)
+
+	// Micro-lotes: se guardan al alcanzar el tamaño o el tiempo máximo, lo que ocurra primero
+	config.Kafka.BatchSize = getEnvAsInt("KAFKA_BATCH_SIZE", 100)
+	config.Kafka.BatchTimeoutMs = getEnvAsInt("KAFKA_BATCH_TIMEOUT_MS", 500)
+
+	// Workers por partición: los mensajes de una misma clave (por ejemplo, un estudiante) se procesan en orden
+	config.Kafka.Workers = getEnvAsInt("KAFKA_WORKERS", 1)
+	if config.Kafka.Workers < 1 {
+		return nil, fmt.Errorf("invalid KAFKA_WORKERS %d: must be at least 1", config.Kafka.Workers)
+	}
+	// Con varios workers el offset se guarda después del lote, fuera de su transacción: se perdería la
+	// garantía exactly-once de KAFKA_OFFSET_STORAGE=postgres
+	if config.Kafka.Workers > 1 && config.Kafka.OffsetStorage == "postgres" {
+		return nil, fmt.Errorf("KAFKA_WORKERS %d is not supported with KAFKA_OFFSET_STORAGE=postgres: offsets would be stored outside the data transaction", config.Kafka.Workers)
+	}
+
+	// Formato por tópico, por ejemplo: "execution.analytics=avro,iam.user.registered=json"
+	config.Kafka.TopicFormats = getEnvAsMap("KAFKA_TOPIC_FORMATS")
+
+	// Tipos de CloudEvents (o envelope) enrutados a cada handler
+	config.Kafka.EventTypes = getEnvAsSlice("KAFKA_EVENT_TYPES", []string{"execution.analytics", "execution.analytics.updated"})
+
+	// Estado de ingesta (/api/v1/admin/ingestion)
+	config.Kafka.LagWarningThreshold = getEnvAsInt("KAFKA_LAG_WARNING_THRESHOLD", 1000)
+
 	// Kafka User Registration configuration
 	config.KafkaUserRegistration.Topic = getEnv("KAFKA_USER_REGISTRATION_TOPIC", "iam.user.registered")
-	config.KafkaUserRegistration.GroupID = getEnv("KAFKA_USER_REGISTRATION_GROUP_ID", "user-registration-analytics-group")
+	config.KafkaUserRegistration.EventTypes = getEnvAsSlice("KAFKA_USER_REGISTRATION_EVENT_TYPES", []string{"iam.user.registered"})
+
+	// Zona horaria de las fechas sin offset (LocalDateTime de Java), por ejemplo: America/Lima
+	timezone := getEnv("KAFKA_USER_REGISTRATION_TIMEZONE", "UTC")
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_USER_REGISTRATION_TIMEZONE %q: %w", timezone, err)
+	}
+	config.KafkaUserRegistration.Timezone = location
+	config.KafkaUserRegistration.MaxClockSkewMs = getEnvAsInt("KAFKA_USER_REGISTRATION_MAX_CLOCK_SKEW_MS", 300000)
+	config.KafkaUserRegistration.LegacyGroupID = getEnv("KAFKA_USER_REGISTRATION_LEGACY_GROUP_ID", "user-registration-analytics-group")
+
+	// Kafka Challenge catalog configuration: opcional, solo se consume si se indica el tópico
+	config.KafkaChallenge.Topic = getEnv("KAFKA_CHALLENGE_TOPIC", "")
+	config.KafkaChallenge.EventTypes = getEnvAsSlice("KAFKA_CHALLENGE_EVENT_TYPES", []string{"challenge.created", "challenge.updated", "challenge.archived"})
+
+	// Kafka Code Version configuration: opcional, solo se consume si se indica el tópico
+	config.KafkaCodeVersion.Topic = getEnv("KAFKA_CODE_VERSION_TOPIC", "")
+	config.KafkaCodeVersion.EventTypes = getEnvAsSlice("KAFKA_CODE_VERSION_EVENT_TYPES", []string{"code.version.created"})
+
+	// Registro de lenguajes: archivo JSON con un arreglo de {"name", "aliases", "family", "version"}
+	config.Languages.RegistryFile = getEnv("LANGUAGE_REGISTRY_FILE", "")
+	if config.Languages.RegistryFile != "" {
+		definitions, err := loadLanguageRegistry(config.Languages.RegistryFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LANGUAGE_REGISTRY_FILE %q: %w", config.Languages.RegistryFile, err)
+		}
+		config.Languages.Definitions = definitions
+	}
+
+	// Fuente de los mensajes: kafka, o file para reprocesar volcados JSONL (un evento por línea)
+	config.MessageSource.Type = strings.ToLower(getEnv("MESSAGE_SOURCE", "kafka"))
+	config.MessageSource.Files = getEnvAsMap("MESSAGE_SOURCE_FILES")
+	switch config.MessageSource.Type {
+	case "kafka":
+	case "file":
+		if len(config.MessageSource.Files) == 0 {
+			return nil, fmt.Errorf("MESSAGE_SOURCE_FILES is required when MESSAGE_SOURCE is file")
+		}
+	default:
+		return nil, fmt.Errorf("invalid MESSAGE_SOURCE %q: must be kafka or file", config.MessageSource.Type)
+	}
+
+	// Outbox de eventos de dominio: se escriben junto con las ejecuciones y un relay los publica en OUTBOX_TOPIC
+	config.Outbox.Enabled = getEnvAsBool("OUTBOX_ENABLED", false)
+	config.Outbox.Topic = getEnv("OUTBOX_TOPIC", "analytics.events")
+	config.Outbox.BatchSize = getEnvAsInt("OUTBOX_BATCH_SIZE", 100)
+	config.Outbox.PollIntervalMs = getEnvAsInt("OUTBOX_POLL_INTERVAL_MS", 1000)
+	config.Outbox.RetentionHours = getEnvAsInt("OUTBOX_RETENTION_HOURS", 168)
+	config.Outbox.MaxAttempts = getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 10)
+	config.Outbox.AtRiskConsecutiveFailures = getEnvAsInt("OUTBOX_AT_RISK_CONSECUTIVE_FAILURES", 5)
+	for _, value := range getEnvAsSlice("OUTBOX_MILESTONES", []string{"1", "5", "10", "25", "50", "100"}) {
+		milestone, err := strconv.Atoi(value)
+		if err != nil || milestone <= 0 {
+			return nil, fmt.Errorf("invalid OUTBOX_MILESTONES value %q: must be a positive integer", value)
+		}
+		config.Outbox.Milestones = append(config.Outbox.Milestones, milestone)
+	}
+
+	// Schema Registry (requerido para tópicos avro o protobuf)
+	config.SchemaRegistry.URL = getEnv("SCHEMA_REGISTRY_URL", "")
+	config.SchemaRegistry.Username = getEnv("SCHEMA_REGISTRY_USERNAME", "")
+	config.SchemaRegistry.Password = getEnv("SCHEMA_REGISTRY_PASSWORD", "")
+	config.SchemaRegistry.TimeoutMs = getEnvAsInt("SCHEMA_REGISTRY_TIMEOUT_MS", 10000)
 
 	// Service Discovery configuration
 	config.ServiceDiscovery.URL = getEnv("SERVICE_DISCOVERY_URL", "http://127.0.0.1:8761/eureka/")
@@ -105,15 +329,49 @@ func Load() (*Config, error) {
 	config.ServiceDiscovery.InstanceIP = getEnv("EUREKA_INSTANCE_IP", "") // IP para registro en Eureka
 
 	// Log configuration (sin mostrar credenciales sensibles)
+	if config.MessageSource.Type == "file" {
+		log.Printf("Message Source: files %v", config.MessageSource.Files)
+	}
 	log.Printf("Kafka Configuration:")
 	log.Printf("  Bootstrap Servers: %v", config.Kafka.BootstrapServers)
 	log.Printf("  Security Protocol: %s", config.Kafka.SecurityProtocol)
-	log.Printf("  SASL Mechanism: %s", config.Kafka.SaslMechanism)
+	if config.IsSaslEnabled() {
+		log.Printf("  SASL Mechanism: %s", config.Kafka.SaslMechanism)
+	}
+	if config.Kafka.TLSCertFile != "" {
+		log.Printf("  TLS Client Certificate: %s", config.Kafka.TLSCertFile)
+	}
 	log.Printf("  Group ID: %s", config.Kafka.GroupID)
+	log.Printf("  Initial Offset: %s", config.Kafka.InitialOffset)
+	log.Printf("  Offset Storage: %s", config.Kafka.OffsetStorage)
+	log.Printf("  Workers per Partition: %d", config.Kafka.Workers)
 	log.Printf("  Topic: %s", config.Kafka.Topic)
 	log.Printf("  User Registration Topic: %s", config.KafkaUserRegistration.Topic)
+	if config.KafkaChallenge.Topic != "" {
+		log.Printf("  Challenge Topic: %s", config.KafkaChallenge.Topic)
+	}
+	if config.KafkaCodeVersion.Topic != "" {
+		log.Printf("  Code Version Topic: %s", config.KafkaCodeVersion.Topic)
+	}
+	if config.Kafka.DeadLetterEnabled {
+		log.Printf("  Dead-Letter Topic: %s (must exist in the broker)", config.Kafka.DeadLetterTopic)
+	} else {
+		log.Printf("  Dead-Letter: disabled, failed messages are only logged")
+	}
+	if config.Outbox.Enabled {
+		log.Printf("  Outbox Topic: %s", config.Outbox.Topic)
+	}
+	if config.Languages.RegistryFile != "" {
+		log.Printf("Language Registry: %s (%d languages)", config.Languages.RegistryFile, len(config.Languages.Definitions))
+	}
+	if len(config.Kafka.TopicFormats) > 0 {
+		log.Printf("  Topic Formats: %v", config.Kafka.TopicFormats)
+	}
+	if config.SchemaRegistry.URL != "" {
+		log.Printf("  Schema Registry: %s", config.SchemaRegistry.URL)
+	}
 
-	if config.Kafka.SecurityProtocol == "SASL_SSL" && config.Kafka.SaslPassword != "" {
+	if config.Kafka.SecurityProtocol == "SASL_SSL" && config.Kafka.SaslMechanism == "PLAIN" && config.Kafka.SaslPassword != "" {
 		log.Printf("  Azure Event Hub: Configured ✓")
 	}
 
@@ -137,11 +395,33 @@ func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf("%s:%s", c.Server.IP, c.Server.Port)
 }
 
+// GetTopicFormat retorna el formato de serialización de un tópico (json por defecto)
+func (c *Config) GetTopicFormat(topic string) string {
+	if format, ok := c.Kafka.TopicFormats[topic]; ok {
+		return format
+	}
+	return "json"
+}
+
 // IsSaslEnabled verifica si SASL está habilitado
 func (c *Config) IsSaslEnabled() bool {
 	return c.Kafka.SecurityProtocol == "SASL_SSL" || c.Kafka.SecurityProtocol == "SASL_PLAINTEXT"
 }
 
+// loadLanguageRegistry lee los lenguajes de un archivo JSON
+func loadLanguageRegistry(path string) ([]LanguageConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []LanguageConfig
+	if err := json.Unmarshal(content, &definitions); err != nil {
+		return nil, fmt.Errorf("error parsing language registry: %w", err)
+	}
+	return definitions, nil
+}
+
 // getEnv obtiene una variable de entorno o retorna un valor por defecto
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -177,3 +457,38 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return value
 }
+
+// getEnvAsSlice obtiene una variable de entorno con valores separados por comas o retorna un valor por defecto
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	values := make([]string, 0)
+	for _, value := range strings.Split(valueStr, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvAsMap obtiene una variable de entorno con pares "clave=valor" separados por comas
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return result
+	}
+
+	for _, pair := range strings.Split(valueStr, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			log.Printf("Warning: Invalid entry %q in %s, expected key=value", pair, key)
+			continue
+		}
+		result[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return result
+}
@@ -23,11 +23,39 @@ func NewDatabase(dsn string) (*gorm.DB, error) {
 	if err := db.AutoMigrate(
 		&repositories.ExecutionAnalyticsModel{},
 		&repositories.TestResultModel{},
+		&repositories.ExecutionAnalyticsHistoryModel{},
 		&repositories.UserRegistrationAnalyticsModel{},
+		&repositories.QuarantinedEventModel{},
+		&repositories.ConsumerOffsetModel{},
+		&repositories.SyncJobModel{},
+		&repositories.SyncCheckpointModel{},
+		&repositories.SyncReconciliationItemModel{},
+		&repositories.SyncSeenKeyModel{},
+		&repositories.OutboxEventModel{},
+		&repositories.ChallengeModel{},
+		&repositories.CodeVersionModel{},
 	); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// Ejecuciones guardadas antes de la taxonomía de resultados
+	backfilled, err := repositories.BackfillExecutionOutcomes(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill execution outcomes: %w", err)
+	}
+	if backfilled > 0 {
+		log.Printf("Derived outcome of %d existing executions", backfilled)
+	}
+
+	// Ejecuciones guardadas antes del puntaje ponderado
+	scored, err := repositories.BackfillExecutionScores(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill execution scores: %w", err)
+	}
+	if scored > 0 {
+		log.Printf("Calculated score of %d existing executions", scored)
+	}
+
 	log.Println("Database connected and migrated successfully")
 	return db, nil
 }
@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+
+	"github.com/IBM/sarama"
+)
+
+// ChallengeEventHandler define el contrato para procesar los eventos del catálogo de challenges
+type ChallengeEventHandler interface {
+	HandleChallengeEvent(ctx context.Context, challenge *aggregates.Challenge) error
+}
+
+// NewChallengeRoute crea la ruta para los eventos de creación, actualización y archivado de challenges,
+// procesados mensaje a mensaje y en orden por challenge
+func NewChallengeRoute(topic string, handler ChallengeEventHandler, payloadDecoder events.PayloadDecoder) Route {
+	return Route{
+		Topic: topic,
+		Name:  "challenge catalog",
+		EventTypes: []string{
+			events.ChallengeCreatedEventType,
+			events.ChallengeUpdatedEventType,
+			events.ChallengeArchivedEventType,
+		},
+		Decode:    challengeDecoder(payloadDecoder),
+		BatchSize: 1,
+		Handle: func(ctx context.Context, values []interface{}) error {
+			for _, value := range values {
+				challenge := value.(*aggregates.Challenge)
+				if err := handler.HandleChallengeEvent(ctx, challenge); err != nil {
+					return fmt.Errorf("error handling challenge event: %w", err)
+				}
+
+				log.Printf("Successfully processed challenge %s event: %s", challenge.Change(), challenge.ChallengeID().Value())
+			}
+			return nil
+		},
+		OrderingKey: func(value interface{}) string {
+			return value.(*aggregates.Challenge).ChallengeID().Value()
+		},
+	}
+}
+
+// challengeDecoder deserializa el mensaje y lo convierte a dominio. Los eventos legados sin tipo se
+// interpretan como actualizaciones.
+func challengeDecoder(payloadDecoder events.PayloadDecoder) Decoder {
+	return func(ctx context.Context, message *sarama.ConsumerMessage) (interface{}, error) {
+		envelope, err := decodeEnvelope(ctx, payloadDecoder, message, events.ChallengeUpdatedEventType)
+		if err != nil {
+			return nil, err
+		}
+		return events.DecodeChallenge(envelope, message.Timestamp)
+	}
+}
@@ -0,0 +1,69 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+
+	"github.com/IBM/sarama"
+)
+
+// CloudEventMode define cómo se publica un CloudEvent en Kafka
+type CloudEventMode int
+
+const (
+	// CloudEventStructured publica el evento completo como JSON en el cuerpo del mensaje
+	CloudEventStructured CloudEventMode = iota
+	// CloudEventBinary publica los atributos como headers ce_* y data como cuerpo del mensaje
+	CloudEventBinary
+)
+
+// headersToMap convierte los headers de un mensaje en un mapa; ante claves repetidas prevalece la última
+func headersToMap(headers []*sarama.RecordHeader) map[string]string {
+	result := make(map[string]string, len(headers))
+	for _, header := range headers {
+		if header != nil {
+			result[string(header.Key)] = string(header.Value)
+		}
+	}
+	return result
+}
+
+// NewCloudEventMessage construye el mensaje de Kafka para publicar un CloudEvent en el modo indicado
+func NewCloudEventMessage(topic string, key string, event *events.CloudEvent, mode CloudEventMode) (*sarama.ProducerMessage, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+
+	message := &sarama.ProducerMessage{Topic: topic}
+	if key != "" {
+		message.Key = sarama.StringEncoder(key)
+	}
+
+	switch mode {
+	case CloudEventBinary:
+		for name, value := range event.Attributes() {
+			if name == "datacontenttype" {
+				message.Headers = append(message.Headers, sarama.RecordHeader{Key: []byte("content-type"), Value: []byte(value)})
+				continue
+			}
+			message.Headers = append(message.Headers, sarama.RecordHeader{
+				Key:   []byte(events.CloudEventHeaderPrefix + name),
+				Value: []byte(value),
+			})
+		}
+		message.Value = sarama.ByteEncoder(event.Data)
+	case CloudEventStructured:
+		value, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling cloud event: %w", err)
+		}
+		message.Headers = append(message.Headers, sarama.RecordHeader{Key: []byte("content-type"), Value: []byte(events.CloudEventsContentType)})
+		message.Value = sarama.ByteEncoder(value)
+	default:
+		return nil, fmt.Errorf("unsupported cloud event mode: %d", mode)
+	}
+
+	return message, nil
+}
@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+
+	"github.com/IBM/sarama"
+)
+
+// CodeVersionEventHandler define el contrato para procesar los eventos de versiones de código
+type CodeVersionEventHandler interface {
+	HandleCodeVersionEvent(ctx context.Context, version *aggregates.CodeVersion) error
+}
+
+// NewCodeVersionRoute crea la ruta para los eventos de versiones de código, procesados mensaje a mensaje
+func NewCodeVersionRoute(topic string, handler CodeVersionEventHandler, payloadDecoder events.PayloadDecoder) Route {
+	return Route{
+		Topic:      topic,
+		Name:       "code version",
+		EventTypes: []string{events.CodeVersionCreatedEventType},
+		Decode:     codeVersionDecoder(payloadDecoder),
+		BatchSize:  1,
+		Handle: func(ctx context.Context, values []interface{}) error {
+			for _, value := range values {
+				version := value.(*aggregates.CodeVersion)
+				if err := handler.HandleCodeVersionEvent(ctx, version); err != nil {
+					return fmt.Errorf("error handling code version event: %w", err)
+				}
+
+				log.Printf("Successfully processed code version: %s (version %d)",
+					version.CodeVersionID().Value(), version.VersionNumber())
+			}
+			return nil
+		},
+		OrderingKey: func(value interface{}) string {
+			return value.(*aggregates.CodeVersion).StudentID().Value()
+		},
+	}
+}
+
+// codeVersionDecoder deserializa el mensaje y lo convierte a dominio
+func codeVersionDecoder(payloadDecoder events.PayloadDecoder) Decoder {
+	return func(ctx context.Context, message *sarama.ConsumerMessage) (interface{}, error) {
+		envelope, err := decodeEnvelope(ctx, payloadDecoder, message, events.CodeVersionCreatedEventType)
+		if err != nil {
+			return nil, err
+		}
+		return events.DecodeCodeVersion(envelope, message.Timestamp)
+	}
+}
@@ -2,29 +2,20 @@ package kafka
 
 import (
 	"context"
-	"crypto/tls"
-	"encoding/json"
 	"fmt"
 	"log"
 	"time"
 
-	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
-	"github.com/nanab/analytics-service/analytics/domain/model/entities"
-	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
 
 	"github.com/IBM/sarama"
 )
 
-// EventHandler define el contrato para procesar eventos
-type EventHandler interface {
-	HandleExecutionAnalyticsEvent(ctx context.Context, execution *aggregates.ExecutionAnalytics) error
-}
-
 // ConsumerConfig contiene la configuración para el consumidor de Kafka
 type ConsumerConfig struct {
 	Brokers          []string
 	GroupID          string
-	Topic            string
 	SecurityProtocol string
 	SaslMechanism    string
 	SaslUsername     string
@@ -32,108 +23,205 @@ type ConsumerConfig struct {
 	RequestTimeoutMs int
 	SessionTimeoutMs int
 	EnableAutoCommit bool
+	// Workers por partición: procesan en paralelo los mensajes de claves distintas (1 = secuencial)
+	Workers int
+	// TLS (SSL y SASL_SSL): CA propia y certificado de cliente para mTLS
+	TLSCAFile             string
+	TLSCertFile           string
+	TLSKeyFile            string
+	TLSServerName         string
+	TLSInsecureSkipVerify bool
+	// OAUTHBEARER: proveedor de tokens propio o, si es nil, client credentials contra OAuthTokenURL o un token fijo
+	TokenProvider     sarama.AccessTokenProvider
+	OAuthToken        string
+	OAuthTokenURL     string
+	OAuthClientID     string
+	OAuthClientSecret string
+	OAuthScopes       []string
+	// Offset inicial de un consumer group sin offsets confirmados: "newest" (por defecto) u "oldest"
+	InitialOffset string
+	// Política de reintentos para errores transitorios (0 = valor por defecto)
+	RetryMaxAttempts      int
+	RetryInitialBackoffMs int
+	RetryMaxBackoffMs     int
+	RetryMaxElapsedMs     int
+	// Enviar al dead-letter los errores transitorios que agotaron los reintentos. Por defecto la sesión se
+	// reinicia sin marcar el offset y el mensaje se reintenta hasta que la dependencia se recupera.
+	DeadLetterTransientErrors bool
 }
 
-// Consumer representa el consumidor de Kafka
+// Consumer representa el consumidor de Kafka. Un único consumer group se suscribe a todos
+// los tópicos registrados y despacha cada mensaje a la ruta de su tópico.
 type Consumer struct {
 	consumerGroup sarama.ConsumerGroup
-	topic         string
-	handler       EventHandler
+	routes        map[string]*Route // por tópico
+	eventTypes    map[string]*Route // por tipo de evento
+	deadLetter    *DeadLetterPublisher
+	retrier       *retrier
+	metrics       *IngestionMetrics
+	autoCommit    bool
+	workers       int
+	groupID       string
+	// Si no es nil, los offsets se guardan en la base de datos y se retoman desde ahí
+	offsetStore repositories.ConsumerOffsetRepository
+	// Pausas y reseteos de offsets pedidos por los administradores
+	control *consumerControl
+	// Si es false, los errores transitorios que agotaron los reintentos no van al dead-letter
+	deadLetterTransient bool
 }
 
 // NewConsumer crea una nueva instancia del consumidor compatible con Azure Event Hub
-func NewConsumer(brokers []string, groupID, topic string, handler EventHandler) (*Consumer, error) {
+func NewConsumer(brokers []string, groupID string) (*Consumer, error) {
 	config := &ConsumerConfig{
 		Brokers:          brokers,
 		GroupID:          groupID,
-		Topic:            topic,
 		SecurityProtocol: "PLAINTEXT",
 		EnableAutoCommit: true,
 		RequestTimeoutMs: 60000,
 		SessionTimeoutMs: 60000,
 	}
-	return NewConsumerWithConfig(config, handler)
+	return NewConsumerWithConfig(config, nil)
 }
 
-// NewConsumerWithConfig crea una nueva instancia del consumidor con configuración personalizada
-func NewConsumerWithConfig(cfg *ConsumerConfig, handler EventHandler) (*Consumer, error) {
-	config := sarama.NewConfig()
-	config.Version = sarama.V2_6_0_0 // Azure Event Hub es compatible con Kafka 1.0+
-
-	// Consumer group settings
-	config.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
-	config.Consumer.Offsets.Initial = sarama.OffsetNewest
-	config.Consumer.Return.Errors = true
-
-	// Auto commit settings
-	if cfg.EnableAutoCommit {
-		config.Consumer.Offsets.AutoCommit.Enable = true
-		config.Consumer.Offsets.AutoCommit.Interval = 1 * time.Second
+// NewConsumerWithConfig crea una nueva instancia del consumidor con configuración personalizada.
+// Si deadLetter es nil, los mensajes que fallan solo se registran en el log.
+func NewConsumerWithConfig(cfg *ConsumerConfig, deadLetter *DeadLetterPublisher) (*Consumer, error) {
+	config, err := NewSaramaConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kafka configuration: %w", err)
 	}
 
-	// Timeouts - Azure Event Hub requiere timeouts más altos
-	config.Net.DialTimeout = 30 * time.Second
-	config.Net.ReadTimeout = time.Duration(cfg.RequestTimeoutMs) * time.Millisecond
-	config.Net.WriteTimeout = 30 * time.Second
+	// Crear consumer group
+	log.Printf("Creating consumer group with brokers: %v, groupID: %s", cfg.Brokers, cfg.GroupID)
+	consumerGroup, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.GroupID, config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating consumer group: %w", err)
+	}
 
-	config.Consumer.MaxProcessingTime = time.Duration(cfg.SessionTimeoutMs) * time.Millisecond
-	config.Consumer.Group.Session.Timeout = time.Duration(cfg.SessionTimeoutMs) * time.Millisecond
-	config.Consumer.Group.Heartbeat.Interval = 3 * time.Second
+	log.Printf("Consumer group created successfully: %s", cfg.GroupID)
+	return newConsumer(consumerGroup, cfg, deadLetter), nil
+}
 
-	// Configuración de metadata para Azure Event Hub
-	config.Metadata.Retry.Max = 5
-	config.Metadata.Retry.Backoff = 2 * time.Second
-	config.Metadata.Timeout = 60 * time.Second
-	config.Metadata.Full = false
+// newConsumer crea el consumidor sobre un consumer group de Kafka o el adaptador de otra fuente de mensajes
+func newConsumer(consumerGroup sarama.ConsumerGroup, cfg *ConsumerConfig, deadLetter *DeadLetterPublisher) *Consumer {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
 
-	// Configuración de seguridad para Azure Event Hub
-	if cfg.SecurityProtocol == "SASL_SSL" {
-		log.Println("Configuring SASL_SSL for Azure Event Hub...")
+	consumer := &Consumer{
+		consumerGroup: consumerGroup,
+		routes:        make(map[string]*Route),
+		eventTypes:    make(map[string]*Route),
+		deadLetter:    deadLetter,
+		metrics:       NewIngestionMetrics(),
+		autoCommit:    cfg.EnableAutoCommit,
+		workers:       workers,
+		groupID:       cfg.GroupID,
+		control:       newConsumerControl(),
+
+		deadLetterTransient: cfg.DeadLetterTransientErrors,
+	}
+	consumer.retrier = &retrier{
+		policy: retryPolicyFromConfig(cfg),
+		pauser: consumer,
+	}
+	return consumer
+}
 
-		// Habilitar TLS
-		config.Net.TLS.Enable = true
-		config.Net.TLS.Config = &tls.Config{
-			InsecureSkipVerify: false,
-			MinVersion:         tls.VersionTLS12,
+// Register agrega una ruta para un tópico. Debe llamarse antes de Start.
+func (c *Consumer) Register(route Route) error {
+	if route.Topic == "" {
+		return fmt.Errorf("route topic cannot be empty")
+	}
+	if route.Decode == nil || route.Handle == nil {
+		return fmt.Errorf("route for topic %s must define a decoder and a handler", route.Topic)
+	}
+	if _, exists := c.routes[route.Topic]; exists {
+		return fmt.Errorf("a route for topic %s is already registered", route.Topic)
+	}
+	for _, eventType := range route.EventTypes {
+		if existing, exists := c.eventTypes[eventType]; exists {
+			return fmt.Errorf("event type %s is already handled by the route for topic %s", eventType, existing.Topic)
 		}
+	}
 
-		// Configurar SASL
-		config.Net.SASL.Enable = true
-		config.Net.SASL.Mechanism = sarama.SASLTypePlaintext // Azure Event Hub usa PLAIN
-		config.Net.SASL.User = cfg.SaslUsername              // Debe ser "$ConnectionString"
-		config.Net.SASL.Password = cfg.SaslPassword          // Connection string completo
-		config.Net.SASL.Handshake = true
-		config.Net.SASL.Version = sarama.SASLHandshakeV1
+	if route.BatchSize <= 0 {
+		route.BatchSize = 1
+	}
+	if route.Name == "" {
+		route.Name = route.Topic
+	}
 
-		log.Printf("SASL configured with mechanism: %s, username: %s", cfg.SaslMechanism, cfg.SaslUsername)
-	} else if cfg.SecurityProtocol == "SASL_PLAINTEXT" {
-		log.Println("Configuring SASL_PLAINTEXT...")
+	c.routes[route.Topic] = &route
+	for _, eventType := range route.EventTypes {
+		c.eventTypes[eventType] = &route
+	}
+	log.Printf("Registered %s route for topic: %s (batch size %d, %d worker(s) per partition)", route.Name, route.Topic, route.BatchSize, c.workers)
+	return nil
+}
 
-		config.Net.SASL.Enable = true
-		config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
-		config.Net.SASL.User = cfg.SaslUsername
-		config.Net.SASL.Password = cfg.SaslPassword
-		config.Net.SASL.Handshake = true
+// Unregister quita la ruta de un tópico y sus tipos de evento. Debe llamarse antes de Start.
+func (c *Consumer) Unregister(topic string) {
+	route, ok := c.routes[topic]
+	if !ok {
+		return
 	}
 
-	// Crear consumer group
-	log.Printf("Creating consumer group with brokers: %v, groupID: %s", cfg.Brokers, cfg.GroupID)
-	consumerGroup, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.GroupID, config)
-	if err != nil {
-		return nil, fmt.Errorf("error creating consumer group: %w", err)
+	delete(c.routes, topic)
+	for _, eventType := range route.EventTypes {
+		delete(c.eventTypes, eventType)
 	}
+	log.Printf("Unregistered %s route for topic: %s", route.Name, topic)
+}
 
-	log.Printf("Consumer group created successfully for topic: %s", cfg.Topic)
+// UseOffsetStore guarda los offsets en la base de datos. Las rutas con HandleWithOffset los guardan en la
+// misma transacción que sus datos y, al asignarse una partición, el consumo se retoma desde el offset
+// guardado. Los commits en Kafka se mantienen solo como referencia para el monitoreo del lag.
+// Debe llamarse antes de Start.
+func (c *Consumer) UseOffsetStore(store repositories.ConsumerOffsetRepository) {
+	c.offsetStore = store
+}
 
-	return &Consumer{
-		consumerGroup: consumerGroup,
-		topic:         cfg.Topic,
-		handler:       handler,
-	}, nil
+// routeFor selecciona la ruta de un mensaje: la del tipo de evento declarado, si hay una registrada,
+// o la del tópico. Los tipos no manejados por la ruta del tópico se rechazan.
+func (c *Consumer) routeFor(message *sarama.ConsumerMessage) (*Route, error) {
+	topicRoute := c.routes[message.Topic]
+
+	eventType := events.PeekEventType(message.Value, headersToMap(message.Headers))
+	if eventType == "" {
+		return topicRoute, nil
+	}
+	if route, ok := c.eventTypes[eventType]; ok {
+		return route, nil
+	}
+	if len(topicRoute.EventTypes) > 0 {
+		return topicRoute, fmt.Errorf("unexpected event type %s on topic %s", eventType, message.Topic)
+	}
+	return topicRoute, nil
+}
+
+// Topics retorna los tópicos registrados
+func (c *Consumer) Topics() []string {
+	topics := make([]string, 0, len(c.routes))
+	for topic := range c.routes {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// Metrics retorna las métricas de procesamiento del consumidor
+func (c *Consumer) Metrics() *IngestionMetrics {
+	return c.metrics
 }
 
 // Start inicia el consumo de mensajes
 func (c *Consumer) Start(ctx context.Context) error {
+	topics := c.Topics()
+	if len(topics) == 0 {
+		return fmt.Errorf("no routes registered")
+	}
+
 	handler := &consumerGroupHandler{
 		consumer: c,
 	}
@@ -151,8 +239,12 @@ func (c *Consumer) Start(ctx context.Context) error {
 			log.Println("Stopping Kafka consumer...")
 			return c.consumerGroup.Close()
 		default:
-			log.Printf("Starting consumer session for topic: %s", c.topic)
-			if err := c.consumerGroup.Consume(ctx, []string{c.topic}, handler); err != nil {
+			log.Printf("Starting consumer session for topics: %v", topics)
+			// La sesión se cancela al programar un reseteo de offsets para fijarlos al reincorporarse al grupo
+			sessionCtx, cancelSession := c.newSession(ctx)
+			err := c.consumerGroup.Consume(sessionCtx, topics, handler)
+			cancelSession()
+			if err != nil {
 				log.Printf("Error consuming messages: %v", err)
 				// Esperar un poco antes de reintentar
 				time.Sleep(5 * time.Second)
@@ -175,133 +267,418 @@ type consumerGroupHandler struct {
 func (h *consumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
 	log.Printf("Consumer group session setup - MemberID: %s, GenerationID: %d",
 		session.MemberID(), session.GenerationID())
+
+	resets, skipped := h.consumer.sessionStarted(session.MemberID(), session.Claims())
+	for _, key := range skipped {
+		log.Printf("Skipping offset reset of topic %s partition %d: not assigned to this instance", key.topic, key.partition)
+	}
+
+	if h.consumer.offsetStore != nil {
+		if err := h.restoreOffsets(session); err != nil {
+			h.consumer.requeueOffsetResets(resets)
+			return err
+		}
+	}
+	return h.applyOffsetResets(session, resets)
+}
+
+// applyOffsetResets fija los offsets programados por un administrador en las particiones asignadas.
+// Con los offsets en la base de datos, también se guardan ahí para que la próxima sesión no los revierta.
+func (h *consumerGroupHandler) applyOffsetResets(session sarama.ConsumerGroupSession, resets map[partitionKey]int64) error {
+	if len(resets) == 0 {
+		return nil
+	}
+
+	if h.consumer.offsetStore != nil {
+		for key, offset := range resets {
+			err := h.consumer.offsetStore.Save(session.Context(), repositories.ConsumerOffset{
+				GroupID:   h.consumer.groupID,
+				Topic:     key.topic,
+				Partition: key.partition,
+				Offset:    offset,
+			})
+			if err != nil {
+				h.consumer.requeueOffsetResets(resets)
+				return fmt.Errorf("error storing reset offset for topic %s partition %d: %w", key.topic, key.partition, err)
+			}
+		}
+	}
+
+	for key, offset := range resets {
+		session.MarkOffset(key.topic, key.partition, offset, "")
+		session.ResetOffset(key.topic, key.partition, offset, "")
+		log.Printf("Reset topic %s partition %d to offset %d", key.topic, key.partition, offset)
+	}
+	session.Commit()
+	return nil
+}
+
+// restoreOffsets posiciona las particiones asignadas en los offsets guardados en la base de datos.
+// Las particiones sin offset guardado usan el offset confirmado en Kafka.
+func (h *consumerGroupHandler) restoreOffsets(session sarama.ConsumerGroupSession) error {
+	for topic, partitions := range session.Claims() {
+		stored, err := h.consumer.offsetStore.FindByGroupAndTopic(session.Context(), h.consumer.groupID, topic)
+		if err != nil {
+			return fmt.Errorf("error loading stored offsets for topic %s: %w", topic, err)
+		}
+
+		for _, partition := range partitions {
+			offset, ok := stored[partition]
+			if !ok {
+				continue
+			}
+			// MarkOffset solo avanza y ResetOffset solo retrocede: juntos fijan el offset exacto
+			session.MarkOffset(topic, partition, offset, "")
+			session.ResetOffset(topic, partition, offset, "")
+			log.Printf("Resuming topic %s partition %d from stored offset %d", topic, partition, offset)
+		}
+	}
 	return nil
 }
 
 func (h *consumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
 	log.Printf("Consumer group session cleanup - MemberID: %s", session.MemberID())
+	h.consumer.sessionEnded()
 	return nil
 }
 
+// pendingMessage es un mensaje leído que espera el flush de su lote.
+// Si decoded es false, el mensaje ya fue resuelto (por ejemplo, enviado al dead-letter).
+type pendingMessage struct {
+	message *sarama.ConsumerMessage
+	value   interface{}
+	decoded bool
+}
+
+// ConsumeClaim acumula los mensajes en micro-lotes acotados por el tamaño y tiempo de la ruta.
+// Un lote solo contiene mensajes de una ruta: si cambia, el lote en curso se procesa antes.
+// Los offsets se marcan solo después de que el lote fue procesado.
 func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	log.Printf("Starting to consume partition %d from offset %d", claim.Partition(), claim.InitialOffset())
+	topicRoute, ok := h.consumer.routes[claim.Topic()]
+	if !ok {
+		return fmt.Errorf("no route registered for topic %s", claim.Topic())
+	}
+
+	log.Printf("Starting to consume %s partition %d from offset %d", topicRoute.Name, claim.Partition(), claim.InitialOffset())
+
+	// La partición se pausó antes de esta sesión: la pausa se aplica al consumidor de la nueva asignación
+	if h.consumer.isPaused(claim.Topic(), claim.Partition()) {
+		log.Printf("%s partition %d is paused", topicRoute.Name, claim.Partition())
+		h.consumer.consumerGroup.Pause(map[string][]int32{claim.Topic(): {claim.Partition()}})
+	}
+
+	if h.consumer.workers > 1 {
+		return h.consumeConcurrently(session, claim, topicRoute)
+	}
+
+	route := topicRoute
+	batch := make([]*pendingMessage, 0, route.BatchSize)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	flush := func() bool {
+		timer.Stop()
+		if len(batch) == 0 {
+			return true
+		}
+		if !h.flushBatch(session, claim, route, batch) {
+			return false
+		}
+		batch = batch[:0]
+		return true
+	}
 
 	for {
 		select {
 		case <-session.Context().Done():
-			log.Println("Session context done, stopping consumption")
+			// Los mensajes del lote sin marcar se volverán a entregar
+			log.Printf("Session context done, stopping %s consumption", topicRoute.Name)
 			return nil
+		case <-timer.C:
+			if !flush() {
+				return nil
+			}
 		case message, ok := <-claim.Messages():
 			if !ok {
-				log.Println("Message channel closed")
+				log.Printf("%s message channel closed", topicRoute.Name)
+				flush()
 				return nil
 			}
 
-			if err := h.processMessage(session.Context(), message); err != nil {
-				log.Printf("Error processing message (offset %d): %v", message.Offset, err)
-				// Continuar procesando otros mensajes incluso si uno falla
-				continue
+			log.Printf("Received message from topic %s, partition %d, offset %d, timestamp: %v",
+				message.Topic, message.Partition, message.Offset, message.Timestamp)
+
+			messageRoute, routeErr := h.consumer.routeFor(message)
+			if messageRoute != route {
+				if !flush() {
+					return nil
+				}
+				route = messageRoute
+			}
+
+			pending, ok := h.prepare(session, claim, route, routeErr, message)
+			if !ok {
+				return nil
 			}
 
-			// Marcar el mensaje como procesado
-			session.MarkMessage(message, "")
+			batch = append(batch, pending)
+			if len(batch) == 1 && route.BatchSize > 1 {
+				timer.Reset(route.BatchTimeout)
+			}
+			if len(batch) >= route.BatchSize {
+				if !flush() {
+					return nil
+				}
+			}
 		}
 	}
 }
 
-func (h *consumerGroupHandler) processMessage(ctx context.Context, message *sarama.ConsumerMessage) error {
-	log.Printf("Received message from topic %s, partition %d, offset %d, timestamp: %v",
-		message.Topic, message.Partition, message.Offset, message.Timestamp)
+// prepare decodifica el mensaje, o lo envía al dead-letter si no tiene ruta o no pudo decodificarse.
+// Retorna false si la sesión terminó mientras se decodificaba.
+func (h *consumerGroupHandler) prepare(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, route *Route, routeErr error, message *sarama.ConsumerMessage) (*pendingMessage, bool) {
+	pending := &pendingMessage{message: message}
+	if routeErr != nil {
+		if !h.deadLetter(session, claim, message, Permanent(routeErr), 1) {
+			return nil, false
+		}
+		return pending, true
+	}
 
-	// Deserializar el evento
-	var event ExecutionAnalyticsEvent
-	if err := json.Unmarshal(message.Value, &event); err != nil {
-		return fmt.Errorf("error unmarshaling event: %w", err)
+	value, attempts, err := h.decode(session.Context(), claim, route, message)
+	if err != nil {
+		if session.Context().Err() != nil {
+			log.Printf("Session ended while decoding %s message (offset %d): %v", route.Name, message.Offset, err)
+			return nil, false
+		}
+		if !h.deadLetter(session, claim, message, err, attempts) {
+			return nil, false
+		}
+		return pending, true
 	}
 
-	log.Printf("Processing execution analytics event: ExecutionID=%s, ChallengeID=%s, StudentID=%s",
-		event.ExecutionID, event.ChallengeID, event.StudentID)
+	pending.value = value
+	pending.decoded = true
+	return pending, true
+}
 
-	// Convertir evento a dominio
-	execution, err := h.eventToDomain(&event)
-	if err != nil {
-		return fmt.Errorf("error converting event to domain: %w", err)
+// decode deserializa el mensaje reintentando los errores temporales del decoder
+func (h *consumerGroupHandler) decode(ctx context.Context, claim sarama.ConsumerGroupClaim, route *Route, message *sarama.ConsumerMessage) (interface{}, int, error) {
+	var value interface{}
+	attempts, err := h.consumer.retrier.run(ctx, claim.Topic(), claim.Partition(), func(ctx context.Context) error {
+		var err error
+		value, err = route.Decode(ctx, message)
+		return decodeError(err)
+	})
+	return value, attempts, err
+}
+
+// flushBatch procesa el lote y marca el último offset. Retorna false si la sesión terminó antes de completar.
+func (h *consumerGroupHandler) flushBatch(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, route *Route, batch []*pendingMessage) bool {
+	offsetStored, ok := h.processBatch(session, claim, route, batch, true)
+	if !ok {
+		return false
 	}
 
-	// Procesar el evento usando el handler
-	if err := h.consumer.handler.HandleExecutionAnalyticsEvent(ctx, execution); err != nil {
-		return fmt.Errorf("error handling event: %w", err)
+	// Marcar el último mensaje del lote como procesado
+	last := batch[len(batch)-1].message
+	if !h.markProcessed(session, claim, last, offsetStored) {
+		return false
 	}
 
-	log.Printf("Successfully processed execution analytics: %s", event.ExecutionID)
-	return nil
+	if len(batch) > 1 {
+		log.Printf("Flushed %s batch of %d messages from partition %d (last offset %d)",
+			route.Name, len(batch), claim.Partition(), last.Offset)
+	}
+	return true
 }
 
-// eventToDomain convierte el evento de Kafka a un aggregate de dominio
-func (h *consumerGroupHandler) eventToDomain(event *ExecutionAnalyticsEvent) (*aggregates.ExecutionAnalytics, error) {
-	// Crear value objects
-	executionID, err := valueobjects.NewExecutionID(event.ExecutionID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid execution ID: %w", err)
-	}
+// processBatch procesa el lote sin marcar su offset. Con transactional, el offset siguiente al lote se guarda
+// junto con los datos si la ruta lo permite. Retorna si el offset quedó guardado, y false si la sesión terminó
+// antes de completar.
+func (h *consumerGroupHandler) processBatch(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, route *Route, batch []*pendingMessage, transactional bool) (bool, bool) {
+	ctx := session.Context()
 
-	challengeID, err := valueobjects.NewChallengeID(event.ChallengeID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid challenge ID: %w", err)
+	values := make([]interface{}, 0, len(batch))
+	for _, pending := range batch {
+		if pending.decoded {
+			values = append(values, pending.value)
+		}
 	}
 
-	studentID, err := valueobjects.NewStudentID(event.StudentID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid student ID: %w", err)
+	// Offset del siguiente mensaje a leer después del lote
+	last := batch[len(batch)-1].message
+	offsetStored := false
+
+	if len(values) > 0 {
+		attempts, err := h.consumer.retrier.run(ctx, claim.Topic(), claim.Partition(), func(ctx context.Context) error {
+			return h.handle(ctx, route, values, last, transactional)
+		})
+		if err == nil {
+			offsetStored = transactional && h.storesOffsetWith(route)
+			for _, pending := range batch {
+				if pending.decoded {
+					h.recordProcessed(pending.message)
+				}
+			}
+		} else {
+			if ctx.Err() != nil {
+				log.Printf("Session ended while processing %s batch of %d messages: %v", route.Name, len(batch), err)
+				return false, false
+			}
+
+			if ClassifyError(err) == ErrorPermanent && len(values) > 1 {
+				// Un mensaje inválido no debe descartar el lote completo: procesar uno a uno
+				log.Printf("%s batch of %d messages rejected, processing individually: %v", route.Name, len(values), err)
+				if !h.processIndividually(session, claim, route, batch) {
+					return false, false
+				}
+			} else {
+				for _, pending := range batch {
+					if pending.decoded && !h.deadLetter(session, claim, pending.message, err, attempts) {
+						return false, false
+					}
+				}
+			}
+		}
 	}
 
-	language, err := valueobjects.NewProgrammingLanguage(event.Language)
-	if err != nil {
-		return nil, fmt.Errorf("invalid programming language: %w", err)
+	return offsetStored, true
+}
+
+// markProcessed marca como procesados los mensajes hasta last. Sin transacción con los datos (lote rechazado,
+// ruta sin HandleWithOffset o procesamiento en paralelo) el offset se guarda aparte en la base de datos.
+// Retorna false si la sesión terminó antes de guardarlo.
+func (h *consumerGroupHandler) markProcessed(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, last *sarama.ConsumerMessage, offsetStored bool) bool {
+	if h.consumer.offsetStore != nil && !offsetStored {
+		if !h.storeOffset(session.Context(), claim, last) {
+			return false
+		}
 	}
 
-	status, err := valueobjects.NewExecutionStatus(event.Status)
-	if err != nil {
-		return nil, fmt.Errorf("invalid execution status: %w", err)
-	}
-
-	// Crear aggregate
-	execution, err := aggregates.NewExecutionAnalytics(
-		executionID,
-		challengeID,
-		event.CodeVersionID,
-		studentID,
-		language,
-		status,
-		event.Timestamp,
-		event.ExecutionTimeMs,
-		event.ExitCode,
-		event.TotalTests,
-		event.PassedTests,
-		event.FailedTests,
-		event.Success,
-		event.ServerInstance,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error creating execution analytics aggregate: %w", err)
+	session.MarkMessage(last, "")
+	if !h.consumer.autoCommit {
+		session.Commit()
 	}
+	return true
+}
+
+// processIndividually procesa los mensajes del lote uno a uno para aislar los inválidos
+func (h *consumerGroupHandler) processIndividually(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, route *Route, batch []*pendingMessage) bool {
+	ctx := session.Context()
 
-	// Agregar test results
-	for _, tr := range event.TestResults {
-		testID, err := valueobjects.NewTestID(tr.TestID)
+	for _, pending := range batch {
+		if !pending.decoded {
+			continue
+		}
+
+		value := pending.value
+		message := pending.message
+		attempts, err := h.consumer.retrier.run(ctx, claim.Topic(), claim.Partition(), func(ctx context.Context) error {
+			return h.handle(ctx, route, []interface{}{value}, message, false)
+		})
 		if err != nil {
-			log.Printf("Warning: invalid test ID %s, skipping test result", tr.TestID)
+			if ctx.Err() != nil {
+				log.Printf("Session ended while retrying message (offset %d): %v", pending.message.Offset, err)
+				return false
+			}
+			if !h.deadLetter(session, claim, pending.message, err, attempts) {
+				return false
+			}
 			continue
 		}
+		h.recordProcessed(pending.message)
+	}
+
+	return true
+}
+
+// handle procesa los objetos con el handler de la ruta. Con transactional, si los offsets se guardan en la base
+// de datos y la ruta lo permite, el offset siguiente a last se guarda en la misma transacción.
+func (h *consumerGroupHandler) handle(ctx context.Context, route *Route, values []interface{}, last *sarama.ConsumerMessage, transactional bool) error {
+	if !transactional || !h.storesOffsetWith(route) {
+		return route.Handle(ctx, values)
+	}
+	return route.HandleWithOffset(ctx, values, h.nextOffset(last))
+}
 
-		testResult := entities.NewTestResult(
-			testID,
-			tr.TestName,
-			tr.Passed,
-			tr.ErrorMessage,
-		)
-		execution.AddTestResult(testResult)
+// storesOffsetWith indica si el handler de la ruta guarda el offset junto con los datos
+func (h *consumerGroupHandler) storesOffsetWith(route *Route) bool {
+	return h.consumer.offsetStore != nil && route.HandleWithOffset != nil
+}
+
+// nextOffset construye el offset a guardar después de procesar el mensaje
+func (h *consumerGroupHandler) nextOffset(message *sarama.ConsumerMessage) repositories.ConsumerOffset {
+	return repositories.ConsumerOffset{
+		GroupID:   h.consumer.groupID,
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset + 1,
+	}
+}
+
+// storeOffset guarda el offset siguiente al mensaje, reintentando los errores transitorios.
+// Retorna false si la sesión terminó antes de guardarlo.
+func (h *consumerGroupHandler) storeOffset(ctx context.Context, claim sarama.ConsumerGroupClaim, message *sarama.ConsumerMessage) bool {
+	offset := h.nextOffset(message)
+	_, err := h.consumer.retrier.run(ctx, claim.Topic(), claim.Partition(), func(ctx context.Context) error {
+		return h.consumer.offsetStore.Save(ctx, offset)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Printf("Session ended while storing offset %d for %s partition %d: %v", offset.Offset, offset.Topic, offset.Partition, err)
+			return false
+		}
+		// El mensaje ya fue resuelto: en el peor caso se vuelve a entregar tras un reinicio
+		log.Printf("Error storing offset %d for %s partition %d: %v", offset.Offset, offset.Topic, offset.Partition, err)
+	}
+	return true
+}
+
+// recordProcessed registra un mensaje procesado en las métricas de ingesta
+func (h *consumerGroupHandler) recordProcessed(message *sarama.ConsumerMessage) {
+	h.consumer.metrics.RecordProcessed(message.Topic, message.Partition, message.Offset, message.Timestamp)
+}
+
+// deadLetter envía un mensaje fallido al dead-letter, o solo lo registra si no está configurado.
+// Los errores transitorios que agotaron los reintentos no se envían, salvo con DeadLetterTransientErrors: la
+// dependencia caída (por ejemplo, la base de datos) suele ser también la de la cuarentena. En su lugar se reinicia
+// la sesión y el mensaje se reintenta en una nueva ronda, sin límite, hasta que la dependencia se recupera.
+// Si el mensaje no puede preservarse ni en el tópico ni en cuarentena, se reintenta con la partición pausada y,
+// agotados los reintentos, se reinicia la sesión sin marcar el offset para que el mensaje se vuelva a entregar.
+// Retorna false si el offset no debe marcarse.
+func (h *consumerGroupHandler) deadLetter(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, message *sarama.ConsumerMessage, err error, attempts int) bool {
+	if ClassifyError(err) == ErrorTransient && !h.consumer.deadLetterTransient {
+		log.Printf("Transient error processing message from topic %s, partition %d, offset %d after %d attempt(s); restarting session: %v",
+			message.Topic, message.Partition, message.Offset, attempts, err)
+		h.consumer.metrics.RecordError(message.Topic, message.Partition)
+		h.consumer.restartSession()
+		return false
+	}
+
+	log.Printf("Error processing message from topic %s (offset %d, %s error, %d attempt(s)): %v",
+		message.Topic, message.Offset, ClassifyError(err), attempts, err)
+	h.consumer.metrics.RecordError(message.Topic, message.Partition)
+
+	if h.consumer.deadLetter == nil {
+		return true
+	}
+
+	ctx := session.Context()
+	_, dlqErr := h.consumer.retrier.run(ctx, claim.Topic(), claim.Partition(), func(ctx context.Context) error {
+		return h.consumer.deadLetter.Publish(ctx, message, err, attempts)
+	})
+	if dlqErr == nil {
+		return true
+	}
+	if ctx.Err() != nil {
+		log.Printf("Session ended while sending message (offset %d) to dead-letter: %v", message.Offset, dlqErr)
+		return false
 	}
 
-	return execution, nil
+	log.Printf("Could not preserve message from topic %s, partition %d, offset %d in the dead-letter; restarting session: %v",
+		message.Topic, message.Partition, message.Offset, dlqErr)
+	h.consumer.restartSession()
+	return false
 }
@@ -0,0 +1,224 @@
+package kafka
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// consumerControl guarda las pausas y los reseteos de offsets pedidos por los administradores.
+// Se conserva entre sesiones del consumer group: las pausas se vuelven a aplicar tras cada rebalanceo.
+type consumerControl struct {
+	mu            sync.Mutex
+	paused        map[partitionKey]bool
+	retryPauses   map[partitionKey]int // Reintentos en curso que pausaron la partición
+	pendingResets map[partitionKey]int64
+	memberID      string
+	claims        map[string][]int32
+	cancelSession context.CancelFunc
+}
+
+// newConsumerControl crea el estado de control vacío
+func newConsumerControl() *consumerControl {
+	return &consumerControl{
+		paused:        make(map[partitionKey]bool),
+		retryPauses:   make(map[partitionKey]int),
+		pendingResets: make(map[partitionKey]int64),
+	}
+}
+
+// Pause detiene la lectura de las particiones asignadas a esta instancia. Los mensajes ya leídos del broker
+// se siguen procesando. La pausa se mantiene si la partición se reasigna a esta instancia.
+func (c *Consumer) Pause(topic string, partitions []int32) {
+	c.control.mu.Lock()
+	for _, partition := range partitions {
+		c.control.paused[partitionKey{topic: topic, partition: partition}] = true
+	}
+	c.control.mu.Unlock()
+
+	c.consumerGroup.Pause(map[string][]int32{topic: partitions})
+}
+
+// pauseForRetry pausa la partición mientras un mensaje espera su reintento.
+// Varios workers pueden reintentar en la misma partición: la pausa se cuenta por reintento.
+func (c *Consumer) pauseForRetry(topic string, partition int32) {
+	key := partitionKey{topic: topic, partition: partition}
+
+	c.control.mu.Lock()
+	c.control.retryPauses[key]++
+	first := c.control.retryPauses[key] == 1
+	c.control.mu.Unlock()
+
+	if first {
+		c.consumerGroup.Pause(map[string][]int32{topic: {partition}})
+	}
+}
+
+// resumeAfterRetry retoma la partición al terminar el último reintento en curso, salvo que un administrador la haya pausado
+func (c *Consumer) resumeAfterRetry(topic string, partition int32) {
+	key := partitionKey{topic: topic, partition: partition}
+
+	c.control.mu.Lock()
+	c.control.retryPauses[key]--
+	last := c.control.retryPauses[key] <= 0
+	if last {
+		delete(c.control.retryPauses, key)
+	}
+	paused := c.control.paused[key]
+	c.control.mu.Unlock()
+
+	if last && !paused {
+		c.consumerGroup.Resume(map[string][]int32{topic: {partition}})
+	}
+}
+
+// Resume retoma la lectura de las particiones
+func (c *Consumer) Resume(topic string, partitions []int32) {
+	c.control.mu.Lock()
+	for _, partition := range partitions {
+		delete(c.control.paused, partitionKey{topic: topic, partition: partition})
+	}
+	c.control.mu.Unlock()
+
+	c.consumerGroup.Resume(map[string][]int32{topic: partitions})
+}
+
+// PausedPartitions retorna las particiones pausadas, por tópico
+func (c *Consumer) PausedPartitions() map[string][]int32 {
+	c.control.mu.Lock()
+	defer c.control.mu.Unlock()
+
+	return groupPartitions(c.control.paused)
+}
+
+// isPaused indica si la partición está pausada
+func (c *Consumer) isPaused(topic string, partition int32) bool {
+	c.control.mu.Lock()
+	defer c.control.mu.Unlock()
+
+	return c.control.paused[partitionKey{topic: topic, partition: partition}]
+}
+
+// ScheduleOffsetReset programa el reseteo de los offsets de un tópico y reinicia la sesión del consumer group.
+// Los offsets se fijan al iniciar la nueva sesión en las particiones asignadas a esta instancia.
+func (c *Consumer) ScheduleOffsetReset(topic string, offsets map[int32]int64) {
+	c.control.mu.Lock()
+	for partition, offset := range offsets {
+		c.control.pendingResets[partitionKey{topic: topic, partition: partition}] = offset
+	}
+	cancelSession := c.control.cancelSession
+	c.control.mu.Unlock()
+
+	// Las particiones en curso leen desde su posición actual: el reseteo requiere una nueva sesión
+	if cancelSession != nil {
+		cancelSession()
+	}
+}
+
+// restartSession cancela la sesión actual del consumer group. Los mensajes sin marcar se vuelven a entregar
+// en la sesión siguiente.
+func (c *Consumer) restartSession() {
+	c.control.mu.Lock()
+	cancelSession := c.control.cancelSession
+	c.control.mu.Unlock()
+
+	if cancelSession != nil {
+		cancelSession()
+	}
+}
+
+// PendingOffsetResets retorna las particiones con un reseteo de offset por aplicar, por tópico
+func (c *Consumer) PendingOffsetResets() map[string][]int32 {
+	c.control.mu.Lock()
+	defer c.control.mu.Unlock()
+
+	pending := make(map[partitionKey]bool, len(c.control.pendingResets))
+	for key := range c.control.pendingResets {
+		pending[key] = true
+	}
+	return groupPartitions(pending)
+}
+
+// Membership retorna el ID de miembro y las particiones asignadas en la sesión actual
+func (c *Consumer) Membership() (string, map[string][]int32) {
+	c.control.mu.Lock()
+	defer c.control.mu.Unlock()
+
+	return c.control.memberID, c.control.claims
+}
+
+// newSession crea el contexto de una sesión del consumer group, que se cancela al programar un reseteo
+func (c *Consumer) newSession(ctx context.Context) (context.Context, context.CancelFunc) {
+	sessionCtx, cancel := context.WithCancel(ctx)
+
+	c.control.mu.Lock()
+	c.control.cancelSession = cancel
+	c.control.mu.Unlock()
+
+	return sessionCtx, cancel
+}
+
+// sessionStarted registra la asignación de la sesión y retorna los reseteos a aplicar.
+// Los reseteos de particiones no asignadas a esta instancia se descartan.
+func (c *Consumer) sessionStarted(memberID string, claims map[string][]int32) (map[partitionKey]int64, []partitionKey) {
+	c.control.mu.Lock()
+	defer c.control.mu.Unlock()
+
+	c.control.memberID = memberID
+	c.control.claims = claims
+
+	resets := make(map[partitionKey]int64)
+	var skipped []partitionKey
+	for key, offset := range c.control.pendingResets {
+		if containsPartition(claims[key.topic], key.partition) {
+			resets[key] = offset
+		} else {
+			skipped = append(skipped, key)
+		}
+	}
+	c.control.pendingResets = make(map[partitionKey]int64)
+	return resets, skipped
+}
+
+// requeueOffsetResets vuelve a programar reseteos que no pudieron aplicarse
+func (c *Consumer) requeueOffsetResets(resets map[partitionKey]int64) {
+	c.control.mu.Lock()
+	defer c.control.mu.Unlock()
+
+	for key, offset := range resets {
+		if _, exists := c.control.pendingResets[key]; !exists {
+			c.control.pendingResets[key] = offset
+		}
+	}
+}
+
+// sessionEnded limpia la asignación de la sesión
+func (c *Consumer) sessionEnded() {
+	c.control.mu.Lock()
+	defer c.control.mu.Unlock()
+
+	c.control.memberID = ""
+	c.control.claims = nil
+}
+
+// groupPartitions agrupa un conjunto de particiones por tópico, ordenadas
+func groupPartitions(set map[partitionKey]bool) map[string][]int32 {
+	grouped := make(map[string][]int32)
+	for key := range set {
+		grouped[key.topic] = append(grouped[key.topic], key.partition)
+	}
+	for _, partitions := range grouped {
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	}
+	return grouped
+}
+
+// containsPartition indica si la partición está en la lista
+func containsPartition(partitions []int32, partition int32) bool {
+	for _, p := range partitions {
+		if p == partition {
+			return true
+		}
+	}
+	return false
+}
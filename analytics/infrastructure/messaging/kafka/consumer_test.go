@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/source"
+
+	"github.com/IBM/sarama"
+)
+
+// testRoute crea una ruta que descarta los mensajes
+func testRoute(topic string, eventTypes ...string) Route {
+	return Route{
+		Topic:      topic,
+		EventTypes: eventTypes,
+		Decode: func(ctx context.Context, message *sarama.ConsumerMessage) (interface{}, error) {
+			return message.Value, nil
+		},
+		Handle: func(ctx context.Context, values []interface{}) error {
+			return nil
+		},
+	}
+}
+
+func TestUnregisterRemovesRouteAndEventTypes(t *testing.T) {
+	consumer := NewSourceConsumer(source.NewMemorySource(1), &ConsumerConfig{}, nil)
+	if err := consumer.Register(testRoute("executions", "execution.analytics")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := consumer.Register(testRoute("challenges", "challenge.created")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	consumer.Unregister("challenges")
+	consumer.Unregister("unknown")
+
+	if topics := consumer.Topics(); len(topics) != 1 || topics[0] != "executions" {
+		t.Fatalf("expected only the executions topic, got %v", topics)
+	}
+
+	// El tipo de evento queda libre: otra ruta puede declararlo
+	if err := consumer.Register(testRoute("catalog", "challenge.created")); err != nil {
+		t.Fatalf("expected event type to be released: %v", err)
+	}
+}
+
+func TestRouteForDispatchesByEventType(t *testing.T) {
+	consumer := NewSourceConsumer(source.NewMemorySource(1), &ConsumerConfig{}, nil)
+	if err := consumer.Register(testRoute("executions", "execution.analytics")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := consumer.Register(testRoute("catalog", "challenge.created", "code_version.created")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := consumer.Register(testRoute("users")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		topic string
+		value string
+		want  string
+		err   bool
+	}{
+		{"legacy event uses the topic route", "catalog", `{"challengeId":"c1"}`, "catalog", false},
+		{"declared event type of the topic", "catalog", `{"type":"code_version.created","schema_version":1,"payload":{}}`, "catalog", false},
+		{"event type of another topic", "catalog", `{"type":"execution.analytics","schema_version":1,"payload":{}}`, "executions", false},
+		{"unknown event type on a typed topic", "catalog", `{"type":"challenge.deleted","schema_version":1,"payload":{}}`, "catalog", true},
+		{"unknown event type on an untyped topic", "users", `{"type":"user.registered","schema_version":1,"payload":{}}`, "users", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, err := consumer.routeFor(&sarama.ConsumerMessage{Topic: tt.topic, Value: []byte(tt.value)})
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error=%t, got %v", tt.err, err)
+			}
+			if route.Topic != tt.want {
+				t.Fatalf("expected route for %s, got %s", tt.want, route.Topic)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+
+	"github.com/IBM/sarama"
+)
+
+// Headers agregados a los mensajes enviados al tópico de dead-letter
+const (
+	HeaderOriginalTopic     = "dlq.original.topic"
+	HeaderOriginalPartition = "dlq.original.partition"
+	HeaderOriginalOffset    = "dlq.original.offset"
+	HeaderError             = "dlq.error"
+	HeaderAttempts          = "dlq.attempts"
+	HeaderFailedAt          = "dlq.failed.at"
+)
+
+// DeadLetterPublisher reenvía los mensajes que no pudieron procesarse a un tópico de
+// dead-letter y los guarda en la tabla de cuarentena
+type DeadLetterPublisher struct {
+	producer   sarama.SyncProducer
+	topic      string
+	repository repositories.QuarantinedEventRepository
+}
+
+// NewDeadLetterPublisher crea un nuevo publicador de dead-letter usando la misma configuración de seguridad del consumidor
+func NewDeadLetterPublisher(cfg *ConsumerConfig, topic string, repository repositories.QuarantinedEventRepository) (*DeadLetterPublisher, error) {
+	config, err := NewSaramaConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kafka configuration: %w", err)
+	}
+	config.Producer.Return.Successes = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 3
+
+	producer, err := sarama.NewSyncProducer(cfg.Brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dead-letter producer: %w", err)
+	}
+
+	log.Printf("Dead-letter producer created successfully for topic: %s", topic)
+
+	return &DeadLetterPublisher{
+		producer:   producer,
+		topic:      topic,
+		repository: repository,
+	}, nil
+}
+
+// Publish envía el mensaje al tópico de dead-letter y lo registra en cuarentena.
+// Solo retorna error si el mensaje no pudo preservarse en ninguno de los dos destinos.
+func (p *DeadLetterPublisher) Publish(ctx context.Context, message *sarama.ConsumerMessage, cause error, attempts int) error {
+	failedAt := time.Now().UTC()
+
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+6)
+	for _, h := range message.Headers {
+		if h != nil {
+			headers = append(headers, *h)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderOriginalTopic), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalPartition), Value: []byte(strconv.Itoa(int(message.Partition)))},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalOffset), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		sarama.RecordHeader{Key: []byte(HeaderError), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderAttempts), Value: []byte(strconv.Itoa(attempts))},
+		sarama.RecordHeader{Key: []byte(HeaderFailedAt), Value: []byte(failedAt.Format(time.RFC3339Nano))},
+	)
+
+	producerMessage := &sarama.ProducerMessage{
+		Topic:   p.topic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	if message.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(message.Key)
+	}
+
+	_, _, publishErr := p.producer.SendMessage(producerMessage)
+	if publishErr != nil {
+		log.Printf("Error publishing message to dead-letter topic %s (topic %s, partition %d, offset %d): %v",
+			p.topic, message.Topic, message.Partition, message.Offset, publishErr)
+	}
+
+	quarantineErr := p.repository.Save(ctx, &repositories.QuarantinedEvent{
+		Topic:           message.Topic,
+		Partition:       message.Partition,
+		Offset:          message.Offset,
+		Key:             string(message.Key),
+		Payload:         message.Value,
+		Error:           cause.Error(),
+		Attempts:        attempts,
+		DeadLetterTopic: p.topic,
+		QuarantinedAt:   failedAt,
+	})
+	if quarantineErr != nil {
+		log.Printf("Error saving quarantined event (topic %s, partition %d, offset %d): %v",
+			message.Topic, message.Partition, message.Offset, quarantineErr)
+	}
+
+	if publishErr != nil && quarantineErr != nil {
+		return fmt.Errorf("error sending message to dead-letter: publish: %v, quarantine: %w", publishErr, quarantineErr)
+	}
+
+	log.Printf("Message from topic %s, partition %d, offset %d sent to dead-letter after %d attempt(s): %v",
+		message.Topic, message.Partition, message.Offset, attempts, cause)
+	return nil
+}
+
+// Close cierra el productor de dead-letter
+func (p *DeadLetterPublisher) Close() error {
+	log.Println("Closing dead-letter producer...")
+	return p.producer.Close()
+}
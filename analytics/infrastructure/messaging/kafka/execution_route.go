@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+
+	"github.com/IBM/sarama"
+)
+
+// EventHandler define el contrato para procesar eventos
+type EventHandler interface {
+	HandleExecutionAnalyticsEvent(ctx context.Context, execution *aggregates.ExecutionAnalytics) error
+	HandleExecutionAnalyticsBatch(ctx context.Context, executions []*aggregates.ExecutionAnalytics) error
+	HandleExecutionAnalyticsBatchWithOffset(ctx context.Context, executions []*aggregates.ExecutionAnalytics, offset repositories.ConsumerOffset) error
+}
+
+// Valores por defecto de los micro-lotes de ingesta de ejecuciones
+const (
+	defaultBatchSize    = 100
+	defaultBatchTimeout = 500 * time.Millisecond
+)
+
+// NewExecutionAnalyticsRoute crea la ruta para los eventos de ejecución de código y sus re-evaluaciones,
+// procesados en micro-lotes.
+// payloadDecoder convierte el formato del tópico (JSON, Avro, Protobuf) al JSON del evento y languages
+// normaliza el lenguaje de las ejecuciones.
+func NewExecutionAnalyticsRoute(topic string, handler EventHandler, payloadDecoder events.PayloadDecoder, languages *valueobjects.LanguageRegistry, batchSize int, batchTimeout time.Duration) Route {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	if batchTimeout <= 0 {
+		batchTimeout = defaultBatchTimeout
+	}
+
+	return Route{
+		Topic:        topic,
+		Name:         "execution analytics",
+		EventTypes:   []string{events.ExecutionAnalyticsEventType, events.ExecutionAnalyticsUpdatedEventType},
+		Decode:       executionAnalyticsDecoder(payloadDecoder, languages),
+		BatchSize:    batchSize,
+		BatchTimeout: batchTimeout,
+		Handle: func(ctx context.Context, values []interface{}) error {
+			executions := toExecutions(values)
+			if len(executions) == 1 {
+				return handler.HandleExecutionAnalyticsEvent(ctx, executions[0])
+			}
+			return handler.HandleExecutionAnalyticsBatch(ctx, executions)
+		},
+		HandleWithOffset: func(ctx context.Context, values []interface{}, offset repositories.ConsumerOffset) error {
+			return handler.HandleExecutionAnalyticsBatchWithOffset(ctx, toExecutions(values), offset)
+		},
+		OrderingKey: func(value interface{}) string {
+			return value.(*aggregates.ExecutionAnalytics).StudentID().Value()
+		},
+	}
+}
+
+// toExecutions convierte los objetos decodificados del lote a aggregates
+func toExecutions(values []interface{}) []*aggregates.ExecutionAnalytics {
+	executions := make([]*aggregates.ExecutionAnalytics, 0, len(values))
+	for _, value := range values {
+		executions = append(executions, value.(*aggregates.ExecutionAnalytics))
+	}
+	return executions
+}
+
+// executionAnalyticsDecoder deserializa el mensaje y lo convierte a dominio
+func executionAnalyticsDecoder(payloadDecoder events.PayloadDecoder, languages *valueobjects.LanguageRegistry) Decoder {
+	return func(ctx context.Context, message *sarama.ConsumerMessage) (interface{}, error) {
+		envelope, err := decodeEnvelope(ctx, payloadDecoder, message, events.ExecutionAnalyticsEventType)
+		if err != nil {
+			return nil, err
+		}
+		return events.DecodeExecutionAnalytics(envelope, languages)
+	}
+}
@@ -0,0 +1,264 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/commandservices"
+	"github.com/nanab/analytics-service/analytics/application/queryservices"
+
+	"github.com/IBM/sarama"
+)
+
+// PausePartitions detiene la lectura de las particiones del tópico asignadas a esta instancia
+func (m *IngestionMonitor) PausePartitions(topic string, partitions []int32) ([]int32, error) {
+	resolved, err := m.resolvePartitions(topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	m.consumer.Pause(topic, resolved)
+	return resolved, nil
+}
+
+// ResumePartitions retoma la lectura de las particiones del tópico
+func (m *IngestionMonitor) ResumePartitions(topic string, partitions []int32) ([]int32, error) {
+	resolved, err := m.resolvePartitions(topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	m.consumer.Resume(topic, resolved)
+	return resolved, nil
+}
+
+// ResetOffsets calcula el offset de destino de cada partición según la estrategia y lo programa en el consumidor.
+// Los offsets se aplican al reincorporarse la instancia al consumer group, en las particiones que tenga asignadas.
+func (m *IngestionMonitor) ResetOffsets(ctx context.Context, request commandservices.OffsetResetRequest) ([]commandservices.PartitionOffsetReset, error) {
+	partitions, err := m.resolvePartitions(request.Topic, request.Partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	client, _, err := m.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make(map[int32]int64, len(partitions))
+	resets := make([]commandservices.PartitionOffsetReset, 0, len(partitions))
+	for _, partition := range partitions {
+		offset, err := resetOffset(client, request, partition)
+		if err != nil {
+			return nil, err
+		}
+
+		offsets[partition] = offset
+		resets = append(resets, commandservices.PartitionOffsetReset{
+			Topic:     request.Topic,
+			Partition: partition,
+			Offset:    offset,
+		})
+	}
+
+	m.consumer.ScheduleOffsetReset(request.Topic, offsets)
+	return resets, nil
+}
+
+// SeedOffsetsFromGroup copia al consumer group los offsets confirmados por otro grupo en las particiones
+// del tópico que el grupo aún no consumió, y los programa en el consumidor. Sirve para migrar un tópico
+// desde un consumer group propio sin saltarse los mensajes publicados durante el cambio. Las particiones
+// con offset confirmado (o guardado en la base de datos) no se tocan, por lo que solo tiene efecto una vez.
+func (m *IngestionMonitor) SeedOffsetsFromGroup(ctx context.Context, topic, fromGroup string) (map[int32]int64, error) {
+	partitions, err := m.resolvePartitions(topic, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	_, admin, err := m.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	topicPartitions := map[string][]int32{topic: partitions}
+	current, err := admin.ListConsumerGroupOffsets(m.cfg.GroupID, topicPartitions)
+	if err != nil {
+		return nil, fmt.Errorf("error listing offsets for group %s: %w", m.cfg.GroupID, err)
+	}
+	previous, err := admin.ListConsumerGroupOffsets(fromGroup, topicPartitions)
+	if err != nil {
+		return nil, fmt.Errorf("error listing offsets for group %s: %w", fromGroup, err)
+	}
+
+	var stored map[int32]int64
+	if m.consumer.offsetStore != nil {
+		stored, err = m.consumer.offsetStore.FindByGroupAndTopic(ctx, m.cfg.GroupID, topic)
+		if err != nil {
+			return nil, fmt.Errorf("error loading stored offsets for topic %s: %w", topic, err)
+		}
+	}
+
+	offsets := make(map[int32]int64)
+	for _, partition := range partitions {
+		if block := current.GetBlock(topic, partition); block != nil && block.Offset >= 0 {
+			continue
+		}
+		if _, ok := stored[partition]; ok {
+			continue
+		}
+		if block := previous.GetBlock(topic, partition); block != nil && block.Offset >= 0 {
+			offsets[partition] = block.Offset
+		}
+	}
+
+	if len(offsets) > 0 {
+		m.consumer.ScheduleOffsetReset(topic, offsets)
+	}
+	return offsets, nil
+}
+
+// SkipMissingTopics quita del consumidor las rutas de los tópicos indicados que no existen en el broker,
+// para que un tópico opcional sin crear no impida consumir los demás. Debe llamarse antes de Start.
+// Retorna los tópicos omitidos.
+func (m *IngestionMonitor) SkipMissingTopics(topics ...string) ([]string, error) {
+	_, admin, err := m.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := admin.ListTopics()
+	if err != nil {
+		return nil, fmt.Errorf("error listing topics: %w", err)
+	}
+
+	var skipped []string
+	for _, topic := range topics {
+		if _, ok := existing[topic]; ok {
+			continue
+		}
+		m.consumer.Unregister(topic)
+		skipped = append(skipped, topic)
+	}
+	return skipped, nil
+}
+
+// ConsumerGroup obtiene los miembros del consumer group con sus particiones asignadas, y las pausas y
+// reseteos pendientes de esta instancia
+func (m *IngestionMonitor) ConsumerGroup(ctx context.Context) (*queryservices.ConsumerGroupStatus, error) {
+	_, admin, err := m.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions, err := admin.DescribeConsumerGroups([]string{m.cfg.GroupID})
+	if err != nil {
+		return nil, fmt.Errorf("error describing group %s: %w", m.cfg.GroupID, err)
+	}
+	if len(descriptions) == 0 {
+		return nil, fmt.Errorf("group %s not found", m.cfg.GroupID)
+	}
+	description := descriptions[0]
+	if description.Err != sarama.ErrNoError {
+		return nil, fmt.Errorf("error describing group %s: %w", m.cfg.GroupID, description.Err)
+	}
+
+	localMemberID, _ := m.consumer.Membership()
+
+	status := &queryservices.ConsumerGroupStatus{
+		GroupID:       description.GroupId,
+		State:         description.State,
+		Protocol:      description.Protocol,
+		Members:       make([]queryservices.ConsumerGroupMember, 0, len(description.Members)),
+		Paused:        m.consumer.PausedPartitions(),
+		PendingResets: m.consumer.PendingOffsetResets(),
+		ObservedAt:    time.Now().UTC(),
+	}
+
+	for memberID, member := range description.Members {
+		assignments := make(map[string][]int32)
+		if assignment, err := member.GetMemberAssignment(); err == nil && assignment != nil {
+			assignments = assignment.Topics
+		}
+
+		status.Members = append(status.Members, queryservices.ConsumerGroupMember{
+			MemberID:    memberID,
+			ClientID:    member.ClientId,
+			ClientHost:  member.ClientHost,
+			Local:       memberID == localMemberID,
+			Assignments: assignments,
+		})
+	}
+	sort.Slice(status.Members, func(i, j int) bool { return status.Members[i].MemberID < status.Members[j].MemberID })
+
+	return status, nil
+}
+
+// resolvePartitions valida que el tópico esté registrado y que las particiones existan.
+// Sin particiones, retorna todas las del tópico.
+func (m *IngestionMonitor) resolvePartitions(topic string, partitions []int32) ([]int32, error) {
+	if _, ok := m.consumer.routes[topic]; !ok {
+		return nil, fmt.Errorf("%w: %s", commandservices.ErrUnknownConsumerTopic, topic)
+	}
+
+	client, _, err := m.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("error getting partitions for topic %s: %w", topic, err)
+	}
+	if len(partitions) == 0 {
+		return available, nil
+	}
+
+	for _, partition := range partitions {
+		if !containsPartition(available, partition) {
+			return nil, fmt.Errorf("%w: partition %d of topic %s", commandservices.ErrUnknownPartition, partition, topic)
+		}
+	}
+	return partitions, nil
+}
+
+// resetOffset calcula el offset de destino de una partición según la estrategia del reseteo
+func resetOffset(client sarama.Client, request commandservices.OffsetResetRequest, partition int32) (int64, error) {
+	topic := request.Topic
+
+	oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, fmt.Errorf("error getting oldest offset of %s/%d: %w", topic, partition, err)
+	}
+	newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, fmt.Errorf("error getting newest offset of %s/%d: %w", topic, partition, err)
+	}
+
+	switch request.Strategy {
+	case commandservices.OffsetResetEarliest:
+		return oldest, nil
+	case commandservices.OffsetResetLatest:
+		return newest, nil
+	case commandservices.OffsetResetTimestamp:
+		offset, err := client.GetOffset(topic, partition, request.Timestamp.UnixMilli())
+		if err != nil {
+			return 0, fmt.Errorf("error getting offset for time of %s/%d: %w", topic, partition, err)
+		}
+		if offset < 0 {
+			// No hay mensajes desde esa fecha
+			return newest, nil
+		}
+		return offset, nil
+	case commandservices.OffsetResetOffset:
+		offset := *request.Offset
+		if offset < oldest || offset > newest {
+			return 0, fmt.Errorf("%w: offset %d of %s/%d is out of range [%d, %d]",
+				commandservices.ErrInvalidOffsetReset, offset, topic, partition, oldest, newest)
+		}
+		return offset, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown strategy %s", commandservices.ErrInvalidOffsetReset, request.Strategy)
+	}
+}
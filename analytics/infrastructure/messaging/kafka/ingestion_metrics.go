@@ -0,0 +1,123 @@
+package kafka
+
+import (
+	"sync"
+	"time"
+)
+
+// rateWindowSeconds es la ventana usada para calcular mensajes por segundo
+const rateWindowSeconds = 60
+
+// PartitionMetrics es una instantánea de las métricas de procesamiento de una partición
+type PartitionMetrics struct {
+	Topic              string
+	Partition          int32
+	LastOffset         int64
+	LastProcessedAt    time.Time
+	LastEventTimestamp time.Time
+	ProcessedCount     int64
+	ErrorCount         int64
+	MessagesPerSecond  float64
+}
+
+// partitionKey identifica una partición de un tópico
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+// partitionCounters acumula las métricas de una partición
+type partitionCounters struct {
+	lastOffset         int64
+	lastProcessedAt    time.Time
+	lastEventTimestamp time.Time
+	processed          int64
+	errors             int64
+	// Contadores por segundo en una ventana circular
+	buckets     [rateWindowSeconds]int64
+	bucketTimes [rateWindowSeconds]int64
+}
+
+// IngestionMetrics registra en memoria las métricas de procesamiento del consumidor
+type IngestionMetrics struct {
+	mu         sync.Mutex
+	partitions map[partitionKey]*partitionCounters
+}
+
+// NewIngestionMetrics crea un nuevo registro de métricas
+func NewIngestionMetrics() *IngestionMetrics {
+	return &IngestionMetrics{
+		partitions: make(map[partitionKey]*partitionCounters),
+	}
+}
+
+// RecordProcessed registra un mensaje procesado (guardado o descartado por duplicado)
+func (m *IngestionMetrics) RecordProcessed(topic string, partition int32, offset int64, eventTimestamp time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	counters := m.counters(topic, partition)
+	counters.processed++
+	counters.lastOffset = offset
+	counters.lastProcessedAt = now
+	if eventTimestamp.After(counters.lastEventTimestamp) {
+		counters.lastEventTimestamp = eventTimestamp
+	}
+
+	second := now.Unix()
+	bucket := second % rateWindowSeconds
+	if counters.bucketTimes[bucket] != second {
+		counters.bucketTimes[bucket] = second
+		counters.buckets[bucket] = 0
+	}
+	counters.buckets[bucket]++
+}
+
+// RecordError registra un mensaje que no pudo procesarse
+func (m *IngestionMetrics) RecordError(topic string, partition int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters(topic, partition).errors++
+}
+
+// Snapshot retorna las métricas actuales de todas las particiones
+func (m *IngestionMetrics) Snapshot() []PartitionMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().Unix()
+	result := make([]PartitionMetrics, 0, len(m.partitions))
+	for key, counters := range m.partitions {
+		var recent int64
+		for i := range counters.buckets {
+			if now-counters.bucketTimes[i] < rateWindowSeconds {
+				recent += counters.buckets[i]
+			}
+		}
+
+		result = append(result, PartitionMetrics{
+			Topic:              key.topic,
+			Partition:          key.partition,
+			LastOffset:         counters.lastOffset,
+			LastProcessedAt:    counters.lastProcessedAt,
+			LastEventTimestamp: counters.lastEventTimestamp,
+			ProcessedCount:     counters.processed,
+			ErrorCount:         counters.errors,
+			MessagesPerSecond:  float64(recent) / rateWindowSeconds,
+		})
+	}
+	return result
+}
+
+// counters obtiene o crea los contadores de una partición. Debe llamarse con el lock tomado.
+func (m *IngestionMetrics) counters(topic string, partition int32) *partitionCounters {
+	key := partitionKey{topic: topic, partition: partition}
+	counters, ok := m.partitions[key]
+	if !ok {
+		counters = &partitionCounters{lastOffset: -1}
+		m.partitions[key] = counters
+	}
+	return counters
+}
@@ -0,0 +1,201 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/queryservices"
+
+	"github.com/IBM/sarama"
+)
+
+// IngestionMonitor combina los offsets del broker con las métricas del consumidor y controla su consumo.
+// Implementa queryservices.IngestionStatusProvider y commandservices.ConsumerController.
+type IngestionMonitor struct {
+	cfg      *ConsumerConfig
+	consumer *Consumer
+
+	mu     sync.Mutex
+	client sarama.Client
+	admin  sarama.ClusterAdmin
+}
+
+// NewIngestionMonitor crea un nuevo monitor para los tópicos registrados en el consumidor.
+// La conexión al broker se establece en la primera consulta.
+func NewIngestionMonitor(cfg *ConsumerConfig, consumer *Consumer) *IngestionMonitor {
+	return &IngestionMonitor{
+		cfg:      cfg,
+		consumer: consumer,
+	}
+}
+
+// IngestionStatus obtiene, por tópico y partición, el offset confirmado, el high-water mark, el lag
+// y las métricas de procesamiento
+func (m *IngestionMonitor) IngestionStatus(ctx context.Context) (*queryservices.IngestionStatus, error) {
+	client, admin, err := m.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	topics := m.consumer.Topics()
+	sort.Strings(topics)
+
+	// Particiones de cada tópico
+	topicPartitions := make(map[string][]int32, len(topics))
+	for _, topic := range topics {
+		if err := client.RefreshMetadata(topic); err != nil {
+			return nil, fmt.Errorf("error refreshing metadata for topic %s: %w", topic, err)
+		}
+		partitions, err := client.Partitions(topic)
+		if err != nil {
+			return nil, fmt.Errorf("error getting partitions for topic %s: %w", topic, err)
+		}
+		topicPartitions[topic] = partitions
+	}
+
+	committed, err := admin.ListConsumerGroupOffsets(m.cfg.GroupID, topicPartitions)
+	if err != nil {
+		return nil, fmt.Errorf("error listing offsets for group %s: %w", m.cfg.GroupID, err)
+	}
+
+	paused := make(map[partitionKey]bool)
+	for topic, partitions := range m.consumer.PausedPartitions() {
+		for _, partition := range partitions {
+			paused[partitionKey{topic: topic, partition: partition}] = true
+		}
+	}
+
+	metrics := make(map[partitionKey]PartitionMetrics)
+	for _, partitionMetrics := range m.consumer.Metrics().Snapshot() {
+		metrics[partitionKey{topic: partitionMetrics.Topic, partition: partitionMetrics.Partition}] = partitionMetrics
+	}
+
+	status := &queryservices.IngestionStatus{
+		GroupID:    m.cfg.GroupID,
+		Topics:     make([]queryservices.TopicIngestionStatus, 0, len(topics)),
+		ObservedAt: time.Now().UTC(),
+	}
+
+	for _, topic := range topics {
+		topicStatus := queryservices.TopicIngestionStatus{
+			Topic:      topic,
+			Consumer:   m.consumer.routes[topic].Name,
+			Partitions: make([]queryservices.PartitionIngestionStatus, 0, len(topicPartitions[topic])),
+		}
+
+		for _, partition := range topicPartitions[topic] {
+			partitionStatus, err := m.partitionStatus(client, committed, metrics, topic, partition)
+			if err != nil {
+				return nil, err
+			}
+			partitionStatus.Paused = paused[partitionKey{topic: topic, partition: partition}]
+
+			topicStatus.Lag += partitionStatus.Lag
+			topicStatus.MessagesPerSecond += partitionStatus.MessagesPerSecond
+			topicStatus.ProcessedCount += partitionStatus.ProcessedCount
+			topicStatus.ErrorCount += partitionStatus.ErrorCount
+			topicStatus.Partitions = append(topicStatus.Partitions, partitionStatus)
+		}
+
+		status.Topics = append(status.Topics, topicStatus)
+	}
+
+	return status, nil
+}
+
+// partitionStatus calcula el estado de una partición. Sin offset confirmado, el lag se mide desde el offset más antiguo.
+func (m *IngestionMonitor) partitionStatus(client sarama.Client, committed *sarama.OffsetFetchResponse, metrics map[partitionKey]PartitionMetrics, topic string, partition int32) (queryservices.PartitionIngestionStatus, error) {
+	highWaterMark, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return queryservices.PartitionIngestionStatus{}, fmt.Errorf("error getting high-water mark for %s/%d: %w", topic, partition, err)
+	}
+
+	committedOffset := int64(-1)
+	if block := committed.GetBlock(topic, partition); block != nil && block.Err == sarama.ErrNoError {
+		committedOffset = block.Offset
+	}
+
+	lagFrom := committedOffset
+	if lagFrom < 0 {
+		lagFrom, err = client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return queryservices.PartitionIngestionStatus{}, fmt.Errorf("error getting oldest offset for %s/%d: %w", topic, partition, err)
+		}
+	}
+
+	lag := highWaterMark - lagFrom
+	if lag < 0 {
+		lag = 0
+	}
+
+	partitionStatus := queryservices.PartitionIngestionStatus{
+		Partition:       partition,
+		CommittedOffset: committedOffset,
+		HighWaterMark:   highWaterMark,
+		Lag:             lag,
+	}
+
+	if partitionMetrics, ok := metrics[partitionKey{topic: topic, partition: partition}]; ok {
+		partitionStatus.MessagesPerSecond = partitionMetrics.MessagesPerSecond
+		partitionStatus.ProcessedCount = partitionMetrics.ProcessedCount
+		partitionStatus.ErrorCount = partitionMetrics.ErrorCount
+		if !partitionMetrics.LastProcessedAt.IsZero() {
+			lastProcessedAt := partitionMetrics.LastProcessedAt.UTC()
+			partitionStatus.LastProcessedAt = &lastProcessedAt
+		}
+		if !partitionMetrics.LastEventTimestamp.IsZero() {
+			lastEventTimestamp := partitionMetrics.LastEventTimestamp.UTC()
+			partitionStatus.LastEventTimestamp = &lastEventTimestamp
+		}
+	}
+
+	return partitionStatus, nil
+}
+
+// connect crea el cliente y el cluster admin, o reutiliza los existentes
+func (m *IngestionMonitor) connect() (sarama.Client, sarama.ClusterAdmin, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.client != nil && !m.client.Closed() {
+		return m.client, m.admin, nil
+	}
+
+	config, err := NewSaramaConfig(m.cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating Kafka configuration: %w", err)
+	}
+
+	client, err := sarama.NewClient(m.cfg.Brokers, config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating Kafka client: %w", err)
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("error creating Kafka cluster admin: %w", err)
+	}
+
+	m.client = client
+	m.admin = admin
+	return client, admin, nil
+}
+
+// Close cierra la conexión del monitor
+func (m *IngestionMonitor) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.admin == nil {
+		return nil
+	}
+	// Cerrar el admin también cierra el cliente subyacente
+	err := m.admin.Close()
+	m.admin = nil
+	m.client = nil
+	return err
+}
@@ -0,0 +1,221 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+
+	"github.com/IBM/sarama"
+)
+
+// KafkaSource lee los tópicos de Kafka partición por partición, sin consumer group. Implementa events.MessageSource.
+type KafkaSource struct {
+	brokers []string
+	config  *sarama.Config
+}
+
+// NewKafkaSource crea una fuente que lee de los brokers con la configuración de conexión y seguridad indicada
+func NewKafkaSource(brokers []string, config *sarama.Config) *KafkaSource {
+	return &KafkaSource{
+		brokers: brokers,
+		config:  config,
+	}
+}
+
+// Partitions obtiene el rango de offsets de cada partición del tópico. Si se indica una ventana de tiempo,
+// el rango va del primer mensaje con timestamp >= from al primer mensaje con timestamp >= to
+// (API offsets-for-times de Kafka).
+func (s *KafkaSource) Partitions(ctx context.Context, topic string, from, to *time.Time) ([]events.PartitionRange, error) {
+	client, err := sarama.NewClient(s.brokers, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kafka client: %w", err)
+	}
+	defer client.Close()
+
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("error getting partitions: %w", err)
+	}
+
+	ranges := make([]events.PartitionRange, 0, len(partitions))
+	for _, partition := range partitions {
+		oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return nil, fmt.Errorf("error getting oldest offset of partition %d: %w", partition, err)
+		}
+		newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return nil, fmt.Errorf("error getting newest offset of partition %d: %w", partition, err)
+		}
+
+		start, end := oldest, newest
+		if from != nil {
+			if start, err = offsetForTime(client, topic, partition, *from, newest); err != nil {
+				return nil, err
+			}
+		}
+		if to != nil {
+			if end, err = offsetForTime(client, topic, partition, *to, newest); err != nil {
+				return nil, err
+			}
+		}
+		if end < start {
+			end = start
+		}
+
+		ranges = append(ranges, events.PartitionRange{Partition: partition, Start: start, End: end})
+	}
+	return ranges, nil
+}
+
+// offsetForTime obtiene el offset del primer mensaje con timestamp >= t, o newest si no hay ninguno
+func offsetForTime(client sarama.Client, topic string, partition int32, t time.Time, newest int64) (int64, error) {
+	offset, err := client.GetOffset(topic, partition, t.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("error getting offset for time %s of partition %d: %w", t.Format(time.RFC3339), partition, err)
+	}
+	if offset < 0 {
+		return newest, nil
+	}
+	return offset, nil
+}
+
+// Read consume la partición desde offset. Si los mensajes desde offset ya fueron eliminados por la retención
+// del tópico, continúa desde el offset más antiguo.
+func (s *KafkaSource) Read(ctx context.Context, topic string, partition int32, offset int64) (events.MessageReader, error) {
+	client, err := sarama.NewClient(s.brokers, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kafka client: %w", err)
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error creating Kafka consumer: %w", err)
+	}
+
+	partitionConsumer, err := consumer.ConsumePartition(topic, partition, offset)
+	if errors.Is(err, sarama.ErrOffsetOutOfRange) {
+		oldest, offsetErr := client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if offsetErr != nil {
+			consumer.Close()
+			client.Close()
+			return nil, fmt.Errorf("error getting oldest offset of partition %d: %w", partition, offsetErr)
+		}
+		log.Printf("Offset %d of topic %s partition %d is no longer available, continuing from %d", offset, topic, partition, oldest)
+		partitionConsumer, err = consumer.ConsumePartition(topic, partition, oldest)
+	}
+	if err != nil {
+		consumer.Close()
+		client.Close()
+		return nil, fmt.Errorf("error consuming partition %d: %w", partition, err)
+	}
+
+	reader := &kafkaReader{
+		client:            client,
+		consumer:          consumer,
+		partitionConsumer: partitionConsumer,
+		messages:          make(chan *events.Message),
+		errors:            make(chan error, 1),
+		done:              make(chan struct{}),
+	}
+	go reader.forward()
+	return reader, nil
+}
+
+// kafkaReader convierte los mensajes de un consumidor de partición de sarama. Implementa events.MessageReader.
+type kafkaReader struct {
+	client            sarama.Client
+	consumer          sarama.Consumer
+	partitionConsumer sarama.PartitionConsumer
+	messages          chan *events.Message
+	errors            chan error
+	done              chan struct{}
+	closeOnce         sync.Once
+}
+
+// forward entrega los mensajes y errores del consumidor de partición hasta Close
+func (r *kafkaReader) forward() {
+	defer close(r.messages)
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case err, ok := <-r.partitionConsumer.Errors():
+			if !ok {
+				return
+			}
+			select {
+			case r.errors <- err:
+			case <-r.done:
+				return
+			}
+		case msg, ok := <-r.partitionConsumer.Messages():
+			if !ok {
+				return
+			}
+			select {
+			case r.messages <- toEventMessage(msg):
+			case <-r.done:
+				return
+			}
+		}
+	}
+}
+
+func (r *kafkaReader) Messages() <-chan *events.Message {
+	return r.messages
+}
+
+func (r *kafkaReader) Errors() <-chan error {
+	return r.errors
+}
+
+// Close detiene la lectura y cierra el consumidor y el cliente
+func (r *kafkaReader) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.done)
+		err = r.partitionConsumer.Close()
+		r.consumer.Close()
+		r.client.Close()
+	})
+	return err
+}
+
+// toEventMessage convierte un mensaje de sarama en un events.Message
+func toEventMessage(msg *sarama.ConsumerMessage) *events.Message {
+	return &events.Message{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headersToMap(msg.Headers),
+		Timestamp: msg.Timestamp,
+	}
+}
+
+// toConsumerMessage convierte un events.Message en el mensaje de sarama que procesan las rutas
+func toConsumerMessage(msg *events.Message) *sarama.ConsumerMessage {
+	headers := make([]*sarama.RecordHeader, 0, len(msg.Headers))
+	for key, value := range msg.Headers {
+		headers = append(headers, &sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+
+	return &sarama.ConsumerMessage{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headers,
+		Timestamp: msg.Timestamp,
+	}
+}
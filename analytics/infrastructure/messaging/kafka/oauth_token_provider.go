@@ -0,0 +1,111 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// tokenRefreshMargin es el tiempo antes del vencimiento en que se pide un token nuevo
+const tokenRefreshMargin = 30 * time.Second
+
+// StaticTokenProvider entrega siempre el mismo token OAUTHBEARER. Implementa sarama.AccessTokenProvider.
+type StaticTokenProvider struct {
+	token string
+}
+
+// NewStaticTokenProvider crea un proveedor con un token fijo
+func NewStaticTokenProvider(token string) *StaticTokenProvider {
+	return &StaticTokenProvider{token: token}
+}
+
+// Token retorna el token fijo
+func (p *StaticTokenProvider) Token() (*sarama.AccessToken, error) {
+	return &sarama.AccessToken{Token: p.token}, nil
+}
+
+// ClientCredentialsTokenProvider obtiene tokens OAUTHBEARER con el flujo client credentials de OAuth 2.0
+// y los reutiliza hasta poco antes de su vencimiento. Implementa sarama.AccessTokenProvider.
+type ClientCredentialsTokenProvider struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	httpClient   *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewClientCredentialsTokenProvider crea un proveedor que pide los tokens al endpoint del servidor de autorización
+func NewClientCredentialsTokenProvider(tokenURL, clientID, clientSecret string, scopes []string) *ClientCredentialsTokenProvider {
+	return &ClientCredentialsTokenProvider{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// tokenResponse es la respuesta del endpoint de tokens
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Token retorna el token vigente o pide uno nuevo
+func (p *ClientCredentialsTokenProvider) Token() (*sarama.AccessToken, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Before(p.expiresAt) {
+		return &sarama.AccessToken{Token: p.token}, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(p.scopes) > 0 {
+		form.Set("scope", strings.Join(p.scopes, " "))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.httpClient.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting OAuth token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OAuth token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("error decoding OAuth token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("OAuth token response has no access_token")
+	}
+
+	p.token = token.AccessToken
+	p.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenRefreshMargin)
+	return &sarama.AccessToken{Token: p.token}, nil
+}
@@ -0,0 +1,153 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+
+	"github.com/IBM/sarama"
+)
+
+// DomainEventSchemaVersion es la versión de esquema de los eventos de dominio publicados
+const DomainEventSchemaVersion = 1
+
+// outboxCleanupInterval es cada cuánto se eliminan los eventos publicados que superaron la retención
+const outboxCleanupInterval = time.Hour
+
+// OutboxRelayConfig configura la publicación del outbox
+type OutboxRelayConfig struct {
+	Topic        string        // Tópico de los eventos de dominio
+	Source       string        // Atributo source de los CloudEvents
+	BatchSize    int           // Eventos por pasada
+	PollInterval time.Duration // Espera entre pasadas cuando no hay más eventos pendientes
+	Retention    time.Duration // Tiempo que se conservan los eventos publicados (0 = siempre)
+	MaxAttempts  int           // Intentos antes de apartar un evento como fallido (0 = sin límite)
+}
+
+// OutboxRelay publica en Kafka, como CloudEvents estructurados y en orden, los eventos de dominio del outbox.
+// La clave de cada mensaje es la del evento (el ID del estudiante), por lo que el orden se mantiene por estudiante.
+type OutboxRelay struct {
+	producer   sarama.SyncProducer
+	repository repositories.OutboxRepository
+	config     OutboxRelayConfig
+}
+
+// NewOutboxRelay crea un relay con la misma configuración de conexión y seguridad del consumidor
+func NewOutboxRelay(cfg *ConsumerConfig, relayConfig OutboxRelayConfig, repository repositories.OutboxRepository) (*OutboxRelay, error) {
+	config, err := NewSaramaConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kafka configuration: %w", err)
+	}
+	config.Producer.Return.Successes = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 3
+
+	producer, err := sarama.NewSyncProducer(cfg.Brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating outbox producer: %w", err)
+	}
+
+	if relayConfig.BatchSize <= 0 {
+		relayConfig.BatchSize = 100
+	}
+	if relayConfig.PollInterval <= 0 {
+		relayConfig.PollInterval = time.Second
+	}
+
+	log.Printf("Outbox producer created successfully for topic: %s", relayConfig.Topic)
+
+	return &OutboxRelay{
+		producer:   producer,
+		repository: repository,
+		config:     relayConfig,
+	}, nil
+}
+
+// Run publica los eventos pendientes hasta que se cancele ctx
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		r.publishPending(ctx)
+
+		if r.config.Retention > 0 && time.Since(lastCleanup) >= outboxCleanupInterval {
+			r.cleanup(ctx)
+			lastCleanup = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishPending publica lotes mientras haya eventos pendientes. Ante un error espera a la siguiente pasada.
+func (r *OutboxRelay) publishPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		result, err := r.repository.PublishPending(ctx, r.config.BatchSize, r.config.MaxAttempts, r.publishOrPark)
+		if result.Published > 0 {
+			log.Printf("Published %d domain event(s) to topic %s", result.Published, r.config.Topic)
+		}
+		if err != nil {
+			log.Printf("Error publishing outbox events: %v", err)
+			return
+		}
+		if result.Published+result.Failed < r.config.BatchSize {
+			return
+		}
+	}
+}
+
+// publishOrPark publica un evento y registra cuando agota los intentos y queda apartado como fallido
+func (r *OutboxRelay) publishOrPark(event *repositories.OutboxEvent) error {
+	err := r.publish(event)
+	if err != nil && r.config.MaxAttempts > 0 && event.Attempts+1 >= r.config.MaxAttempts {
+		log.Printf("Parking outbox event %s (%s) after %d failed attempt(s): %v", event.EventID, event.Type, event.Attempts+1, err)
+	}
+	return err
+}
+
+// publish envía un evento del outbox como CloudEvent
+func (r *OutboxRelay) publish(event *repositories.OutboxEvent) error {
+	cloudEvent, err := events.NewCloudEvent(event.Type, r.config.Source, event.EventID, event.OccurredAt, DomainEventSchemaVersion, json.RawMessage(event.Payload))
+	if err != nil {
+		return fmt.Errorf("error creating cloud event %s: %w", event.EventID, err)
+	}
+
+	message, err := NewCloudEventMessage(r.config.Topic, event.Key, cloudEvent, CloudEventStructured)
+	if err != nil {
+		return fmt.Errorf("error creating message for event %s: %w", event.EventID, err)
+	}
+
+	if _, _, err := r.producer.SendMessage(message); err != nil {
+		return fmt.Errorf("error sending event %s: %w", event.EventID, err)
+	}
+	return nil
+}
+
+// cleanup elimina los eventos publicados que superaron la retención
+func (r *OutboxRelay) cleanup(ctx context.Context) {
+	deleted, err := r.repository.DeletePublishedBefore(ctx, time.Now().Add(-r.config.Retention))
+	if err != nil {
+		log.Printf("Error deleting published outbox events: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d published outbox event(s)", deleted)
+	}
+}
+
+// Close cierra el productor del outbox
+func (r *OutboxRelay) Close() error {
+	log.Println("Closing outbox producer...")
+	return r.producer.Close()
+}
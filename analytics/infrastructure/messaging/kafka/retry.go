@@ -0,0 +1,200 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+)
+
+// ErrorClass clasifica los errores de procesamiento según si vale la pena reintentar
+type ErrorClass int
+
+const (
+	// ErrorTransient agrupa errores de red o de repositorio que pueden resolverse solos
+	ErrorTransient ErrorClass = iota
+	// ErrorPermanent agrupa errores de deserialización o validación de dominio
+	ErrorPermanent
+)
+
+// String implementa Stringer
+func (c ErrorClass) String() string {
+	if c == ErrorPermanent {
+		return "permanent"
+	}
+	return "transient"
+}
+
+// permanentError marca un error que no se resolverá reintentando
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marca un error como permanente para que no sea reintentado
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// decodeError clasifica un error de decodificación: es permanente salvo que se declare temporal
+func decodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if events.IsTemporary(err) {
+		return err
+	}
+	return Permanent(err)
+}
+
+// ClassifyError determina si un error de procesamiento es permanente o transitorio
+func ClassifyError(err error) ErrorClass {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return ErrorPermanent
+	}
+
+	// Los errores de datos y de integridad de la base de datos no cambian al reintentar
+	if repositories.IsDataError(err) {
+		return ErrorPermanent
+	}
+
+	return ErrorTransient
+}
+
+// RetryPolicy define cómo se reintentan los errores transitorios. MaxAttempts y MaxElapsed acotan una ronda de
+// reintentos; al agotarse, el consumidor reinicia la sesión sin marcar el offset (salvo con DeadLetterTransientErrors)
+// y el mensaje se vuelve a entregar, por lo que un error transitorio se reintenta hasta que la dependencia se recupera.
+type RetryPolicy struct {
+	MaxAttempts    int           // Intentos de una ronda, incluyendo el primero
+	InitialBackoff time.Duration // Espera antes del primer reintento
+	MaxBackoff     time.Duration // Espera máxima entre reintentos
+	Multiplier     float64       // Factor de crecimiento exponencial
+	Jitter         float64       // Variación aleatoria relativa (0.2 = ±20%)
+	MaxElapsed     time.Duration // Presupuesto de tiempo de una ronda de reintentos
+}
+
+// DefaultRetryPolicy retorna la política de reintentos por defecto
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxElapsed:     2 * time.Minute,
+	}
+}
+
+// retryPolicyFromConfig construye la política de reintentos a partir de la configuración del consumidor
+func retryPolicyFromConfig(cfg *ConsumerConfig) RetryPolicy {
+	policy := DefaultRetryPolicy()
+	if cfg.RetryMaxAttempts > 0 {
+		policy.MaxAttempts = cfg.RetryMaxAttempts
+	}
+	if cfg.RetryInitialBackoffMs > 0 {
+		policy.InitialBackoff = time.Duration(cfg.RetryInitialBackoffMs) * time.Millisecond
+	}
+	if cfg.RetryMaxBackoffMs > 0 {
+		policy.MaxBackoff = time.Duration(cfg.RetryMaxBackoffMs) * time.Millisecond
+	}
+	if cfg.RetryMaxElapsedMs > 0 {
+		policy.MaxElapsed = time.Duration(cfg.RetryMaxElapsedMs) * time.Millisecond
+	}
+	return policy
+}
+
+// Backoff calcula la espera antes del reintento número attempt (empezando en 1)
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		backoff = backoff * (1 + p.Jitter*(2*rand.Float64()-1))
+	}
+
+	return time.Duration(backoff)
+}
+
+// partitionPauser pausa una partición mientras se reintenta un mensaje
+type partitionPauser interface {
+	pauseForRetry(topic string, partition int32)
+	resumeAfterRetry(topic string, partition int32)
+}
+
+// retrier ejecuta el procesamiento de mensajes aplicando la política de reintentos
+// y pausando la partición mientras espera
+type retrier struct {
+	policy RetryPolicy
+	pauser partitionPauser
+}
+
+// run ejecuta process reintentando los errores transitorios, pausando la partición durante las esperas.
+// Retorna el número de intentos realizados y el último error.
+func (r *retrier) run(ctx context.Context, topic string, partition int32, process func(context.Context) error) (int, error) {
+	start := time.Now()
+	paused := false
+
+	defer func() {
+		if paused {
+			r.pauser.resumeAfterRetry(topic, partition)
+			log.Printf("Resumed partition %d of topic %s", partition, topic)
+		}
+	}()
+
+	for attempt := 1; ; attempt++ {
+		err := process(ctx)
+		if err == nil {
+			return attempt, nil
+		}
+
+		if ClassifyError(err) == ErrorPermanent {
+			return attempt, err
+		}
+
+		if attempt >= r.policy.MaxAttempts {
+			log.Printf("Retry budget exhausted for topic %s, partition %d after %d attempts", topic, partition, attempt)
+			return attempt, err
+		}
+
+		backoff := r.policy.Backoff(attempt)
+		if r.policy.MaxElapsed > 0 && time.Since(start)+backoff > r.policy.MaxElapsed {
+			log.Printf("Retry time budget of %v exhausted for topic %s, partition %d after %d attempts",
+				r.policy.MaxElapsed, topic, partition, attempt)
+			return attempt, err
+		}
+
+		if !paused {
+			r.pauser.pauseForRetry(topic, partition)
+			paused = true
+			log.Printf("Paused partition %d of topic %s while retrying", partition, topic)
+		}
+
+		log.Printf("Transient error processing topic %s, partition %d (attempt %d/%d), retrying in %v: %v",
+			topic, partition, attempt, r.policy.MaxAttempts, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// sqlError simula un error de PostgreSQL con su SQLSTATE
+type sqlError string
+
+func (e sqlError) Error() string    { return "sql error " + string(e) }
+func (e sqlError) SQLState() string { return string(e) }
+
+// temporaryError simula un error que se declara temporal
+type temporaryError struct{ temporary bool }
+
+func (e temporaryError) Error() string   { return "schema registry unavailable" }
+func (e temporaryError) Temporary() bool { return e.temporary }
+
+// recordingPauser registra las pausas y reanudaciones de particiones
+type recordingPauser struct {
+	paused  int
+	resumed int
+}
+
+func (p *recordingPauser) pauseForRetry(topic string, partition int32)    { p.paused++ }
+func (p *recordingPauser) resumeAfterRetry(topic string, partition int32) { p.resumed++ }
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"plain error", errors.New("connection refused"), ErrorTransient},
+		{"context deadline", context.DeadlineExceeded, ErrorTransient},
+		{"permanent", Permanent(errors.New("invalid payload")), ErrorPermanent},
+		{"wrapped permanent", fmt.Errorf("handling batch: %w", Permanent(errors.New("invalid payload"))), ErrorPermanent},
+		{"data exception", fmt.Errorf("insert: %w", sqlError("22P02")), ErrorPermanent},
+		{"integrity violation", sqlError("23505"), ErrorPermanent},
+		{"connection exception", sqlError("08006"), ErrorTransient},
+		{"serialization failure", sqlError("40001"), ErrorTransient},
+		{"temporary decode error", decodeError(temporaryError{temporary: true}), ErrorTransient},
+		{"decode error", decodeError(temporaryError{temporary: false}), ErrorPermanent},
+		{"plain decode error", decodeError(errors.New("unexpected end of JSON input")), ErrorPermanent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	if Permanent(nil) != nil || decodeError(nil) != nil {
+		t.Fatalf("expected nil errors to stay nil")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+
+	policy.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		if got := policy.Backoff(2); got < 160*time.Millisecond || got > 240*time.Millisecond {
+			t.Fatalf("Backoff(2) with jitter = %v, want within ±20%% of 200ms", got)
+		}
+	}
+}
+
+func TestRetryPolicyFromConfig(t *testing.T) {
+	if policy := retryPolicyFromConfig(&ConsumerConfig{}); policy != DefaultRetryPolicy() {
+		t.Fatalf("expected default policy, got %+v", policy)
+	}
+
+	policy := retryPolicyFromConfig(&ConsumerConfig{
+		RetryMaxAttempts:      3,
+		RetryInitialBackoffMs: 10,
+		RetryMaxBackoffMs:     100,
+		RetryMaxElapsedMs:     1000,
+	})
+	if policy.MaxAttempts != 3 || policy.InitialBackoff != 10*time.Millisecond ||
+		policy.MaxBackoff != 100*time.Millisecond || policy.MaxElapsed != time.Second {
+		t.Fatalf("unexpected policy %+v", policy)
+	}
+}
+
+func TestRetrierRun(t *testing.T) {
+	transient := errors.New("connection refused")
+	permanent := Permanent(errors.New("invalid payload"))
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 2}
+
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		failures []error
+		attempts int
+		err      error
+		paused   bool
+	}{
+		{"success on first attempt", policy, nil, 1, nil, false},
+		{"success after transient errors", policy, []error{transient, transient}, 3, nil, true},
+		{"permanent error is not retried", policy, []error{permanent}, 1, permanent, false},
+		{"permanent error after transient error", policy, []error{transient, permanent}, 2, permanent, true},
+		{"max attempts exhausted", policy, []error{transient, transient, transient, transient}, 3, transient, true},
+		{
+			name:     "elapsed budget exhausted before backoff",
+			policy:   RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: time.Second, Multiplier: 2, MaxElapsed: 500 * time.Millisecond},
+			failures: []error{transient, transient},
+			attempts: 1,
+			err:      transient,
+			paused:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pauser := &recordingPauser{}
+			r := &retrier{policy: tt.policy, pauser: pauser}
+
+			calls := 0
+			attempts, err := r.run(context.Background(), "executions", 0, func(ctx context.Context) error {
+				calls++
+				if calls <= len(tt.failures) {
+					return tt.failures[calls-1]
+				}
+				return nil
+			})
+
+			if attempts != tt.attempts || calls != tt.attempts {
+				t.Fatalf("expected %d attempts, got %d (%d calls)", tt.attempts, attempts, calls)
+			}
+			if err != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if (pauser.paused == 1) != tt.paused || pauser.resumed != pauser.paused {
+				t.Fatalf("expected paused=%t and resumed once per pause, got %d pauses and %d resumes",
+					tt.paused, pauser.paused, pauser.resumed)
+			}
+		})
+	}
+}
+
+func TestRetrierRunStopsOnContextCancel(t *testing.T) {
+	pauser := &recordingPauser{}
+	r := &retrier{
+		policy: RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Minute, MaxBackoff: time.Minute, Multiplier: 2},
+		pauser: pauser,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts, err := r.run(ctx, "executions", 0, func(ctx context.Context) error {
+		cancel()
+		return errors.New("connection refused")
+	})
+
+	if attempts != 1 || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected 1 attempt and context.Canceled, got %d attempts and %v", attempts, err)
+	}
+	if pauser.paused != 1 || pauser.resumed != 1 {
+		t.Fatalf("expected partition to be resumed after cancel, got %d pauses and %d resumes", pauser.paused, pauser.resumed)
+	}
+}
@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+
+	"github.com/IBM/sarama"
+)
+
+// Decoder convierte un mensaje de Kafka en un objeto listo para su manejo.
+// Sus errores se consideran permanentes y envían el mensaje al dead-letter, salvo los que
+// implementan Temporary() (por ejemplo, el Schema Registry no disponible), que se reintentan.
+type Decoder func(ctx context.Context, message *sarama.ConsumerMessage) (interface{}, error)
+
+// Handler procesa un lote de objetos decodificados de un mismo tópico
+type Handler func(ctx context.Context, values []interface{}) error
+
+// OffsetHandler procesa un lote y guarda el offset del consumidor en la misma transacción.
+// Se usa en lugar de Handler cuando los offsets se guardan en la base de datos.
+type OffsetHandler func(ctx context.Context, values []interface{}, offset repositories.ConsumerOffset) error
+
+// OrderingKey retorna la clave que ordena un objeto decodificado (por ejemplo, el ID del estudiante).
+// Los objetos con la misma clave se procesan en orden aunque la partición use varios workers.
+type OrderingKey func(value interface{}) string
+
+// Route asocia un tópico con su decoder y su handler.
+// Si EventTypes no está vacío, la ruta también recibe los eventos de esos tipos (CloudEvents o envelope)
+// publicados en otros tópicos, y rechaza los tipos desconocidos publicados en el suyo.
+type Route struct {
+	Topic            string        // Tópico a consumir
+	Name             string        // Nombre descriptivo para los logs
+	EventTypes       []string      // Tipos de evento que maneja la ruta
+	Decode           Decoder       // Deserializa y convierte a dominio
+	Handle           Handler       // Procesa los objetos decodificados
+	HandleWithOffset OffsetHandler // Opcional: procesa el lote y guarda el offset atómicamente
+	OrderingKey      OrderingKey   // Opcional: clave de orden (por defecto, la clave del mensaje)
+	BatchSize        int           // Tamaño máximo del micro-lote (1 = mensaje a mensaje)
+	BatchTimeout     time.Duration // Tiempo máximo de espera antes de procesar un lote incompleto
+}
+
+// decodeEnvelope convierte el valor del mensaje a JSON con el decoder del tópico y lo interpreta
+// como CloudEvent (binario o estructurado), envelope versionado o evento legado
+func decodeEnvelope(ctx context.Context, payloadDecoder events.PayloadDecoder, message *sarama.ConsumerMessage, defaultType string) (*events.Envelope, error) {
+	payload, err := payloadDecoder.Decode(ctx, message.Value)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParseMessage(payload, headersToMap(message.Headers), defaultType)
+}
@@ -0,0 +1,173 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// NewSaramaConfig construye la configuración base de sarama compartida por consumidores, productores y clientes
+func NewSaramaConfig(cfg *ConsumerConfig) (*sarama.Config, error) {
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_6_0_0 // Azure Event Hub es compatible con Kafka 1.0+
+
+	// Consumer group settings
+	config.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	if cfg.InitialOffset == "oldest" {
+		// Un consumer group nuevo procesa el historial disponible del tópico
+		config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	}
+	config.Consumer.Return.Errors = true
+
+	// Auto commit settings
+	if cfg.EnableAutoCommit {
+		config.Consumer.Offsets.AutoCommit.Enable = true
+		config.Consumer.Offsets.AutoCommit.Interval = 1 * time.Second
+	}
+
+	// Timeouts - Azure Event Hub requiere timeouts más altos
+	config.Net.DialTimeout = 30 * time.Second
+	config.Net.ReadTimeout = time.Duration(cfg.RequestTimeoutMs) * time.Millisecond
+	config.Net.WriteTimeout = 30 * time.Second
+
+	config.Consumer.MaxProcessingTime = time.Duration(cfg.SessionTimeoutMs) * time.Millisecond
+	config.Consumer.Group.Session.Timeout = time.Duration(cfg.SessionTimeoutMs) * time.Millisecond
+	config.Consumer.Group.Heartbeat.Interval = 3 * time.Second
+
+	// Configuración de metadata para Azure Event Hub
+	config.Metadata.Retry.Max = 5
+	config.Metadata.Retry.Backoff = 2 * time.Second
+	config.Metadata.Timeout = 60 * time.Second
+	config.Metadata.Full = false
+
+	if err := configureSecurity(config, cfg); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// configureSecurity configura TLS y SASL según el protocolo de seguridad:
+// PLAINTEXT, SSL (TLS sin SASL, opcionalmente mTLS), SASL_PLAINTEXT o SASL_SSL
+func configureSecurity(config *sarama.Config, cfg *ConsumerConfig) error {
+	switch cfg.SecurityProtocol {
+	case "", "PLAINTEXT":
+		return nil
+	case "SSL":
+		log.Println("Configuring SSL...")
+		return configureTLS(config, cfg)
+	case "SASL_SSL":
+		log.Printf("Configuring SASL_SSL with mechanism %s...", saslMechanism(cfg))
+		if err := configureTLS(config, cfg); err != nil {
+			return err
+		}
+		return configureSASL(config, cfg)
+	case "SASL_PLAINTEXT":
+		log.Printf("Configuring SASL_PLAINTEXT with mechanism %s...", saslMechanism(cfg))
+		return configureSASL(config, cfg)
+	default:
+		return fmt.Errorf("unsupported security protocol %q", cfg.SecurityProtocol)
+	}
+}
+
+// configureTLS habilita TLS con la CA del sistema o una propia, y el certificado de cliente si se indica (mTLS)
+func configureTLS(config *sarama.Config, cfg *ConsumerConfig) error {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.TLSServerName,
+	}
+
+	if cfg.TLSCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return fmt.Errorf("error reading TLS CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("TLS CA file %s contains no PEM certificates", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return fmt.Errorf("both TLS client certificate and key are required for mutual TLS")
+		}
+		certificate, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return fmt.Errorf("error loading TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+		log.Println("TLS client certificate configured (mutual TLS)")
+	}
+
+	if cfg.TLSInsecureSkipVerify {
+		log.Println("Warning: TLS certificate verification is disabled")
+	}
+
+	config.Net.TLS.Enable = true
+	config.Net.TLS.Config = tlsConfig
+	return nil
+}
+
+// configureSASL configura el mecanismo SASL: PLAIN (Azure Event Hub), SCRAM-SHA-256, SCRAM-SHA-512 u OAUTHBEARER
+func configureSASL(config *sarama.Config, cfg *ConsumerConfig) error {
+	config.Net.SASL.Enable = true
+	config.Net.SASL.Handshake = true
+	config.Net.SASL.Version = sarama.SASLHandshakeV1
+
+	mechanism := saslMechanism(cfg)
+	switch mechanism {
+	case sarama.SASLTypePlaintext:
+		// Azure Event Hub: el usuario es "$ConnectionString" y la contraseña el connection string completo
+		config.Net.SASL.User = cfg.SaslUsername
+		config.Net.SASL.Password = cfg.SaslPassword
+	case sarama.SASLTypeSCRAMSHA256, sarama.SASLTypeSCRAMSHA512:
+		config.Net.SASL.User = cfg.SaslUsername
+		config.Net.SASL.Password = cfg.SaslPassword
+		config.Net.SASL.SCRAMClientGeneratorFunc = newSCRAMClientGenerator(mechanism)
+	case sarama.SASLTypeOAuth:
+		provider, err := tokenProvider(cfg)
+		if err != nil {
+			return err
+		}
+		config.Net.SASL.TokenProvider = provider
+	default:
+		return fmt.Errorf("unsupported SASL mechanism %q", cfg.SaslMechanism)
+	}
+
+	config.Net.SASL.Mechanism = mechanism
+	log.Printf("SASL configured with mechanism: %s, username: %s", mechanism, cfg.SaslUsername)
+	return nil
+}
+
+// saslMechanism retorna el mecanismo SASL configurado (PLAIN por defecto)
+func saslMechanism(cfg *ConsumerConfig) sarama.SASLMechanism {
+	if cfg.SaslMechanism == "" {
+		return sarama.SASLTypePlaintext
+	}
+	return sarama.SASLMechanism(strings.ToUpper(cfg.SaslMechanism))
+}
+
+// tokenProvider retorna el proveedor de tokens OAUTHBEARER: el de la configuración, uno con el flujo
+// client credentials si hay un endpoint de tokens, o uno con un token fijo
+func tokenProvider(cfg *ConsumerConfig) (sarama.AccessTokenProvider, error) {
+	switch {
+	case cfg.TokenProvider != nil:
+		return cfg.TokenProvider, nil
+	case cfg.OAuthTokenURL != "":
+		return NewClientCredentialsTokenProvider(cfg.OAuthTokenURL, cfg.OAuthClientID, cfg.OAuthClientSecret, cfg.OAuthScopes), nil
+	case cfg.OAuthToken != "":
+		return NewStaticTokenProvider(cfg.OAuthToken), nil
+	default:
+		return nil, fmt.Errorf("OAUTHBEARER requires a token provider, a token endpoint or a static token")
+	}
+}
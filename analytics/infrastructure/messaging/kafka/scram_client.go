@@ -0,0 +1,50 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/xdg-go/scram"
+)
+
+// scramClient adapta github.com/xdg-go/scram a sarama.SCRAMClient para SCRAM-SHA-256 y SCRAM-SHA-512
+type scramClient struct {
+	hashGenerator scram.HashGeneratorFcn
+
+	conversation *scram.ClientConversation
+}
+
+// newSCRAMClientGenerator retorna el generador de clientes SCRAM que usa sarama en cada conexión
+func newSCRAMClientGenerator(mechanism sarama.SASLMechanism) func() sarama.SCRAMClient {
+	hashGenerator := scram.SHA256
+	if mechanism == sarama.SASLTypeSCRAMSHA512 {
+		hashGenerator = scram.SHA512
+	}
+	return func() sarama.SCRAMClient {
+		return &scramClient{hashGenerator: hashGenerator}
+	}
+}
+
+// Begin prepara el intercambio con las credenciales del usuario
+func (c *scramClient) Begin(userName, password, authzID string) error {
+	client, err := c.hashGenerator.NewClient(userName, password, authzID)
+	if err != nil {
+		return fmt.Errorf("error creating SCRAM client: %w", err)
+	}
+	c.conversation = client.NewConversation()
+	return nil
+}
+
+// Step avanza el intercambio: client-first, client-final y verificación del server-final
+func (c *scramClient) Step(challenge string) (string, error) {
+	response, err := c.conversation.Step(challenge)
+	if err != nil {
+		return "", fmt.Errorf("SCRAM authentication failed: %w", err)
+	}
+	return response, nil
+}
+
+// Done indica si el intercambio terminó
+func (c *scramClient) Done() bool {
+	return c.conversation.Done()
+}
@@ -0,0 +1,347 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+
+	"github.com/IBM/sarama"
+)
+
+// sourceMemberID es el ID de miembro del único consumidor de una fuente que no es Kafka
+const sourceMemberID = "local"
+
+// NewSourceConsumer crea un consumidor que procesa los mensajes de una fuente que no es Kafka (archivos JSONL
+// o un canal en memoria) con las mismas rutas, micro-lotes, reintentos y workers que el consumer group.
+// El consumidor recibe todas las particiones de sus tópicos desde el offset más antiguo; los offsets
+// procesados se guardan en memoria o, con UseOffsetStore, en la base de datos.
+func NewSourceConsumer(source events.MessageSource, cfg *ConsumerConfig, deadLetter *DeadLetterPublisher) *Consumer {
+	return newConsumer(newSourceGroup(source), cfg, deadLetter)
+}
+
+// sourceGroup adapta una events.MessageSource a sarama.ConsumerGroup con un único miembro
+type sourceGroup struct {
+	source events.MessageSource
+
+	mu         sync.Mutex
+	offsets    map[partitionKey]int64 // Siguiente offset a leer de cada partición
+	paused     map[partitionKey]bool
+	pausedAll  bool
+	resumed    chan struct{} // Se cierra al retomar particiones
+	generation int32
+
+	errors    chan error
+	closed    bool
+	closeOnce sync.Once
+}
+
+// newSourceGroup crea el adaptador de la fuente
+func newSourceGroup(source events.MessageSource) *sourceGroup {
+	return &sourceGroup{
+		source:  source,
+		offsets: make(map[partitionKey]int64),
+		paused:  make(map[partitionKey]bool),
+		resumed: make(chan struct{}),
+		errors:  make(chan error, 16),
+	}
+}
+
+// Consume asigna todas las particiones de los tópicos y las procesa con el handler. Si la fuente es acotada,
+// al terminar de leerla espera a que se cancele ctx para no volver a iniciar la sesión.
+func (g *sourceGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	claims := make(map[string][]int32, len(topics))
+	highWaterMarks := make(map[partitionKey]int64)
+	for _, topic := range topics {
+		ranges, err := g.source.Partitions(ctx, topic, nil, nil)
+		if err != nil {
+			return fmt.Errorf("error getting partitions of topic %s: %w", topic, err)
+		}
+		for _, partitionRange := range ranges {
+			key := partitionKey{topic: topic, partition: partitionRange.Partition}
+			claims[topic] = append(claims[topic], partitionRange.Partition)
+			highWaterMarks[key] = partitionRange.End
+			g.initOffset(key, partitionRange.Start)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	g.mu.Lock()
+	g.generation++
+	session := &sourceSession{group: g, ctx: ctx, claims: claims, generation: g.generation}
+	g.mu.Unlock()
+
+	if err := handler.Setup(session); err != nil {
+		return err
+	}
+	defer func() {
+		if err := handler.Cleanup(session); err != nil {
+			g.reportError(err)
+		}
+	}()
+
+	// Las particiones se leen desde los offsets fijados en Setup (offsets guardados o reseteos)
+	var wg sync.WaitGroup
+	for topic, partitions := range claims {
+		for _, partition := range partitions {
+			key := partitionKey{topic: topic, partition: partition}
+			offset := g.offset(key)
+			reader, err := g.source.Read(ctx, topic, partition, offset)
+			if err != nil {
+				cancel()
+				wg.Wait()
+				return fmt.Errorf("error reading topic %s partition %d: %w", topic, partition, err)
+			}
+
+			claim := &sourceClaim{
+				topic:         topic,
+				partition:     partition,
+				initialOffset: offset,
+				highWaterMark: highWaterMarks[key],
+				messages:      make(chan *sarama.ConsumerMessage),
+			}
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				g.forward(ctx, reader, claim)
+			}()
+			go func() {
+				defer wg.Done()
+				if err := handler.ConsumeClaim(session, claim); err != nil {
+					g.reportError(err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	// Fuente acotada leída por completo: la sesión sigue abierta hasta que se cancele
+	if ctx.Err() == nil {
+		log.Printf("Finished reading message source for topics %v", topics)
+		<-ctx.Done()
+	}
+	return nil
+}
+
+// forward entrega al claim los mensajes de la partición mientras no esté pausada
+func (g *sourceGroup) forward(ctx context.Context, reader events.MessageReader, claim *sourceClaim) {
+	defer close(claim.messages)
+	defer reader.Close()
+
+	key := partitionKey{topic: claim.topic, partition: claim.partition}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-reader.Errors():
+			g.reportError(fmt.Errorf("error reading topic %s partition %d: %w", claim.topic, claim.partition, err))
+		case message, ok := <-reader.Messages():
+			if !ok {
+				return
+			}
+			if !g.waitResumed(ctx, key) {
+				return
+			}
+			select {
+			case claim.messages <- toConsumerMessage(message):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// waitResumed espera a que la partición no esté pausada. Retorna false si ctx se canceló antes.
+func (g *sourceGroup) waitResumed(ctx context.Context, key partitionKey) bool {
+	for {
+		g.mu.Lock()
+		paused := g.pausedAll || g.paused[key]
+		resumed := g.resumed
+		g.mu.Unlock()
+
+		if !paused {
+			return true
+		}
+		select {
+		case <-resumed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// initOffset fija el offset inicial de una partición que aún no se leyó
+func (g *sourceGroup) initOffset(key partitionKey, offset int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.offsets[key]; !ok {
+		g.offsets[key] = offset
+	}
+}
+
+// offset retorna el siguiente offset a leer de una partición
+func (g *sourceGroup) offset(key partitionKey) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.offsets[key]
+}
+
+// reportError publica un error en Errors sin bloquear
+func (g *sourceGroup) reportError(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return
+	}
+	select {
+	case g.errors <- err:
+	default:
+		log.Printf("Message source error: %v", err)
+	}
+}
+
+// notifyResumed despierta a las particiones que esperan. Debe llamarse con g.mu tomado.
+func (g *sourceGroup) notifyResumed() {
+	close(g.resumed)
+	g.resumed = make(chan struct{})
+}
+
+func (g *sourceGroup) Errors() <-chan error {
+	return g.errors
+}
+
+func (g *sourceGroup) Close() error {
+	g.closeOnce.Do(func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.closed = true
+		close(g.errors)
+	})
+	return nil
+}
+
+func (g *sourceGroup) Pause(partitions map[string][]int32) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for topic, ids := range partitions {
+		for _, partition := range ids {
+			g.paused[partitionKey{topic: topic, partition: partition}] = true
+		}
+	}
+}
+
+func (g *sourceGroup) Resume(partitions map[string][]int32) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for topic, ids := range partitions {
+		for _, partition := range ids {
+			delete(g.paused, partitionKey{topic: topic, partition: partition})
+		}
+	}
+	g.notifyResumed()
+}
+
+func (g *sourceGroup) PauseAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pausedAll = true
+}
+
+func (g *sourceGroup) ResumeAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.pausedAll = false
+	g.paused = make(map[partitionKey]bool)
+	g.notifyResumed()
+}
+
+// sourceSession es la sesión del único miembro de un sourceGroup. Implementa sarama.ConsumerGroupSession.
+type sourceSession struct {
+	group      *sourceGroup
+	ctx        context.Context
+	claims     map[string][]int32
+	generation int32
+}
+
+func (s *sourceSession) Claims() map[string][]int32 {
+	return s.claims
+}
+
+func (s *sourceSession) MemberID() string {
+	return sourceMemberID
+}
+
+func (s *sourceSession) GenerationID() int32 {
+	return s.generation
+}
+
+// MarkOffset avanza el offset de la partición; como en sarama, nunca lo retrocede
+func (s *sourceSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+	s.group.mu.Lock()
+	defer s.group.mu.Unlock()
+
+	key := partitionKey{topic: topic, partition: partition}
+	if offset > s.group.offsets[key] {
+		s.group.offsets[key] = offset
+	}
+}
+
+// Commit no hace nada: los offsets marcados ya están en memoria
+func (s *sourceSession) Commit() {}
+
+// ResetOffset retrocede el offset de la partición; como en sarama, nunca lo avanza
+func (s *sourceSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+	s.group.mu.Lock()
+	defer s.group.mu.Unlock()
+
+	key := partitionKey{topic: topic, partition: partition}
+	if offset < s.group.offsets[key] {
+		s.group.offsets[key] = offset
+	}
+}
+
+func (s *sourceSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+func (s *sourceSession) Context() context.Context {
+	return s.ctx
+}
+
+// sourceClaim es una partición asignada al miembro de un sourceGroup. Implementa sarama.ConsumerGroupClaim.
+type sourceClaim struct {
+	topic         string
+	partition     int32
+	initialOffset int64
+	highWaterMark int64
+	messages      chan *sarama.ConsumerMessage
+}
+
+func (c *sourceClaim) Topic() string {
+	return c.topic
+}
+
+func (c *sourceClaim) Partition() int32 {
+	return c.partition
+}
+
+func (c *sourceClaim) InitialOffset() int64 {
+	return c.initialOffset
+}
+
+func (c *sourceClaim) HighWaterMarkOffset() int64 {
+	return c.highWaterMark
+}
+
+func (c *sourceClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
@@ -0,0 +1,147 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/source"
+
+	"github.com/IBM/sarama"
+)
+
+// testEvent es el evento que publica y procesa la prueba del consumidor
+type testEvent struct {
+	Student  string `json:"student"`
+	Sequence int    `json:"sequence"`
+}
+
+func publishTestEvent(t *testing.T, memory *source.MemorySource, student string, sequence int) {
+	t.Helper()
+
+	value, _ := json.Marshal(testEvent{Student: student, Sequence: sequence})
+	if _, err := memory.Publish("executions", []byte(student), value, nil, time.Time{}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+func TestSourceConsumerProcessesMemorySourceInKeyOrder(t *testing.T) {
+	memory := source.NewMemorySource(2)
+	consumer := NewSourceConsumer(memory, &ConsumerConfig{Workers: 2}, nil)
+
+	const perStudent = 5
+	students := []string{"student-1", "student-2", "student-3"}
+
+	var mu sync.Mutex
+	handled := make(map[string][]int)
+	processed := 0
+	allProcessed := make(chan struct{})
+
+	err := consumer.Register(Route{
+		Topic: "executions",
+		Decode: func(ctx context.Context, message *sarama.ConsumerMessage) (interface{}, error) {
+			var event testEvent
+			if err := json.Unmarshal(message.Value, &event); err != nil {
+				return nil, fmt.Errorf("invalid event: %w", err)
+			}
+			return event, nil
+		},
+		Handle: func(ctx context.Context, values []interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, value := range values {
+				event := value.(testEvent)
+				handled[event.Student] = append(handled[event.Student], event.Sequence)
+				processed++
+				if processed == perStudent*len(students) {
+					close(allProcessed)
+				}
+			}
+			return nil
+		},
+		OrderingKey: func(value interface{}) string {
+			return value.(testEvent).Student
+		},
+		BatchSize:    3,
+		BatchTimeout: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	// Parte de los eventos se publica antes de iniciar y el resto con el consumidor en marcha
+	for sequence := 0; sequence < 2; sequence++ {
+		for _, student := range students {
+			publishTestEvent(t, memory, student, sequence)
+		}
+	}
+	// Un mensaje inválido no detiene la partición: sin dead-letter solo se registra y se marca
+	if _, err := memory.Publish("executions", []byte("student-1"), []byte("not json"), nil, time.Time{}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- consumer.Start(ctx)
+	}()
+
+	for sequence := 2; sequence < perStudent; sequence++ {
+		for _, student := range students {
+			publishTestEvent(t, memory, student, sequence)
+		}
+	}
+
+	select {
+	case <-allProcessed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for events to be processed")
+	}
+
+	mu.Lock()
+	for _, student := range students {
+		sequences := handled[student]
+		if len(sequences) != perStudent {
+			t.Errorf("%s: expected %d events, got %v", student, perStudent, sequences)
+			continue
+		}
+		for i, sequence := range sequences {
+			if sequence != i {
+				t.Errorf("%s: events processed out of order: %v", student, sequences)
+				break
+			}
+		}
+	}
+	mu.Unlock()
+
+	// Los offsets marcados llegan al final de cada partición, incluido el mensaje inválido
+	group := consumer.consumerGroup.(*sourceGroup)
+	ranges, err := memory.Partitions(context.Background(), "executions", nil, nil)
+	if err != nil {
+		t.Fatalf("Partitions: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for _, partitionRange := range ranges {
+		key := partitionKey{topic: "executions", partition: partitionRange.Partition}
+		for group.offset(key) != partitionRange.End {
+			if time.Now().After(deadline) {
+				t.Fatalf("partition %d: expected offset %d, got %d", partitionRange.Partition, partitionRange.End, group.offset(key))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("consumer did not stop after cancel")
+	}
+}
@@ -0,0 +1,55 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+
+	"github.com/IBM/sarama"
+)
+
+// UserRegistrationEventHandler define el contrato para procesar eventos de registro de usuarios
+type UserRegistrationEventHandler interface {
+	HandleUserRegistrationEvent(ctx context.Context, userReg *aggregates.UserRegistrationAnalytics) error
+}
+
+// NewUserRegistrationRoute crea la ruta para los eventos de registro de usuarios, procesados mensaje a mensaje.
+// timestamps define cómo interpretar la fecha de registro de los eventos.
+func NewUserRegistrationRoute(topic string, handler UserRegistrationEventHandler, payloadDecoder events.PayloadDecoder, timestamps events.TimestampPolicy) Route {
+	return Route{
+		Topic:      topic,
+		Name:       "user registration",
+		EventTypes: []string{events.UserRegisteredEventType},
+		Decode:     userRegistrationDecoder(payloadDecoder, timestamps),
+		BatchSize:  1,
+		Handle: func(ctx context.Context, values []interface{}) error {
+			for _, value := range values {
+				userReg := value.(*aggregates.UserRegistrationAnalytics)
+				if err := handler.HandleUserRegistrationEvent(ctx, userReg); err != nil {
+					return fmt.Errorf("error handling user registration event: %w", err)
+				}
+
+				log.Printf("Successfully processed user registration: %s (username: %s)",
+					userReg.UserID().Value(), userReg.Username())
+			}
+			return nil
+		},
+		OrderingKey: func(value interface{}) string {
+			return value.(*aggregates.UserRegistrationAnalytics).UserID().Value()
+		},
+	}
+}
+
+// userRegistrationDecoder deserializa el mensaje y lo convierte a dominio
+func userRegistrationDecoder(payloadDecoder events.PayloadDecoder, timestamps events.TimestampPolicy) Decoder {
+	return func(ctx context.Context, message *sarama.ConsumerMessage) (interface{}, error) {
+		envelope, err := decodeEnvelope(ctx, payloadDecoder, message, events.UserRegisteredEventType)
+		if err != nil {
+			return nil, err
+		}
+		return events.DecodeUserRegistration(envelope, message.Timestamp, timestamps)
+	}
+}
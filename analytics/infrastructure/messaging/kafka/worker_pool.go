@@ -0,0 +1,259 @@
+package kafka
+
+import (
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// dispatchedMessage es un mensaje asignado a un worker junto con la ruta que lo procesa
+type dispatchedMessage struct {
+	route   *Route
+	pending *pendingMessage
+}
+
+// consumeConcurrently reparte los mensajes de la partición entre los workers del consumidor.
+// Los mensajes con la misma clave de orden van siempre al mismo worker, que los procesa en orden;
+// los de claves distintas se procesan en paralelo. El offset solo avanza hasta el último mensaje
+// tal que todos los anteriores de la partición ya fueron procesados.
+func (h *consumerGroupHandler) consumeConcurrently(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, topicRoute *Route) error {
+	tracker := newOffsetTracker()
+	workers := make([]*claimWorker, h.consumer.workers)
+
+	var wg sync.WaitGroup
+	for i := range workers {
+		workers[i] = &claimWorker{
+			handler:  h,
+			session:  session,
+			claim:    claim,
+			tracker:  tracker,
+			messages: make(chan dispatchedMessage, topicRoute.BatchSize),
+		}
+		wg.Add(1)
+		go func(worker *claimWorker) {
+			defer wg.Done()
+			worker.run()
+		}(workers[i])
+	}
+
+	// Al salir, los workers procesan lo que ya recibieron (si la sesión sigue activa) antes de terminar el claim
+	defer func() {
+		for _, worker := range workers {
+			close(worker.messages)
+		}
+		wg.Wait()
+	}()
+
+	next := 0
+	for {
+		select {
+		case <-session.Context().Done():
+			// Los mensajes sin marcar se volverán a entregar
+			log.Printf("Session context done, stopping %s consumption", topicRoute.Name)
+			return nil
+		case message, ok := <-claim.Messages():
+			if !ok {
+				log.Printf("%s message channel closed", topicRoute.Name)
+				return nil
+			}
+
+			log.Printf("Received message from topic %s, partition %d, offset %d, timestamp: %v",
+				message.Topic, message.Partition, message.Offset, message.Timestamp)
+
+			route, routeErr := h.consumer.routeFor(message)
+			pending, ok := h.prepare(session, claim, route, routeErr, message)
+			if !ok {
+				return nil
+			}
+
+			// Los mensajes sin clave se reparten por turnos
+			var worker *claimWorker
+			if key := orderingKey(route, pending); key != "" {
+				worker = workers[workerIndex(key, len(workers))]
+			} else {
+				worker = workers[next]
+				next = (next + 1) % len(workers)
+			}
+
+			tracker.track(message)
+			select {
+			case worker.messages <- dispatchedMessage{route: route, pending: pending}:
+			case <-session.Context().Done():
+				log.Printf("Session context done, stopping %s consumption", topicRoute.Name)
+				return nil
+			}
+		}
+	}
+}
+
+// orderingKey retorna la clave de orden del mensaje: la de la ruta si pudo decodificarse, o la clave del mensaje
+func orderingKey(route *Route, pending *pendingMessage) string {
+	if pending.decoded && route.OrderingKey != nil {
+		if key := route.OrderingKey(pending.value); key != "" {
+			return key
+		}
+	}
+	return string(pending.message.Key)
+}
+
+// workerIndex asigna una clave a un worker
+func workerIndex(key string, workers int) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % uint32(workers))
+}
+
+// claimWorker procesa en micro-lotes los mensajes de una partición que le asigna el dispatcher
+type claimWorker struct {
+	handler  *consumerGroupHandler
+	session  sarama.ConsumerGroupSession
+	claim    sarama.ConsumerGroupClaim
+	tracker  *offsetTracker
+	messages chan dispatchedMessage
+}
+
+// run acumula los mensajes en lotes de una misma ruta y los procesa hasta que se cierra el canal
+func (w *claimWorker) run() {
+	var route *Route
+	batch := make([]*pendingMessage, 0)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	flush := func() bool {
+		timer.Stop()
+		if len(batch) == 0 {
+			return true
+		}
+		if !w.flush(route, batch) {
+			return false
+		}
+		batch = batch[:0]
+		return true
+	}
+
+	for {
+		select {
+		case <-timer.C:
+			if !flush() {
+				return
+			}
+		case dispatched, ok := <-w.messages:
+			if !ok {
+				if w.session.Context().Err() == nil {
+					flush()
+				}
+				return
+			}
+
+			if dispatched.route != route {
+				if !flush() {
+					return
+				}
+				route = dispatched.route
+			}
+
+			batch = append(batch, dispatched.pending)
+			if len(batch) == 1 && route.BatchSize > 1 {
+				timer.Reset(route.BatchTimeout)
+			}
+			if len(batch) >= route.BatchSize {
+				if !flush() {
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush procesa el lote y marca los offsets que quedaron contiguos. Retorna false si la sesión terminó antes de completar.
+func (w *claimWorker) flush(route *Route, batch []*pendingMessage) bool {
+	// Con varios workers el offset no puede guardarse en la transacción del lote: puede haber mensajes anteriores pendientes
+	if _, ok := w.handler.processBatch(w.session, w.claim, route, batch, false); !ok {
+		return false
+	}
+
+	messages := make([]*sarama.ConsumerMessage, 0, len(batch))
+	for _, pending := range batch {
+		messages = append(messages, pending.message)
+	}
+	return w.tracker.complete(messages, func(last *sarama.ConsumerMessage) bool {
+		return w.handler.markProcessed(w.session, w.claim, last, false)
+	})
+}
+
+// offsetTracker sigue los mensajes en vuelo de una partición para marcar los offsets en orden
+type offsetTracker struct {
+	mu       sync.Mutex
+	inFlight []*sarama.ConsumerMessage // Mensajes despachados, en orden de offset
+	done     map[int64]bool            // Offsets procesados que aún esperan a uno anterior
+	ready    *sarama.ConsumerMessage   // Último mensaje contiguo procesado, pendiente de marcar
+
+	// markMu serializa las marcas sin bloquear a los workers ni al dispatcher mientras se guarda el offset
+	markMu sync.Mutex
+	marked *sarama.ConsumerMessage
+}
+
+// newOffsetTracker crea un tracker vacío
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{
+		done: make(map[int64]bool),
+	}
+}
+
+// track registra un mensaje despachado a un worker
+func (t *offsetTracker) track(message *sarama.ConsumerMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inFlight = append(t.inFlight, message)
+}
+
+// complete registra los mensajes procesados y marca, con mark, el último mensaje cuyos anteriores ya terminaron.
+// mark se llama fuera del lock de seguimiento; las marcas se serializan y se omiten las que quedaron atrás,
+// para que el offset guardado nunca retroceda. Retorna false si mark falló.
+func (t *offsetTracker) complete(messages []*sarama.ConsumerMessage, mark func(last *sarama.ConsumerMessage) bool) bool {
+	if !t.advance(messages) {
+		return true
+	}
+
+	t.markMu.Lock()
+	defer t.markMu.Unlock()
+
+	// Otro worker pudo avanzar mientras se esperaba el turno: se marca el mensaje más reciente
+	t.mu.Lock()
+	last := t.ready
+	t.mu.Unlock()
+
+	if t.marked != nil && last.Offset <= t.marked.Offset {
+		return true
+	}
+	if !mark(last) {
+		return false
+	}
+	t.marked = last
+	return true
+}
+
+// advance registra los mensajes procesados y avanza ready hasta el primer mensaje aún en proceso.
+// Retorna true si ready avanzó.
+func (t *offsetTracker) advance(messages []*sarama.ConsumerMessage) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, message := range messages {
+		t.done[message.Offset] = true
+	}
+
+	advanced := false
+	for len(t.inFlight) > 0 && t.done[t.inFlight[0].Offset] {
+		t.ready = t.inFlight[0]
+		delete(t.done, t.ready.Offset)
+		t.inFlight[0] = nil
+		t.inFlight = t.inFlight[1:]
+		advanced = true
+	}
+	return advanced
+}
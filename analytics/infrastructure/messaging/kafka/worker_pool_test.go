@@ -0,0 +1,144 @@
+package kafka
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// trackMessages registra en el tracker mensajes con offsets consecutivos desde cero
+func trackMessages(tracker *offsetTracker, count int) []*sarama.ConsumerMessage {
+	messages := make([]*sarama.ConsumerMessage, count)
+	for i := range messages {
+		messages[i] = &sarama.ConsumerMessage{Offset: int64(i)}
+		tracker.track(messages[i])
+	}
+	return messages
+}
+
+func TestOffsetTrackerMarksInOrderWhenCompletionsArriveOutOfOrder(t *testing.T) {
+	tracker := newOffsetTracker()
+	messages := trackMessages(tracker, 6)
+
+	var marked []int64
+	mark := func(last *sarama.ConsumerMessage) bool {
+		marked = append(marked, last.Offset)
+		return true
+	}
+
+	steps := []struct {
+		completed []*sarama.ConsumerMessage
+		marked    []int64
+	}{
+		{[]*sarama.ConsumerMessage{messages[2]}, nil},
+		{[]*sarama.ConsumerMessage{messages[1], messages[4]}, nil},
+		{[]*sarama.ConsumerMessage{messages[0]}, []int64{2}},
+		{[]*sarama.ConsumerMessage{messages[5]}, []int64{2}},
+		{[]*sarama.ConsumerMessage{messages[3]}, []int64{2, 5}},
+	}
+
+	for i, step := range steps {
+		if !tracker.complete(step.completed, mark) {
+			t.Fatalf("step %d: complete returned false", i)
+		}
+		if !reflect.DeepEqual(marked, step.marked) {
+			t.Fatalf("step %d: expected marks %v, got %v", i, step.marked, marked)
+		}
+	}
+}
+
+func TestOffsetTrackerReportsMarkFailure(t *testing.T) {
+	tracker := newOffsetTracker()
+	messages := trackMessages(tracker, 2)
+
+	if tracker.complete(messages[:1], func(last *sarama.ConsumerMessage) bool { return false }) {
+		t.Fatalf("expected complete to report the failed mark")
+	}
+
+	// El siguiente avance vuelve a intentar la marca con el último mensaje contiguo
+	var marked int64 = -1
+	if !tracker.complete(messages[1:], func(last *sarama.ConsumerMessage) bool {
+		marked = last.Offset
+		return true
+	}) {
+		t.Fatalf("expected complete to succeed")
+	}
+	if marked != 1 {
+		t.Fatalf("expected offset 1 to be marked, got %d", marked)
+	}
+}
+
+func TestOffsetTrackerDoesNotHoldLockWhileMarking(t *testing.T) {
+	tracker := newOffsetTracker()
+	messages := trackMessages(tracker, 1)
+
+	marking := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan bool)
+	go func() {
+		finished <- tracker.complete(messages, func(last *sarama.ConsumerMessage) bool {
+			close(marking)
+			<-release
+			return true
+		})
+	}()
+
+	<-marking
+	tracked := make(chan struct{})
+	go func() {
+		tracker.track(&sarama.ConsumerMessage{Offset: 1})
+		close(tracked)
+	}()
+
+	select {
+	case <-tracked:
+	case <-time.After(time.Second):
+		t.Fatalf("track blocked while an offset was being marked")
+	}
+
+	close(release)
+	if !<-finished {
+		t.Fatalf("expected complete to succeed")
+	}
+}
+
+func TestOffsetTrackerNeverMarksBackwardsUnderConcurrentCompletions(t *testing.T) {
+	const count = 500
+
+	tracker := newOffsetTracker()
+	messages := trackMessages(tracker, count)
+
+	order := rand.Perm(count)
+	var mu sync.Mutex
+	var marked []int64
+	mark := func(last *sarama.ConsumerMessage) bool {
+		time.Sleep(time.Microsecond)
+		mu.Lock()
+		marked = append(marked, last.Offset)
+		mu.Unlock()
+		return true
+	}
+
+	var wg sync.WaitGroup
+	for _, index := range order {
+		wg.Add(1)
+		go func(message *sarama.ConsumerMessage) {
+			defer wg.Done()
+			tracker.complete([]*sarama.ConsumerMessage{message}, mark)
+		}(messages[index])
+	}
+	wg.Wait()
+
+	if len(marked) == 0 || marked[len(marked)-1] != count-1 {
+		t.Fatalf("expected last mark to be offset %d, got %v", count-1, marked)
+	}
+	for i := 1; i < len(marked); i++ {
+		if marked[i] <= marked[i-1] {
+			t.Fatalf("marks went backwards: %d after %d", marked[i], marked[i-1])
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchemaType identifica el formato de un esquema registrado
+type SchemaType string
+
+const (
+	SchemaTypeAvro     SchemaType = "AVRO"
+	SchemaTypeProtobuf SchemaType = "PROTOBUF"
+	SchemaTypeJSON     SchemaType = "JSON"
+)
+
+// Reference es una dependencia de un esquema (un import de Protobuf o un tipo con nombre de Avro)
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Schema es un esquema obtenido del Schema Registry
+type Schema struct {
+	ID         int         `json:"id"`
+	Subject    string      `json:"subject,omitempty"`
+	Version    int         `json:"version,omitempty"`
+	Type       SchemaType  `json:"schemaType,omitempty"`
+	Schema     string      `json:"schema"`
+	References []Reference `json:"references,omitempty"`
+}
+
+// Error representa una respuesta de error del Schema Registry o un fallo al contactarlo
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       int    `json:"error_code"`
+	Message    string `json:"message"`
+	Err        error  `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("schema registry unavailable: %v", e.Err)
+	}
+	return fmt.Sprintf("schema registry error %d (status %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Temporary indica si vale la pena reintentar: fallos de red, 5xx o limitación de tasa
+func (e *Error) Temporary() bool {
+	return e.Err != nil || e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// Client es un cliente REST compatible con el Schema Registry de Confluent.
+// Los esquemas son inmutables, por lo que se guardan en caché indefinidamente.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	username   string
+	password   string
+
+	mu        sync.RWMutex
+	byID      map[int]*Schema
+	bySubject map[string]*Schema
+}
+
+// NewClient crea un nuevo cliente del Schema Registry. Si httpClient es nil se usa uno con timeout de 10 segundos.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+		byID:       make(map[int]*Schema),
+		bySubject:  make(map[string]*Schema),
+	}
+}
+
+// SetBasicAuth configura las credenciales de autenticación básica
+func (c *Client) SetBasicAuth(username, password string) {
+	c.username = username
+	c.password = password
+}
+
+// GetSchemaByID obtiene un esquema por su ID global
+func (c *Client) GetSchemaByID(ctx context.Context, id int) (*Schema, error) {
+	c.mu.RLock()
+	schema, ok := c.byID[id]
+	c.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	schema = &Schema{}
+	if err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d", id), schema); err != nil {
+		return nil, fmt.Errorf("error getting schema %d: %w", id, err)
+	}
+	schema.ID = id
+	normalizeType(schema)
+
+	c.mu.Lock()
+	c.byID[id] = schema
+	c.mu.Unlock()
+
+	return schema, nil
+}
+
+// GetSchemaBySubjectVersion obtiene la versión de un subject, usada para resolver referencias
+func (c *Client) GetSchemaBySubjectVersion(ctx context.Context, subject string, version int) (*Schema, error) {
+	key := fmt.Sprintf("%s:%d", subject, version)
+
+	c.mu.RLock()
+	schema, ok := c.bySubject[key]
+	c.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	schema = &Schema{}
+	path := fmt.Sprintf("/subjects/%s/versions/%d", url.PathEscape(subject), version)
+	if err := c.get(ctx, path, schema); err != nil {
+		return nil, fmt.Errorf("error getting schema %s version %d: %w", subject, version, err)
+	}
+	normalizeType(schema)
+
+	c.mu.Lock()
+	c.bySubject[key] = schema
+	c.mu.Unlock()
+
+	return schema, nil
+}
+
+// get ejecuta una petición GET y deserializa la respuesta
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json, application/json")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &Error{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &Error{Err: err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		registryErr := &Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(body, registryErr) != nil || registryErr.Message == "" {
+			registryErr.Message = strings.TrimSpace(string(body))
+		}
+		return registryErr
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error unmarshaling schema registry response: %w", err)
+	}
+	return nil
+}
+
+// normalizeType aplica el tipo por defecto: el registro omite schemaType para Avro
+func normalizeType(schema *Schema) {
+	if schema.Type == "" {
+		schema.Type = SchemaTypeAvro
+	}
+}
@@ -0,0 +1,165 @@
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetSchemaByIDDefaultsToAvroAndCaches(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/schemas/ids/42" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"schema":"{\"type\":\"string\"}"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", nil)
+
+	for i := 0; i < 2; i++ {
+		schema, err := client.GetSchemaByID(context.Background(), 42)
+		if err != nil {
+			t.Fatalf("GetSchemaByID: %v", err)
+		}
+		if schema.ID != 42 || schema.Type != SchemaTypeAvro || schema.Schema != `{"type":"string"}` {
+			t.Fatalf("unexpected schema: %+v", schema)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("expected 1 request, got %d", got)
+	}
+}
+
+func TestGetSchemaBySubjectVersionEscapesAndCaches(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.EscapedPath() != "/subjects/common%2Ftypes.proto/versions/3" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		w.Write([]byte(`{"id":7,"subject":"common/types.proto","version":3,"schemaType":"PROTOBUF","schema":"syntax = \"proto3\";"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+
+	for i := 0; i < 2; i++ {
+		schema, err := client.GetSchemaBySubjectVersion(context.Background(), "common/types.proto", 3)
+		if err != nil {
+			t.Fatalf("GetSchemaBySubjectVersion: %v", err)
+		}
+		if schema.ID != 7 || schema.Type != SchemaTypeProtobuf {
+			t.Fatalf("unexpected schema: %+v", schema)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("expected 1 request, got %d", got)
+	}
+}
+
+func TestGetSendsBasicAuthAndAcceptHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "secret" {
+			t.Errorf("unexpected credentials %q %q %v", username, password, ok)
+		}
+		if r.Header.Get("Accept") == "" {
+			t.Errorf("missing Accept header")
+		}
+		w.Write([]byte(`{"schema":"{}"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetBasicAuth("user", "secret")
+
+	if _, err := client.GetSchemaByID(context.Background(), 1); err != nil {
+		t.Fatalf("GetSchemaByID: %v", err)
+	}
+}
+
+func TestErrorTemporaryClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		temporary bool
+		code      int
+		message   string
+	}{
+		{"not found", http.StatusNotFound, `{"error_code":40403,"message":"Schema not found"}`, false, 40403, "Schema not found"},
+		{"unauthorized", http.StatusUnauthorized, `Unauthorized`, false, 0, "Unauthorized"},
+		{"server error", http.StatusInternalServerError, `{"error_code":50001,"message":"Error in the backend data store"}`, true, 50001, "Error in the backend data store"},
+		{"rate limited", http.StatusTooManyRequests, `slow down`, true, 0, "slow down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, nil)
+			_, err := client.GetSchemaByID(context.Background(), 1)
+
+			var registryErr *Error
+			if !errors.As(err, &registryErr) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if registryErr.StatusCode != tt.status || registryErr.Code != tt.code || registryErr.Message != tt.message {
+				t.Fatalf("unexpected error: %+v", registryErr)
+			}
+			if registryErr.Temporary() != tt.temporary {
+				t.Fatalf("expected Temporary() = %v", tt.temporary)
+			}
+		})
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"schema":"{}"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+
+	if _, err := client.GetSchemaByID(context.Background(), 1); err == nil {
+		t.Fatalf("expected error on first request")
+	}
+	if _, err := client.GetSchemaByID(context.Background(), 1); err != nil {
+		t.Fatalf("expected retry to succeed: %v", err)
+	}
+}
+
+func TestNetworkErrorIsTemporary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	baseURL := server.URL
+	server.Close()
+
+	client := NewClient(baseURL, nil)
+	_, err := client.GetSchemaByID(context.Background(), 1)
+
+	var registryErr *Error
+	if !errors.As(err, &registryErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if registryErr.Err == nil || !registryErr.Temporary() {
+		t.Fatalf("expected temporary network error, got %+v", registryErr)
+	}
+}
@@ -0,0 +1,108 @@
+package serde
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/schemaregistry"
+
+	"github.com/hamba/avro/v2"
+)
+
+// AvroDecoder decodifica mensajes Avro en formato de cable de Confluent a JSON.
+// Los nombres de los campos del record se conservan tal cual.
+type AvroDecoder struct {
+	registry *schemaregistry.Client
+
+	mu      sync.Mutex
+	schemas map[int]avro.Schema
+}
+
+// NewAvroDecoder crea un nuevo decoder Avro que resuelve los esquemas en el Schema Registry
+func NewAvroDecoder(registry *schemaregistry.Client) *AvroDecoder {
+	return &AvroDecoder{
+		registry: registry,
+		schemas:  make(map[int]avro.Schema),
+	}
+}
+
+// Decode implementa events.PayloadDecoder
+func (d *AvroDecoder) Decode(ctx context.Context, data []byte) ([]byte, error) {
+	schemaID, payload, err := parseWireFormat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := d.schema(ctx, schemaID)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := avro.Unmarshal(schema, payload, &value); err != nil {
+		return nil, fmt.Errorf("error decoding avro message with schema %d: %w", schemaID, err)
+	}
+
+	result, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error converting avro message to JSON: %w", err)
+	}
+	return result, nil
+}
+
+// schema obtiene y compila el esquema Avro, incluyendo sus referencias
+func (d *AvroDecoder) schema(ctx context.Context, schemaID int) (avro.Schema, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if schema, ok := d.schemas[schemaID]; ok {
+		return schema, nil
+	}
+
+	registered, err := d.registry.GetSchemaByID(ctx, schemaID)
+	if err != nil {
+		return nil, err
+	}
+	if registered.Type != schemaregistry.SchemaTypeAvro {
+		return nil, fmt.Errorf("schema %d is %s, expected %s", schemaID, registered.Type, schemaregistry.SchemaTypeAvro)
+	}
+
+	// Cada esquema usa su propia caché de tipos con nombre para no mezclar versiones
+	cache := &avro.SchemaCache{}
+	if err := d.parseReferences(ctx, registered.References, cache, map[string]bool{}); err != nil {
+		return nil, err
+	}
+
+	schema, err := avro.ParseWithCache(registered.Schema, "", cache)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing avro schema %d: %w", schemaID, err)
+	}
+
+	d.schemas[schemaID] = schema
+	return schema, nil
+}
+
+// parseReferences registra en la caché los tipos con nombre de las referencias, en orden de dependencia
+func (d *AvroDecoder) parseReferences(ctx context.Context, references []schemaregistry.Reference, cache *avro.SchemaCache, seen map[string]bool) error {
+	for _, ref := range references {
+		key := fmt.Sprintf("%s:%d", ref.Subject, ref.Version)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		referenced, err := d.registry.GetSchemaBySubjectVersion(ctx, ref.Subject, ref.Version)
+		if err != nil {
+			return err
+		}
+		if err := d.parseReferences(ctx, referenced.References, cache, seen); err != nil {
+			return err
+		}
+		if _, err := avro.ParseWithCache(referenced.Schema, "", cache); err != nil {
+			return fmt.Errorf("error parsing avro reference %s: %w", ref.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package serde
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/schemaregistry"
+
+	"github.com/hamba/avro/v2"
+)
+
+const (
+	testAvroLanguageSchema  = `{"type":"enum","name":"Language","namespace":"analytics.test","symbols":["GO","PYTHON"]}`
+	testAvroExecutionSchema = `{"type":"record","name":"Execution","namespace":"analytics.test","fields":[
+		{"name":"execution_id","type":"string"},
+		{"name":"language","type":"analytics.test.Language"},
+		{"name":"memory_kb","type":"long"}
+	]}`
+)
+
+func TestAvroDecoderResolvesReferences(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/schemas/ids/4":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"schema":     testAvroExecutionSchema,
+				"references": []map[string]interface{}{{"name": "analytics.test.Language", "subject": "language", "version": 1}},
+			})
+		case "/subjects/language/versions/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 3, "schema": testAvroLanguageSchema})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cache := &avro.SchemaCache{}
+	if _, err := avro.ParseWithCache(testAvroLanguageSchema, "", cache); err != nil {
+		t.Fatalf("parsing reference: %v", err)
+	}
+	schema, err := avro.ParseWithCache(testAvroExecutionSchema, "", cache)
+	if err != nil {
+		t.Fatalf("parsing schema: %v", err)
+	}
+
+	payload, err := avro.Marshal(schema, map[string]interface{}{
+		"execution_id": "exec-1",
+		"language":     "PYTHON",
+		"memory_kb":    int64(2048),
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	decoded, err := NewAvroDecoder(schemaregistry.NewClient(server.URL, nil)).Decode(context.Background(), wireFormat(4, payload))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(decoded, &result); err != nil {
+		t.Fatalf("decoded payload is not JSON: %v", err)
+	}
+	if result["execution_id"] != "exec-1" || result["language"] != "PYTHON" || result["memory_kb"] != float64(2048) {
+		t.Fatalf("unexpected payload %s", decoded)
+	}
+}
+
+func TestAvroDecoderRequiresWireFormat(t *testing.T) {
+	decoder := NewAvroDecoder(schemaregistry.NewClient("http://localhost", nil))
+
+	if _, err := decoder.Decode(context.Background(), []byte(`{"execution_id":"1"}`)); err == nil {
+		t.Fatalf("expected error for message without wire format")
+	}
+}
@@ -0,0 +1,36 @@
+package serde
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/schemaregistry"
+)
+
+// Formatos de serialización soportados por tópico
+const (
+	FormatJSON     = "json"
+	FormatAvro     = "avro"
+	FormatProtobuf = "protobuf"
+)
+
+// NewDecoder crea el decoder para el formato indicado. Avro y Protobuf requieren un cliente del Schema Registry.
+func NewDecoder(format string, registry *schemaregistry.Client) (events.PayloadDecoder, error) {
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		return NewJSONDecoder(), nil
+	case FormatAvro:
+		if registry == nil {
+			return nil, fmt.Errorf("format %s requires a schema registry", FormatAvro)
+		}
+		return NewAvroDecoder(registry), nil
+	case FormatProtobuf:
+		if registry == nil {
+			return nil, fmt.Errorf("format %s requires a schema registry", FormatProtobuf)
+		}
+		return NewProtobufDecoder(registry), nil
+	default:
+		return nil, fmt.Errorf("unsupported message format: %s", format)
+	}
+}
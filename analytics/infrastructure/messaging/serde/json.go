@@ -0,0 +1,31 @@
+package serde
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONDecoder acepta JSON plano o JSON Schema en formato de cable (se descarta el prefijo con el ID de esquema)
+type JSONDecoder struct{}
+
+// NewJSONDecoder crea un nuevo decoder JSON
+func NewJSONDecoder() *JSONDecoder {
+	return &JSONDecoder{}
+}
+
+// Decode implementa events.PayloadDecoder
+func (d *JSONDecoder) Decode(ctx context.Context, data []byte) ([]byte, error) {
+	if hasWireFormat(data) {
+		_, payload, err := parseWireFormat(data)
+		if err != nil {
+			return nil, err
+		}
+		data = payload
+	}
+
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("message is not valid JSON")
+	}
+	return data, nil
+}
@@ -0,0 +1,36 @@
+package serde
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJSONDecoderStripsWireFormat(t *testing.T) {
+	decoder := NewJSONDecoder()
+
+	for _, data := range [][]byte{
+		[]byte(`{"execution_id":"1"}`),
+		wireFormat(5, []byte(`{"execution_id":"1"}`)),
+	} {
+		decoded, err := decoder.Decode(context.Background(), data)
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		if string(decoded) != `{"execution_id":"1"}` {
+			t.Fatalf("unexpected payload %q", decoded)
+		}
+	}
+}
+
+func TestJSONDecoderRejectsInvalidJSON(t *testing.T) {
+	decoder := NewJSONDecoder()
+
+	for _, data := range [][]byte{
+		[]byte(`{"execution_id":`),
+		wireFormat(5, []byte("not json")),
+	} {
+		if _, err := decoder.Decode(context.Background(), data); err == nil {
+			t.Fatalf("expected error for %q", data)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package serde
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/schemaregistry"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtobufDecoder decodifica mensajes Protobuf en formato de cable de Confluent a JSON.
+// Los campos se emiten con su nombre del .proto, los enums por nombre y los Timestamp en RFC 3339.
+type ProtobufDecoder struct {
+	registry *schemaregistry.Client
+
+	mu    sync.Mutex
+	files map[int]protoreflect.FileDescriptor
+}
+
+// NewProtobufDecoder crea un nuevo decoder Protobuf que resuelve los esquemas en el Schema Registry
+func NewProtobufDecoder(registry *schemaregistry.Client) *ProtobufDecoder {
+	return &ProtobufDecoder{
+		registry: registry,
+		files:    make(map[int]protoreflect.FileDescriptor),
+	}
+}
+
+// Decode implementa events.PayloadDecoder
+func (d *ProtobufDecoder) Decode(ctx context.Context, data []byte) ([]byte, error) {
+	schemaID, payload, err := parseWireFormat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, payload, err := readMessageIndexes(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := d.file(ctx, schemaID)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor, err := messageByIndexes(file, indexes)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving message in schema %d: %w", schemaID, err)
+	}
+
+	message := dynamicpb.NewMessage(descriptor)
+	if err := proto.Unmarshal(payload, message); err != nil {
+		return nil, fmt.Errorf("error decoding protobuf message %s: %w", descriptor.FullName(), err)
+	}
+
+	result, err := json.Marshal(protoMessageToMap(message))
+	if err != nil {
+		return nil, fmt.Errorf("error converting protobuf message to JSON: %w", err)
+	}
+	return result, nil
+}
+
+// file obtiene y compila el archivo .proto del esquema junto con sus imports
+func (d *ProtobufDecoder) file(ctx context.Context, schemaID int) (protoreflect.FileDescriptor, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if file, ok := d.files[schemaID]; ok {
+		return file, nil
+	}
+
+	registered, err := d.registry.GetSchemaByID(ctx, schemaID)
+	if err != nil {
+		return nil, err
+	}
+	if registered.Type != schemaregistry.SchemaTypeProtobuf {
+		return nil, fmt.Errorf("schema %d is %s, expected %s", schemaID, registered.Type, schemaregistry.SchemaTypeProtobuf)
+	}
+
+	fileName := fmt.Sprintf("schema-%d.proto", schemaID)
+	sources := map[string]string{fileName: registered.Schema}
+	if err := d.collectReferences(ctx, registered.References, sources); err != nil {
+		return nil, err
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	compiled, err := compiler.Compile(ctx, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling protobuf schema %d: %w", schemaID, err)
+	}
+
+	file := compiled[0]
+	d.files[schemaID] = file
+	return file, nil
+}
+
+// collectReferences agrega al mapa de fuentes los archivos importados, indexados por su nombre de import
+func (d *ProtobufDecoder) collectReferences(ctx context.Context, references []schemaregistry.Reference, sources map[string]string) error {
+	for _, ref := range references {
+		if _, ok := sources[ref.Name]; ok {
+			continue
+		}
+
+		referenced, err := d.registry.GetSchemaBySubjectVersion(ctx, ref.Subject, ref.Version)
+		if err != nil {
+			return err
+		}
+		sources[ref.Name] = referenced.Schema
+
+		if err := d.collectReferences(ctx, referenced.References, sources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// messageByIndexes ubica el mensaje dentro del archivo siguiendo los índices de mensajes anidados
+func messageByIndexes(file protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	messages := file.Messages()
+	var descriptor protoreflect.MessageDescriptor
+
+	for _, index := range indexes {
+		if index >= messages.Len() {
+			return nil, fmt.Errorf("message index %d out of range", index)
+		}
+		descriptor = messages.Get(index)
+		messages = descriptor.Messages()
+	}
+
+	if descriptor == nil {
+		return nil, fmt.Errorf("schema has no messages")
+	}
+	return descriptor, nil
+}
+
+// protoMessageToMap convierte un mensaje a un mapa serializable como JSON.
+// Los campos sin valor se omiten, igual que en el JSON de origen.
+func protoMessageToMap(message protoreflect.Message) map[string]interface{} {
+	result := make(map[string]interface{})
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		result[string(field.Name())] = protoFieldToInterface(field, value)
+		return true
+	})
+	return result
+}
+
+// protoFieldToInterface convierte el valor de un campo, incluyendo listas y mapas
+func protoFieldToInterface(field protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	switch {
+	case field.IsList():
+		list := value.List()
+		items := make([]interface{}, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			items = append(items, protoValueToInterface(field, list.Get(i)))
+		}
+		return items
+	case field.IsMap():
+		entries := make(map[string]interface{})
+		value.Map().Range(func(key protoreflect.MapKey, entry protoreflect.Value) bool {
+			entries[key.String()] = protoValueToInterface(field.MapValue(), entry)
+			return true
+		})
+		return entries
+	default:
+		return protoValueToInterface(field, value)
+	}
+}
+
+// protoValueToInterface convierte un valor singular, resolviendo los tipos conocidos de google.protobuf
+func protoValueToInterface(field protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		message := value.Message()
+		fullName := string(message.Descriptor().FullName())
+		fields := message.Descriptor().Fields()
+
+		switch {
+		case fullName == "google.protobuf.Timestamp":
+			seconds := message.Get(fields.ByName("seconds")).Int()
+			nanos := message.Get(fields.ByName("nanos")).Int()
+			return time.Unix(seconds, nanos).UTC()
+		case strings.HasPrefix(fullName, "google.protobuf.") && strings.HasSuffix(fullName, "Value") && fields.ByName("value") != nil:
+			// Wrappers (StringValue, Int64Value...): se emite el valor envuelto
+			valueField := fields.ByName("value")
+			return protoValueToInterface(valueField, message.Get(valueField))
+		}
+		return protoMessageToMap(message)
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return int32(value.Enum())
+	default:
+		return value.Interface()
+	}
+}
@@ -0,0 +1,170 @@
+package serde
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/schemaregistry"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const testProtoSchema = `syntax = "proto3";
+package analytics.test;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+message Heartbeat {
+  string source = 1;
+}
+
+message Envelope {
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    STATUS_ACCEPTED = 1;
+  }
+
+  message Execution {
+    string execution_id = 1;
+    Status status = 2;
+    google.protobuf.Timestamp created_at = 3;
+    google.protobuf.Int64Value memory_kb = 4;
+    repeated string tags = 5;
+  }
+
+  string id = 1;
+}
+`
+
+// newTestRegistry levanta un Schema Registry que sirve testProtoSchema con el ID 9
+func newTestRegistry(t *testing.T) *schemaregistry.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/ids/9" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"schemaType": "PROTOBUF", "schema": testProtoSchema})
+	}))
+	t.Cleanup(server.Close)
+
+	return schemaregistry.NewClient(server.URL, nil)
+}
+
+// compileTestSchema compila testProtoSchema para codificar los mensajes de prueba
+func compileTestSchema(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{"test.proto": testProtoSchema}),
+		}),
+	}
+	compiled, err := compiler.Compile(context.Background(), "test.proto")
+	if err != nil {
+		t.Fatalf("compiling test schema: %v", err)
+	}
+	return compiled[0]
+}
+
+func TestProtobufDecoderResolvesNestedMessage(t *testing.T) {
+	file := compileTestSchema(t)
+	descriptor := file.Messages().ByName("Envelope").Messages().ByName("Execution")
+
+	message := dynamicpb.NewMessage(descriptor)
+	fields := descriptor.Fields()
+	message.Set(fields.ByName("execution_id"), protoreflect.ValueOfString("exec-1"))
+	message.Set(fields.ByName("status"), protoreflect.ValueOfEnum(1))
+
+	createdAt := message.Mutable(fields.ByName("created_at")).Message()
+	createdAt.Set(createdAt.Descriptor().Fields().ByName("seconds"), protoreflect.ValueOfInt64(1704164645))
+
+	memory := message.Mutable(fields.ByName("memory_kb")).Message()
+	memory.Set(memory.Descriptor().Fields().ByName("value"), protoreflect.ValueOfInt64(2048))
+
+	tags := message.Mutable(fields.ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("go"))
+	tags.Append(protoreflect.ValueOfString("easy"))
+
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	// Envelope es el segundo mensaje del archivo y Execution el primero anidado: índices [1, 0]
+	data := wireFormat(9, append(messageIndexes(1, 0), payload...))
+
+	decoded, err := NewProtobufDecoder(newTestRegistry(t)).Decode(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(decoded, &result); err != nil {
+		t.Fatalf("decoded payload is not JSON: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"execution_id": "exec-1",
+		"status":       "STATUS_ACCEPTED",
+		"created_at":   "2024-01-02T03:04:05Z",
+		"memory_kb":    float64(2048),
+	}
+	for key, value := range expected {
+		if result[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, result[key])
+		}
+	}
+	if tags, ok := result["tags"].([]interface{}); !ok || len(tags) != 2 || tags[0] != "go" || tags[1] != "easy" {
+		t.Errorf("tags: unexpected value %v", result["tags"])
+	}
+}
+
+func TestProtobufDecoderUsesFirstMessageForZeroCount(t *testing.T) {
+	file := compileTestSchema(t)
+	descriptor := file.Messages().ByName("Heartbeat")
+
+	message := dynamicpb.NewMessage(descriptor)
+	message.Set(descriptor.Fields().ByName("source"), protoreflect.ValueOfString("runner"))
+
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	decoded, err := NewProtobufDecoder(newTestRegistry(t)).Decode(context.Background(), wireFormat(9, append(messageIndexes(), payload...)))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if string(decoded) != `{"source":"runner"}` {
+		t.Fatalf("unexpected payload %s", decoded)
+	}
+}
+
+func TestProtobufDecoderRejectsOutOfRangeIndex(t *testing.T) {
+	decoder := NewProtobufDecoder(newTestRegistry(t))
+
+	if _, err := decoder.Decode(context.Background(), wireFormat(9, messageIndexes(1, 5))); err == nil {
+		t.Fatalf("expected error for out-of-range message index")
+	}
+}
+
+func TestProtobufDecoderRejectsNonProtobufSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"schema":"{\"type\":\"string\"}"}`))
+	}))
+	defer server.Close()
+
+	decoder := NewProtobufDecoder(schemaregistry.NewClient(server.URL, nil))
+	if _, err := decoder.Decode(context.Background(), wireFormat(3, messageIndexes())); err == nil {
+		t.Fatalf("expected error for Avro schema")
+	}
+}
@@ -0,0 +1,53 @@
+package serde
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// magicByte es el primer byte del formato de cable de Confluent: magic byte + ID de esquema (4 bytes, big-endian) + payload
+const magicByte = 0x0
+
+// hasWireFormat indica si el mensaje usa el formato de cable de Confluent
+func hasWireFormat(data []byte) bool {
+	return len(data) >= 5 && data[0] == magicByte
+}
+
+// parseWireFormat separa el ID de esquema del payload
+func parseWireFormat(data []byte) (int, []byte, error) {
+	if !hasWireFormat(data) {
+		return 0, nil, fmt.Errorf("message is not in schema registry wire format")
+	}
+
+	schemaID := int(binary.BigEndian.Uint32(data[1:5]))
+	return schemaID, data[5:], nil
+}
+
+// readMessageIndexes lee los índices que ubican el mensaje Protobuf dentro del archivo .proto.
+// Un conteo de cero es la abreviatura de [0], el primer mensaje del archivo.
+func readMessageIndexes(data []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(data)
+	if n <= 0 {
+		return nil, nil, fmt.Errorf("invalid protobuf message index count")
+	}
+	data = data[n:]
+
+	if count == 0 {
+		return []int{0}, data, nil
+	}
+	if count < 0 || count > int64(len(data)) {
+		return nil, nil, fmt.Errorf("invalid protobuf message index count: %d", count)
+	}
+
+	indexes := make([]int, 0, count)
+	for i := int64(0); i < count; i++ {
+		index, n := binary.Varint(data)
+		if n <= 0 || index < 0 {
+			return nil, nil, fmt.Errorf("invalid protobuf message index at position %d", i)
+		}
+		indexes = append(indexes, int(index))
+		data = data[n:]
+	}
+
+	return indexes, data, nil
+}
@@ -0,0 +1,104 @@
+package serde
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// wireFormat arma un mensaje en formato de cable de Confluent
+func wireFormat(schemaID uint32, payload []byte) []byte {
+	data := []byte{magicByte}
+	data = binary.BigEndian.AppendUint32(data, schemaID)
+	return append(data, payload...)
+}
+
+// messageIndexes codifica los índices de mensaje como varints zigzag, precedidos por su conteo
+func messageIndexes(indexes ...int) []byte {
+	data := binary.AppendVarint(nil, int64(len(indexes)))
+	for _, index := range indexes {
+		data = binary.AppendVarint(data, int64(index))
+	}
+	return data
+}
+
+func TestParseWireFormat(t *testing.T) {
+	schemaID, payload, err := parseWireFormat(wireFormat(258, []byte("payload")))
+	if err != nil {
+		t.Fatalf("parseWireFormat: %v", err)
+	}
+	if schemaID != 258 || string(payload) != "payload" {
+		t.Fatalf("unexpected result: %d %q", schemaID, payload)
+	}
+
+	schemaID, payload, err = parseWireFormat(wireFormat(1, nil))
+	if err != nil || schemaID != 1 || len(payload) != 0 {
+		t.Fatalf("expected empty payload, got %d %q %v", schemaID, payload, err)
+	}
+}
+
+func TestParseWireFormatRejectsInvalidFraming(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":       nil,
+		"too short":   {magicByte, 0, 0, 1},
+		"wrong magic": {1, 0, 0, 0, 1, '{', '}'},
+		"plain json":  []byte(`{"execution_id":"1"}`),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if hasWireFormat(data) {
+				t.Fatalf("hasWireFormat(%v) = true", data)
+			}
+			if _, _, err := parseWireFormat(data); err == nil {
+				t.Fatalf("expected error for %v", data)
+			}
+		})
+	}
+}
+
+func TestReadMessageIndexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		indexes []int
+	}{
+		{"zero count is first message", append(messageIndexes(), 'x'), []int{0}},
+		{"single index", append(messageIndexes(2), 'x'), []int{2}},
+		{"nested indexes", append(messageIndexes(1, 0, 3), 'x'), []int{1, 0, 3}},
+		{"large index", append(messageIndexes(200), 'x'), []int{200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexes, rest, err := readMessageIndexes(tt.data)
+			if err != nil {
+				t.Fatalf("readMessageIndexes: %v", err)
+			}
+			if !reflect.DeepEqual(indexes, tt.indexes) {
+				t.Fatalf("expected %v, got %v", tt.indexes, indexes)
+			}
+			if string(rest) != "x" {
+				t.Fatalf("expected remaining payload %q, got %q", "x", rest)
+			}
+		})
+	}
+}
+
+func TestReadMessageIndexesRejectsInvalidData(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":           nil,
+		"negative count":  binary.AppendVarint(nil, -1),
+		"count too large": binary.AppendVarint(nil, 5),
+		"truncated":       append(binary.AppendVarint(nil, 1), 0x80),
+		"negative index":  binary.AppendVarint(binary.AppendVarint(nil, 1), -3),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := readMessageIndexes(data); err == nil {
+				t.Fatalf("expected error for %v", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package source
+
+import (
+	"sync"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+)
+
+// channelReader entrega los mensajes que produce la goroutine de lectura de una fuente. Implementa events.MessageReader.
+type channelReader struct {
+	messages  chan *events.Message
+	errors    chan error
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newChannelReader crea un lector; la goroutine de lectura debe cerrar messages al terminar
+func newChannelReader() *channelReader {
+	return &channelReader{
+		messages: make(chan *events.Message),
+		errors:   make(chan error, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+func (r *channelReader) Messages() <-chan *events.Message {
+	return r.messages
+}
+
+func (r *channelReader) Errors() <-chan error {
+	return r.errors
+}
+
+// Close detiene la goroutine de lectura
+func (r *channelReader) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+	return nil
+}
@@ -0,0 +1,139 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+)
+
+// FileSource lee eventos de archivos JSONL/NDJSON con un evento por línea, por ejemplo volcados de tópicos
+// de producción. Implementa events.MessageSource: cada tópico es un archivo con una única partición, el offset
+// de un mensaje es su número de línea (sin contar las líneas en blanco) y el valor es la línea completa.
+// Los archivos son fuentes acotadas: los lectores terminan al llegar al final del archivo.
+type FileSource struct {
+	files map[string]string // Ruta del archivo por tópico
+}
+
+// NewFileSource crea una fuente con un archivo por tópico
+func NewFileSource(files map[string]string) *FileSource {
+	return &FileSource{files: files}
+}
+
+// Partitions retorna la única partición del tópico, con un offset por evento del archivo.
+// Las líneas no traen el timestamp de publicación, por lo que no admite ventanas de tiempo.
+func (s *FileSource) Partitions(ctx context.Context, topic string, from, to *time.Time) ([]events.PartitionRange, error) {
+	if from != nil || to != nil {
+		return nil, events.ErrTimeWindowUnsupported
+	}
+
+	file, err := s.open(topic)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var count int64
+	err = readLines(file, func(line []byte) bool {
+		count++
+		return ctx.Err() == nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading events file of topic %s: %w", topic, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return []events.PartitionRange{{Partition: 0, Start: 0, End: count}}, nil
+}
+
+// Read entrega los eventos del archivo desde offset hasta el final
+func (s *FileSource) Read(ctx context.Context, topic string, partition int32, offset int64) (events.MessageReader, error) {
+	if partition != 0 {
+		return nil, errors.New("partition out of range")
+	}
+
+	file, err := s.open(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := newChannelReader()
+	go func() {
+		defer close(reader.messages)
+		defer file.Close()
+
+		var next int64
+		err := readLines(file, func(line []byte) bool {
+			current := next
+			next++
+			if current < offset {
+				return true
+			}
+
+			message := &events.Message{
+				Topic:     topic,
+				Partition: 0,
+				Offset:    current,
+				Value:     line,
+				Headers:   map[string]string{},
+			}
+			select {
+			case reader.messages <- message:
+				return true
+			case <-reader.done:
+				return false
+			case <-ctx.Done():
+				return false
+			}
+		})
+		if err != nil {
+			select {
+			case reader.errors <- fmt.Errorf("error reading events file of topic %s: %w", topic, err):
+			case <-reader.done:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return reader, nil
+}
+
+// open abre el archivo del tópico
+func (s *FileSource) open(topic string) (*os.File, error) {
+	path, ok := s.files[topic]
+	if !ok {
+		return nil, fmt.Errorf("no events file configured for topic %s", topic)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening events file of topic %s: %w", topic, err)
+	}
+	return file, nil
+}
+
+// readLines entrega cada línea no vacía del archivo, sin el salto de línea, hasta el final o hasta que handle retorne false
+func readLines(file io.Reader, handle func(line []byte) bool) error {
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			if !handle(trimmed) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+)
+
+// writeEventsFile crea un archivo JSONL temporal con el contenido indicado
+func writeEventsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing events file: %v", err)
+	}
+	return path
+}
+
+// readAll lee los mensajes del lector hasta que se cierra
+func readAll(t *testing.T, reader events.MessageReader) []*events.Message {
+	t.Helper()
+
+	var messages []*events.Message
+	for {
+		select {
+		case message, ok := <-reader.Messages():
+			if !ok {
+				return messages
+			}
+			messages = append(messages, message)
+		case err := <-reader.Errors():
+			t.Fatalf("reader error: %v", err)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out reading events file")
+		}
+	}
+}
+
+func TestFileSourceSkipsBlankLines(t *testing.T) {
+	path := writeEventsFile(t, "{\"n\":0}\n\n  \n{\"n\":1}\r\n{\"n\":2}")
+	source := NewFileSource(map[string]string{"executions": path})
+
+	ranges, err := source.Partitions(context.Background(), "executions", nil, nil)
+	if err != nil {
+		t.Fatalf("Partitions: %v", err)
+	}
+	if len(ranges) != 1 || ranges[0] != (events.PartitionRange{Partition: 0, Start: 0, End: 3}) {
+		t.Fatalf("unexpected ranges %+v", ranges)
+	}
+
+	reader, err := source.Read(context.Background(), "executions", 0, 0)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	defer reader.Close()
+
+	messages := readAll(t, reader)
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+	for i, message := range messages {
+		if message.Offset != int64(i) || message.Topic != "executions" {
+			t.Fatalf("unexpected message %+v", message)
+		}
+	}
+	if string(messages[1].Value) != `{"n":1}` {
+		t.Fatalf("expected trimmed line, got %q", messages[1].Value)
+	}
+}
+
+func TestFileSourceReadsFromOffset(t *testing.T) {
+	path := writeEventsFile(t, "{\"n\":0}\n{\"n\":1}\n{\"n\":2}\n")
+	source := NewFileSource(map[string]string{"executions": path})
+
+	reader, err := source.Read(context.Background(), "executions", 0, 2)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	defer reader.Close()
+
+	messages := readAll(t, reader)
+	if len(messages) != 1 || messages[0].Offset != 2 || string(messages[0].Value) != `{"n":2}` {
+		t.Fatalf("unexpected messages %+v", messages)
+	}
+}
+
+func TestFileSourceRejectsTimeWindows(t *testing.T) {
+	source := NewFileSource(map[string]string{"executions": writeEventsFile(t, "{}\n")})
+	from := time.Now()
+
+	if _, err := source.Partitions(context.Background(), "executions", &from, nil); !errors.Is(err, events.ErrTimeWindowUnsupported) {
+		t.Fatalf("expected ErrTimeWindowUnsupported, got %v", err)
+	}
+}
+
+func TestFileSourceRejectsUnknownTopicAndPartition(t *testing.T) {
+	source := NewFileSource(map[string]string{"executions": writeEventsFile(t, "{}\n")})
+
+	if _, err := source.Partitions(context.Background(), "registrations", nil, nil); err == nil {
+		t.Fatalf("expected error for topic without file")
+	}
+	if _, err := source.Read(context.Background(), "executions", 1, 0); err == nil {
+		t.Fatalf("expected error for partition out of range")
+	}
+}
@@ -0,0 +1,189 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+)
+
+// ErrSourceClosed se retorna al publicar en una fuente cerrada
+var ErrSourceClosed = errors.New("message source is closed")
+
+// MemorySource es una fuente de mensajes en memoria para pruebas de extremo a extremo y herramientas locales.
+// Implementa events.MessageSource: los lectores reciben los mensajes publicados en orden y esperan
+// mensajes nuevos hasta Close.
+type MemorySource struct {
+	partitions int32
+
+	mu        sync.Mutex
+	topics    map[string][][]*events.Message // Mensajes por tópico y partición
+	published chan struct{}                  // Se cierra al publicar un mensaje o cerrar la fuente
+	closed    bool
+}
+
+// NewMemorySource crea una fuente vacía cuyos tópicos tienen la cantidad de particiones indicada
+func NewMemorySource(partitions int) *MemorySource {
+	if partitions <= 0 {
+		partitions = 1
+	}
+	return &MemorySource{
+		partitions: int32(partitions),
+		topics:     make(map[string][][]*events.Message),
+		published:  make(chan struct{}),
+	}
+}
+
+// Publish agrega un mensaje al tópico. La partición se elige por la clave (la 0 si no tiene) y el offset es
+// la posición del mensaje en su partición. Si no se indica Timestamp se usa la hora actual.
+func (s *MemorySource) Publish(topic string, key, value []byte, headers map[string]string, timestamp time.Time) (*events.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrSourceClosed
+	}
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	partitions := s.topic(topic)
+	partition := s.partitionFor(key)
+	message := &events.Message{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    int64(len(partitions[partition])),
+		Key:       key,
+		Value:     value,
+		Headers:   headers,
+		Timestamp: timestamp,
+	}
+	partitions[partition] = append(partitions[partition], message)
+
+	close(s.published)
+	s.published = make(chan struct{})
+	return message, nil
+}
+
+// Close deja de aceptar mensajes. Los lectores terminan después de entregar los mensajes ya publicados.
+func (s *MemorySource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.published)
+	}
+	return nil
+}
+
+// Partitions retorna el rango de offsets de cada partición. Con from o to, el rango se limita a los mensajes
+// con timestamp en [from, to).
+func (s *MemorySource) Partitions(ctx context.Context, topic string, from, to *time.Time) ([]events.PartitionRange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	partitions := s.topic(topic)
+	ranges := make([]events.PartitionRange, 0, len(partitions))
+	for partition, messages := range partitions {
+		start, end := int64(0), int64(len(messages))
+		if from != nil {
+			start = firstAtOrAfter(messages, *from)
+		}
+		if to != nil {
+			end = firstAtOrAfter(messages, *to)
+		}
+		if end < start {
+			end = start
+		}
+		ranges = append(ranges, events.PartitionRange{Partition: int32(partition), Start: start, End: end})
+	}
+	return ranges, nil
+}
+
+// firstAtOrAfter retorna el offset del primer mensaje con timestamp >= t, o el final de la partición
+func firstAtOrAfter(messages []*events.Message, t time.Time) int64 {
+	for _, message := range messages {
+		if !message.Timestamp.Before(t) {
+			return message.Offset
+		}
+	}
+	return int64(len(messages))
+}
+
+// Read entrega los mensajes de la partición desde offset y espera los nuevos hasta que se cierre la fuente o el lector
+func (s *MemorySource) Read(ctx context.Context, topic string, partition int32, offset int64) (events.MessageReader, error) {
+	if partition < 0 || partition >= s.partitions {
+		return nil, errors.New("partition out of range")
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	reader := newChannelReader()
+	go func() {
+		defer close(reader.messages)
+
+		for next := offset; ; next++ {
+			message, wait, ok := s.next(topic, partition, next)
+			for message == nil {
+				if !ok {
+					return
+				}
+				select {
+				case <-wait:
+				case <-reader.done:
+					return
+				case <-ctx.Done():
+					return
+				}
+				message, wait, ok = s.next(topic, partition, next)
+			}
+
+			select {
+			case reader.messages <- message:
+			case <-reader.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
+
+// next retorna el mensaje del offset si ya fue publicado; si no, el canal que avisa la próxima publicación
+// y false si la fuente está cerrada
+func (s *MemorySource) next(topic string, partition int32, offset int64) (*events.Message, <-chan struct{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := s.topic(topic)[partition]
+	if offset < int64(len(messages)) {
+		return messages[offset], nil, true
+	}
+	return nil, s.published, !s.closed
+}
+
+// topic retorna las particiones del tópico, creándolo si no existe. Debe llamarse con s.mu tomado.
+func (s *MemorySource) topic(topic string) [][]*events.Message {
+	partitions, ok := s.topics[topic]
+	if !ok {
+		partitions = make([][]*events.Message, s.partitions)
+		s.topics[topic] = partitions
+	}
+	return partitions
+}
+
+// partitionFor asigna una partición a la clave
+func (s *MemorySource) partitionFor(key []byte) int32 {
+	if len(key) == 0 {
+		return 0
+	}
+	hash := fnv.New32a()
+	hash.Write(key)
+	return int32(hash.Sum32() % uint32(s.partitions))
+}
@@ -0,0 +1,153 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nanab/analytics-service/analytics/application/events"
+)
+
+// receive espera el siguiente mensaje del lector
+func receive(t *testing.T, reader events.MessageReader) *events.Message {
+	t.Helper()
+
+	select {
+	case message, ok := <-reader.Messages():
+		if !ok {
+			t.Fatalf("reader closed unexpectedly")
+		}
+		return message
+	case err := <-reader.Errors():
+		t.Fatalf("reader error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for message")
+	}
+	return nil
+}
+
+func TestMemorySourceAssignsOffsetsPerPartition(t *testing.T) {
+	source := NewMemorySource(1)
+
+	for i := int64(0); i < 3; i++ {
+		message, err := source.Publish("executions", nil, []byte("{}"), nil, time.Time{})
+		if err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+		if message.Partition != 0 || message.Offset != i || message.Timestamp.IsZero() {
+			t.Fatalf("unexpected message %+v", message)
+		}
+	}
+
+	ranges, err := source.Partitions(context.Background(), "executions", nil, nil)
+	if err != nil {
+		t.Fatalf("Partitions: %v", err)
+	}
+	if len(ranges) != 1 || ranges[0] != (events.PartitionRange{Partition: 0, Start: 0, End: 3}) {
+		t.Fatalf("unexpected ranges %+v", ranges)
+	}
+}
+
+func TestMemorySourceRoutesKeysToTheSamePartition(t *testing.T) {
+	source := NewMemorySource(4)
+
+	first, _ := source.Publish("executions", []byte("student-1"), []byte("{}"), nil, time.Time{})
+	second, _ := source.Publish("executions", []byte("student-1"), []byte("{}"), nil, time.Time{})
+	if first.Partition != second.Partition || second.Offset != first.Offset+1 {
+		t.Fatalf("messages with the same key landed in %d/%d at offsets %d/%d",
+			first.Partition, second.Partition, first.Offset, second.Offset)
+	}
+
+	ranges, _ := source.Partitions(context.Background(), "executions", nil, nil)
+	if len(ranges) != 4 {
+		t.Fatalf("expected 4 partitions, got %d", len(ranges))
+	}
+}
+
+func TestMemorySourcePartitionsByTimeWindow(t *testing.T) {
+	source := NewMemorySource(1)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		source.Publish("executions", nil, []byte("{}"), nil, base.Add(time.Duration(i)*time.Hour))
+	}
+
+	from := base.Add(time.Hour)
+	to := base.Add(3 * time.Hour)
+	ranges, err := source.Partitions(context.Background(), "executions", &from, &to)
+	if err != nil {
+		t.Fatalf("Partitions: %v", err)
+	}
+	if ranges[0].Start != 1 || ranges[0].End != 3 {
+		t.Fatalf("expected offsets [1, 3), got [%d, %d)", ranges[0].Start, ranges[0].End)
+	}
+
+	late := base.Add(24 * time.Hour)
+	ranges, _ = source.Partitions(context.Background(), "executions", &late, nil)
+	if ranges[0].Start != 5 || ranges[0].End != 5 {
+		t.Fatalf("expected empty range at the end, got [%d, %d)", ranges[0].Start, ranges[0].End)
+	}
+}
+
+func TestMemorySourceReadWaitsForNewMessagesUntilClose(t *testing.T) {
+	source := NewMemorySource(1)
+	source.Publish("executions", nil, []byte(`{"n":0}`), nil, time.Time{})
+	source.Publish("executions", nil, []byte(`{"n":1}`), nil, time.Time{})
+
+	reader, err := source.Read(context.Background(), "executions", 0, 1)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	defer reader.Close()
+
+	if message := receive(t, reader); message.Offset != 1 {
+		t.Fatalf("expected offset 1, got %d", message.Offset)
+	}
+
+	source.Publish("executions", nil, []byte(`{"n":2}`), nil, time.Time{})
+	if message := receive(t, reader); message.Offset != 2 || string(message.Value) != `{"n":2}` {
+		t.Fatalf("unexpected message %+v", message)
+	}
+
+	source.Close()
+	select {
+	case _, ok := <-reader.Messages():
+		if ok {
+			t.Fatalf("expected reader to finish after Close")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("reader did not finish after Close")
+	}
+
+	if _, err := source.Publish("executions", nil, []byte("{}"), nil, time.Time{}); !errors.Is(err, ErrSourceClosed) {
+		t.Fatalf("expected ErrSourceClosed, got %v", err)
+	}
+}
+
+func TestMemorySourceReadStopsOnContextCancel(t *testing.T) {
+	source := NewMemorySource(1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	reader, err := source.Read(ctx, "executions", 0, 0)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-reader.Messages():
+		if ok {
+			t.Fatalf("expected no messages")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("reader did not stop after cancel")
+	}
+}
+
+func TestMemorySourceRejectsUnknownPartition(t *testing.T) {
+	source := NewMemorySource(2)
+
+	if _, err := source.Read(context.Background(), "executions", 2, 0); err == nil {
+		t.Fatalf("expected error for partition out of range")
+	}
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// BackfillExecutionOutcomes deriva el outcome de las ejecuciones guardadas antes de que existiera la columna,
+// y de las que se guardaron con un outcome que contradice success. Procesa por lotes de batchInsertChunkSize
+// y retorna la cantidad de ejecuciones actualizadas.
+func BackfillExecutionOutcomes(db *gorm.DB) (int64, error) {
+	accepted := valueobjects.OutcomeAccepted.Value()
+	if err := db.Model(&ExecutionAnalyticsModel{}).
+		Where("outcome <> '' AND (outcome = ?) <> success", accepted).
+		UpdateColumn("outcome", "").Error; err != nil {
+		return 0, fmt.Errorf("error clearing outcomes that contradict success: %w", err)
+	}
+
+	var updated int64
+	for {
+		var rows []struct {
+			ID          uint
+			Status      string
+			ExitCode    int
+			TotalTests  int
+			PassedTests int
+			Success     bool
+		}
+		if err := db.Model(&ExecutionAnalyticsModel{}).
+			Select("id, status, exit_code, total_tests, passed_tests, success").
+			Where("outcome = ''").
+			Order("id").
+			Limit(batchInsertChunkSize).
+			Scan(&rows).Error; err != nil {
+			return updated, fmt.Errorf("error reading executions without outcome: %w", err)
+		}
+		if len(rows) == 0 {
+			return updated, nil
+		}
+
+		byOutcome := make(map[valueobjects.ExecutionOutcome][]uint)
+		for _, row := range rows {
+			status, err := valueobjects.NewExecutionStatus(row.Status)
+			if err != nil {
+				return updated, fmt.Errorf("invalid status of execution %d: %w", row.ID, err)
+			}
+			outcome := valueobjects.DeriveExecutionOutcome(status, row.ExitCode, row.TotalTests, row.PassedTests, row.Success)
+			byOutcome[outcome] = append(byOutcome[outcome], row.ID)
+		}
+
+		for outcome, ids := range byOutcome {
+			result := db.Model(&ExecutionAnalyticsModel{}).
+				Where("id IN ?", ids).
+				UpdateColumn("outcome", outcome.Value())
+			if result.Error != nil {
+				return updated, fmt.Errorf("error saving derived outcomes: %w", result.Error)
+			}
+			updated += result.RowsAffected
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// BackfillExecutionScores calcula el puntaje ponderado de las ejecuciones guardadas antes de que existiera
+// la columna. Sus test results tienen el peso por defecto, así que los puntos son los tests aprobados; sin
+// test results se usa el porcentaje de tests aprobados, como aggregates.ExecutionAnalytics.WeightedScore.
+// Procesa por lotes de batchInsertChunkSize y retorna la cantidad de ejecuciones actualizadas.
+func BackfillExecutionScores(db *gorm.DB) (int64, error) {
+	var updated int64
+	for {
+		result := db.Exec(`
+			UPDATE execution_analytics SET
+				earned_points = points.earned,
+				total_points = points.total,
+				score = CASE
+					WHEN points.total > 0 THEN points.earned * 100.0 / points.total
+					WHEN execution_analytics.total_tests > 0 THEN execution_analytics.passed_tests * 100.0 / execution_analytics.total_tests
+					ELSE 0
+				END
+			FROM (
+				SELECT
+					pending.id,
+					COALESCE(SUM(CASE WHEN test_results.passed THEN test_results.weight ELSE 0 END), 0) as earned,
+					COALESCE(SUM(test_results.weight), 0) as total
+				FROM (
+					SELECT id FROM execution_analytics WHERE score IS NULL ORDER BY id LIMIT ?
+				) pending
+				LEFT JOIN test_results ON test_results.execution_analytics_id = pending.id
+				GROUP BY pending.id
+			) points
+			WHERE execution_analytics.id = points.id
+		`, batchInsertChunkSize)
+		if result.Error != nil {
+			return updated, fmt.Errorf("error saving execution scores: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return updated, nil
+		}
+		updated += result.RowsAffected
+	}
+}
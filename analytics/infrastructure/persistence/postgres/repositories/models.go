@@ -7,11 +7,15 @@ import (
 // ExecutionAnalyticsModel es el modelo GORM para persistencia
 type ExecutionAnalyticsModel struct {
 	ID              uint              `gorm:"primaryKey"`
+	EventID         string            `gorm:"index;type:varchar(255)"`
 	ExecutionID     string            `gorm:"uniqueIndex;not null;type:uuid"`
 	ChallengeID     string            `gorm:"index;not null;type:uuid"`
 	CodeVersionID   string            `gorm:"type:uuid"`
 	StudentID       string            `gorm:"index;not null;type:uuid"`
-	Language        string            `gorm:"index;not null"`
+	Language        string            `gorm:"index;not null"` // Nombre canónico, "other" fuera del registro
+	LanguageFamily  string            `gorm:"index;not null;default:''"`
+	LanguageVersion string            `gorm:"not null;default:''"`
+	LanguageRaw     string            `gorm:"not null;default:''"` // Valor recibido, vacío en registros anteriores al registro
 	Status          string            `gorm:"not null"`
 	Timestamp       time.Time         `gorm:"index;not null"`
 	ExecutionTimeMs int64             `gorm:"not null"`
@@ -20,7 +24,13 @@ type ExecutionAnalyticsModel struct {
 	PassedTests     int               `gorm:"not null"`
 	FailedTests     int               `gorm:"not null"`
 	Success         bool              `gorm:"index;not null"`
+	Outcome         string            `gorm:"index;not null;default:''"` // Vacío solo hasta el backfill de registros anteriores
+	Score           *float64          // Puntaje ponderado (0 a 100), nil solo hasta el backfill de registros anteriores
+	EarnedPoints    float64           `gorm:"not null;default:0"`
+	TotalPoints     float64           `gorm:"not null;default:0"`
 	ServerInstance  string            `gorm:"not null"`
+	Revision        int64             `gorm:"not null;default:0"`
+	JudgedAt        *time.Time        // nil = registros anteriores al versionado, equivale a Timestamp
 	CreatedAt       time.Time         `gorm:"autoCreateTime"`
 	UpdatedAt       time.Time         `gorm:"autoUpdateTime"`
 	TestResults     []TestResultModel `gorm:"foreignKey:ExecutionAnalyticsID;constraint:OnDelete:CASCADE"`
@@ -33,12 +43,18 @@ func (ExecutionAnalyticsModel) TableName() string {
 
 // TestResultModel es el modelo GORM para resultados de tests
 type TestResultModel struct {
-	ID                   uint      `gorm:"primaryKey"`
-	ExecutionAnalyticsID uint      `gorm:"index;not null"`
-	TestID               string    `gorm:"not null;type:uuid"`
-	TestName             string    `gorm:"not null"`
-	Passed               bool      `gorm:"not null"`
-	ErrorMessage         string    `gorm:"type:text"`
+	ID                   uint   `gorm:"primaryKey"`
+	ExecutionAnalyticsID uint   `gorm:"index;not null"`
+	TestID               string `gorm:"not null;type:uuid"`
+	TestName             string `gorm:"not null"`
+	Passed               bool   `gorm:"not null"`
+	ErrorMessage         string `gorm:"type:text"`
+	DurationMs           *int64
+	Visibility           string    `gorm:"not null;default:'public'"`
+	Weight               float64   `gorm:"not null;default:1"`
+	ExpectedOutput       string    `gorm:"type:text"` // Truncada a entities.MaxTestOutputLength caracteres
+	ActualOutput         string    `gorm:"type:text"`
+	OutputTruncated      bool      `gorm:"not null;default:false"`
 	CreatedAt            time.Time `gorm:"autoCreateTime"`
 }
 
@@ -50,6 +66,7 @@ func (TestResultModel) TableName() string {
 // UserRegistrationAnalyticsModel es el modelo GORM para persistencia de registros de usuarios en la comunidad
 type UserRegistrationAnalyticsModel struct {
 	ID           uint       `gorm:"primaryKey"`
+	EventID      string     `gorm:"index;type:varchar(255)"`
 	UserID       string     `gorm:"uniqueIndex;not null;type:uuid"`
 	ProfileID    string     `gorm:"index;not null;type:uuid"`
 	Username     string     `gorm:"index;not null"`
@@ -63,3 +80,195 @@ type UserRegistrationAnalyticsModel struct {
 func (UserRegistrationAnalyticsModel) TableName() string {
 	return "user_registration_analytics"
 }
+
+// QuarantinedEventModel es el modelo GORM para mensajes de Kafka que no pudieron ser procesados
+type QuarantinedEventModel struct {
+	ID              uint      `gorm:"primaryKey"`
+	Topic           string    `gorm:"index:idx_quarantined_events_source;not null"`
+	Partition       int32     `gorm:"index:idx_quarantined_events_source;not null"`
+	Offset          int64     `gorm:"index:idx_quarantined_events_source;not null"`
+	MessageKey      string    `gorm:"type:text"`
+	Payload         []byte    `gorm:"type:bytea"`
+	Error           string    `gorm:"type:text;not null"`
+	Attempts        int       `gorm:"not null"`
+	DeadLetterTopic string    `gorm:"not null"`
+	QuarantinedAt   time.Time `gorm:"index;not null"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (QuarantinedEventModel) TableName() string {
+	return "quarantined_events"
+}
+
+// ConsumerOffsetModel es el modelo GORM para los offsets de Kafka guardados junto con los datos ingeridos
+type ConsumerOffsetModel struct {
+	GroupID   string    `gorm:"primaryKey;type:varchar(255)"`
+	Topic     string    `gorm:"primaryKey;type:varchar(255)"`
+	Partition int32     `gorm:"primaryKey;autoIncrement:false"`
+	Offset    int64     `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (ConsumerOffsetModel) TableName() string {
+	return "consumer_offsets"
+}
+
+// SyncJobModel es el modelo GORM para los jobs de sincronización desde Kafka
+type SyncJobModel struct {
+	ID           string     `gorm:"primaryKey;type:uuid"`
+	Target       string     `gorm:"index;not null"`
+	Topic        string     `gorm:"not null"`
+	FromTime     *time.Time `gorm:"column:from_time"` // Ventana del replay (nil = tópico completo)
+	ToTime       *time.Time `gorm:"column:to_time"`
+	DryRun       bool       `gorm:"not null;default:false"`
+	Status       string     `gorm:"index;not null"`
+	ActiveTarget *string    `gorm:"uniqueIndex"` // Igual a Target mientras el job está activo: impide jobs concurrentes
+	Synced       int64      `gorm:"not null"`
+	Skipped      int64      `gorm:"not null"`
+	Invalid      int64      `gorm:"not null"`
+	Missing      int64      `gorm:"not null;default:0"`
+	Orphaned     int64      `gorm:"not null;default:0"`
+	ErrorMessage string     `gorm:"type:text"`
+	CreatedAt    time.Time  `gorm:"index;not null"`
+	StartedAt    *time.Time
+	FinishedAt   *time.Time
+	UpdatedAt    time.Time             `gorm:"not null"`
+	Checkpoints  []SyncCheckpointModel `gorm:"foreignKey:SyncJobID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName especifica el nombre de la tabla
+func (SyncJobModel) TableName() string {
+	return "sync_jobs"
+}
+
+// SyncCheckpointModel es el modelo GORM para el avance de un job de sincronización por partición
+type SyncCheckpointModel struct {
+	SyncJobID   string    `gorm:"primaryKey;type:uuid"`
+	Partition   int32     `gorm:"primaryKey;autoIncrement:false"`
+	StartOffset int64     `gorm:"not null"`
+	NextOffset  int64     `gorm:"not null"`
+	EndOffset   int64     `gorm:"not null"`
+	Incomplete  bool      `gorm:"not null;default:false"` // Terminada antes de llegar a EndOffset
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (SyncCheckpointModel) TableName() string {
+	return "sync_checkpoints"
+}
+
+// SyncReconciliationItemModel es el modelo GORM para las diferencias encontradas por un job de sincronización
+type SyncReconciliationItemModel struct {
+	ID        uint      `gorm:"primaryKey"`
+	SyncJobID string    `gorm:"uniqueIndex:idx_sync_reconciliation_items_source;not null;type:uuid"`
+	Kind      string    `gorm:"uniqueIndex:idx_sync_reconciliation_items_source;not null"`
+	Key       string    `gorm:"uniqueIndex:idx_sync_reconciliation_items_source;not null;type:varchar(255)"`
+	Partition *int32    `gorm:"uniqueIndex:idx_sync_reconciliation_items_source"` // nil para registros sin evento
+	Offset    *int64    `gorm:"uniqueIndex:idx_sync_reconciliation_items_source"`
+	Reason    string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (SyncReconciliationItemModel) TableName() string {
+	return "sync_reconciliation_items"
+}
+
+// SyncSeenKeyModel es el modelo GORM para los IDs de negocio leídos del tópico por un job en dry-run
+type SyncSeenKeyModel struct {
+	SyncJobID string `gorm:"primaryKey;type:uuid"`
+	Key       string `gorm:"primaryKey;type:varchar(255)"`
+}
+
+// TableName especifica el nombre de la tabla
+func (SyncSeenKeyModel) TableName() string {
+	return "sync_seen_keys"
+}
+
+// OutboxEventModel es el modelo GORM para los eventos de dominio pendientes de publicar en Kafka
+type OutboxEventModel struct {
+	ID          uint       `gorm:"primaryKey"`
+	EventID     string     `gorm:"uniqueIndex;not null;type:uuid"`
+	Type        string     `gorm:"index;not null"`
+	MessageKey  string     `gorm:"type:varchar(255)"`
+	Payload     []byte     `gorm:"type:jsonb;not null"`
+	OccurredAt  time.Time  `gorm:"not null"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+	PublishedAt *time.Time `gorm:"index"` // nil = pendiente
+	Attempts    int        `gorm:"not null;default:0"`
+	LastError   string     `gorm:"type:text"`
+	FailedAt    *time.Time `gorm:"index"` // Apartado tras agotar los intentos de publicación
+}
+
+// TableName especifica el nombre de la tabla
+func (OutboxEventModel) TableName() string {
+	return "outbox_events"
+}
+
+// ChallengeModel es el modelo GORM para el catálogo de challenges (dimensión de los KPIs)
+type ChallengeModel struct {
+	ChallengeID  string     `gorm:"primaryKey;type:uuid"`
+	EventID      string     `gorm:"type:varchar(255)"`
+	Title        string     `gorm:"not null;default:''"`
+	Difficulty   string     `gorm:"index;not null;default:''"`
+	Tags         []byte     `gorm:"type:jsonb;not null;default:'[]'"`
+	CourseID     string     `gorm:"index;type:varchar(255);not null;default:''"`
+	TestCount    int        `gorm:"not null;default:0"`
+	Archived     bool       `gorm:"index;not null;default:false"`
+	AttributesAt *time.Time // Fecha del último evento que modificó los atributos (nil = nunca)
+	StatusAt     *time.Time // Fecha del último evento que modificó el estado (nil = nunca)
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (ChallengeModel) TableName() string {
+	return "challenges"
+}
+
+// CodeVersionModel es el modelo GORM para las versiones de código de los estudiantes
+type CodeVersionModel struct {
+	CodeVersionID   string    `gorm:"primaryKey;type:uuid"`
+	EventID         string    `gorm:"type:varchar(255)"`
+	ChallengeID     string    `gorm:"index:idx_code_versions_student_challenge,priority:2;not null;type:uuid"`
+	StudentID       string    `gorm:"index:idx_code_versions_student_challenge,priority:1;not null;type:uuid"`
+	VersionNumber   int       `gorm:"not null"`
+	ParentVersionID *string   `gorm:"type:uuid"`
+	LinesChanged    int       `gorm:"not null;default:0"`
+	VersionedAt     time.Time `gorm:"not null"` // Fecha de la versión en el servicio de origen
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (CodeVersionModel) TableName() string {
+	return "code_versions"
+}
+
+// ExecutionAnalyticsHistoryModel es el modelo GORM para las versiones reemplazadas de una ejecución (auditoría)
+type ExecutionAnalyticsHistoryModel struct {
+	ID                   uint      `gorm:"primaryKey"`
+	ExecutionAnalyticsID uint      `gorm:"index;not null"`
+	ExecutionID          string    `gorm:"index;not null;type:uuid"`
+	EventID              string    `gorm:"type:varchar(255)"`
+	Revision             int64     `gorm:"not null"`
+	JudgedAt             time.Time `gorm:"not null"`
+	Status               string    `gorm:"not null"`
+	ExecutionTimeMs      int64     `gorm:"not null"`
+	ExitCode             int       `gorm:"not null"`
+	TotalTests           int       `gorm:"not null"`
+	PassedTests          int       `gorm:"not null"`
+	FailedTests          int       `gorm:"not null"`
+	Success              bool      `gorm:"not null"`
+	Outcome              string    `gorm:"not null;default:''"`
+	Score                *float64
+	TestResults          []byte    `gorm:"type:jsonb;not null"` // Test results de la versión reemplazada
+	ReplacedByEventID    string    `gorm:"type:varchar(255)"`
+	ReplacedAt           time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (ExecutionAnalyticsHistoryModel) TableName() string {
+	return "execution_analytics_history"
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresChallengeRepository implementa el catálogo de challenges usando PostgreSQL
+type PostgresChallengeRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresChallengeRepository crea una nueva instancia del repositorio
+func NewPostgresChallengeRepository(db *gorm.DB) repositories.ChallengeRepository {
+	return &PostgresChallengeRepository{db: db}
+}
+
+// Save aplica los atributos y el estado del challenge, cada uno solo si el evento es más reciente
+// que el último que lo modificó
+func (r *PostgresChallengeRepository) Save(ctx context.Context, challenge *aggregates.Challenge) (bool, error) {
+	tags, err := json.Marshal(challenge.Tags())
+	if err != nil {
+		return false, fmt.Errorf("error marshaling challenge tags: %w", err)
+	}
+	occurredAt := challenge.OccurredAt()
+
+	applied := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if challenge.ChangesAttributes() {
+			model := ChallengeModel{
+				ChallengeID:  challenge.ChallengeID().Value(),
+				EventID:      challenge.EventID(),
+				Title:        challenge.Title(),
+				Difficulty:   challenge.Difficulty(),
+				Tags:         tags,
+				CourseID:     challenge.CourseID(),
+				TestCount:    challenge.TestCount(),
+				AttributesAt: &occurredAt,
+			}
+			result := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "challenge_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"event_id", "title", "difficulty", "tags", "course_id", "test_count", "attributes_at", "updated_at"}),
+				Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "challenges.attributes_at IS NULL OR challenges.attributes_at < EXCLUDED.attributes_at"}}},
+			}).Create(&model)
+			if result.Error != nil {
+				return fmt.Errorf("error saving challenge attributes: %w", result.Error)
+			}
+			applied = applied || result.RowsAffected > 0
+		}
+
+		if challenge.ChangesStatus() {
+			model := ChallengeModel{
+				ChallengeID: challenge.ChallengeID().Value(),
+				EventID:     challenge.EventID(),
+				Tags:        []byte("[]"),
+				Archived:    challenge.Archived(),
+				StatusAt:    &occurredAt,
+			}
+			result := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "challenge_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"event_id", "archived", "status_at", "updated_at"}),
+				Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "challenges.status_at IS NULL OR challenges.status_at < EXCLUDED.status_at"}}},
+			}).Create(&model)
+			if result.Error != nil {
+				return fmt.Errorf("error saving challenge status: %w", result.Error)
+			}
+			applied = applied || result.RowsAffected > 0
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// FindByChallengeID busca un challenge del catálogo
+func (r *PostgresChallengeRepository) FindByChallengeID(ctx context.Context, challengeID valueobjects.ChallengeID) (*aggregates.Challenge, error) {
+	var model ChallengeModel
+
+	if err := r.db.WithContext(ctx).
+		Where("challenge_id = ?", challengeID.Value()).
+		First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model)
+}
+
+// toDomain convierte del modelo de persistencia al aggregate. La fecha del challenge es la del último
+// evento aplicado.
+func (r *PostgresChallengeRepository) toDomain(model *ChallengeModel) (*aggregates.Challenge, error) {
+	challengeID, err := valueobjects.NewChallengeID(model.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	if err := json.Unmarshal(model.Tags, &tags); err != nil {
+		return nil, fmt.Errorf("error reading tags of challenge %s: %w", model.ChallengeID, err)
+	}
+
+	var occurredAt time.Time
+	for _, versionAt := range []*time.Time{model.AttributesAt, model.StatusAt} {
+		if versionAt != nil && versionAt.After(occurredAt) {
+			occurredAt = *versionAt
+		}
+	}
+
+	return aggregates.RestoreChallenge(
+		challengeID,
+		model.EventID,
+		model.Title,
+		model.Difficulty,
+		tags,
+		model.CourseID,
+		model.TestCount,
+		model.Archived,
+		occurredAt,
+	), nil
+}
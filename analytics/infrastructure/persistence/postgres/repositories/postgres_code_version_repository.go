@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresCodeVersionRepository implementa el repositorio de versiones de código usando PostgreSQL
+type PostgresCodeVersionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresCodeVersionRepository crea una nueva instancia del repositorio
+func NewPostgresCodeVersionRepository(db *gorm.DB) repositories.CodeVersionRepository {
+	return &PostgresCodeVersionRepository{db: db}
+}
+
+// Save guarda una versión de código si no existe
+func (r *PostgresCodeVersionRepository) Save(ctx context.Context, version *aggregates.CodeVersion) (bool, error) {
+	model := CodeVersionModel{
+		CodeVersionID: version.CodeVersionID().Value(),
+		EventID:       version.EventID(),
+		ChallengeID:   version.ChallengeID().Value(),
+		StudentID:     version.StudentID().Value(),
+		VersionNumber: version.VersionNumber(),
+		LinesChanged:  version.LinesChanged(),
+		VersionedAt:   version.CreatedAt(),
+	}
+	if parent := version.ParentVersionID(); parent != nil {
+		parentID := parent.Value()
+		model.ParentVersionID = &parentID
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Exists indica si la versión de código ya está guardada
+func (r *PostgresCodeVersionRepository) Exists(ctx context.Context, codeVersionID valueobjects.CodeVersionID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&CodeVersionModel{}).
+		Where("code_version_id = ?", codeVersionID.Value()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetTimeline combina las versiones recibidas con un resumen de las ejecuciones de cada versión
+func (r *PostgresCodeVersionRepository) GetTimeline(ctx context.Context, studentID valueobjects.StudentID, challengeID valueobjects.ChallengeID) ([]repositories.CodeVersionTimelineEntry, error) {
+	var versions []CodeVersionModel
+	if err := r.db.WithContext(ctx).
+		Where("student_id = ? AND challenge_id = ?", studentID.Value(), challengeID.Value()).
+		Order("version_number").
+		Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	// Última ejecución de cada versión, con el total de ejecuciones y la fecha de la primera
+	var executions []struct {
+		CodeVersionID   string
+		Executions      int64
+		FirstExecutedAt time.Time
+		LastExecutedAt  time.Time
+		PassedTests     int
+		TotalTests      int
+		ExitCode        int
+		ExecutionTimeMs int64
+		Success         bool
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (code_version_id)
+			code_version_id,
+			COUNT(*) OVER versions as executions,
+			MIN(timestamp) OVER versions as first_executed_at,
+			timestamp as last_executed_at,
+			passed_tests,
+			total_tests,
+			exit_code,
+			execution_time_ms,
+			success
+		FROM execution_analytics
+		WHERE student_id = ? AND challenge_id = ? AND code_version_id IS NOT NULL
+		WINDOW versions AS (PARTITION BY code_version_id)
+		ORDER BY code_version_id, timestamp DESC, id DESC
+	`, studentID.Value(), challengeID.Value()).Scan(&executions).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]repositories.CodeVersionTimelineEntry, 0, len(versions)+len(executions))
+	byVersionID := make(map[string]int, len(versions))
+	for _, version := range versions {
+		createdAt := version.VersionedAt
+		entry := repositories.CodeVersionTimelineEntry{
+			CodeVersionID: version.CodeVersionID,
+			VersionNumber: version.VersionNumber,
+			LinesChanged:  version.LinesChanged,
+			CreatedAt:     &createdAt,
+		}
+		if version.ParentVersionID != nil {
+			entry.ParentVersionID = *version.ParentVersionID
+		}
+		byVersionID[version.CodeVersionID] = len(entries)
+		entries = append(entries, entry)
+	}
+
+	for _, execution := range executions {
+		index, ok := byVersionID[execution.CodeVersionID]
+		if !ok {
+			index = len(entries)
+			entries = append(entries, repositories.CodeVersionTimelineEntry{CodeVersionID: execution.CodeVersionID})
+		}
+
+		firstExecutedAt, lastExecutedAt := execution.FirstExecutedAt, execution.LastExecutedAt
+		entry := &entries[index]
+		entry.Executions = execution.Executions
+		entry.FirstExecutedAt = &firstExecutedAt
+		entry.LastExecutedAt = &lastExecutedAt
+		entry.PassedTests = execution.PassedTests
+		entry.TotalTests = execution.TotalTests
+		entry.ExitCode = execution.ExitCode
+		entry.ExecutionTimeMs = execution.ExecutionTimeMs
+		entry.Success = execution.Success
+	}
+
+	// Las versiones sin evento se ubican por la fecha de su primera ejecución
+	sort.SliceStable(entries, func(i, j int) bool {
+		return timelineDate(entries[i]).Before(timelineDate(entries[j]))
+	})
+
+	return entries, nil
+}
+
+// timelineDate es la fecha que ordena una versión en el timeline
+func timelineDate(entry repositories.CodeVersionTimelineEntry) time.Time {
+	if entry.CreatedAt != nil {
+		return *entry.CreatedAt
+	}
+	return *entry.FirstExecutedAt
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresConsumerOffsetRepository implementa el repositorio de offsets de Kafka usando PostgreSQL
+type PostgresConsumerOffsetRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresConsumerOffsetRepository crea una nueva instancia del repositorio
+func NewPostgresConsumerOffsetRepository(db *gorm.DB) repositories.ConsumerOffsetRepository {
+	return &PostgresConsumerOffsetRepository{db: db}
+}
+
+// Save guarda el offset de una partición
+func (r *PostgresConsumerOffsetRepository) Save(ctx context.Context, offset repositories.ConsumerOffset) error {
+	return saveConsumerOffset(r.db.WithContext(ctx), offset)
+}
+
+// FindByGroupAndTopic retorna los offsets guardados de un tópico, por partición
+func (r *PostgresConsumerOffsetRepository) FindByGroupAndTopic(ctx context.Context, groupID, topic string) (map[int32]int64, error) {
+	var models []ConsumerOffsetModel
+	if err := r.db.WithContext(ctx).
+		Where("group_id = ? AND topic = ?", groupID, topic).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	offsets := make(map[int32]int64, len(models))
+	for _, model := range models {
+		offsets[model.Partition] = model.Offset
+	}
+	return offsets, nil
+}
+
+// saveConsumerOffset inserta o actualiza un offset. Recibe la transacción de quien guarda los datos
+// del lote para que ambos se confirmen juntos.
+func saveConsumerOffset(tx *gorm.DB, offset repositories.ConsumerOffset) error {
+	model := ConsumerOffsetModel{
+		GroupID:   offset.GroupID,
+		Topic:     offset.Topic,
+		Partition: offset.Partition,
+		Offset:    offset.Offset,
+		UpdatedAt: time.Now(),
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_id"}, {Name: "topic"}, {Name: "partition"}},
+		DoUpdates: clause.AssignmentColumns([]string{"offset", "updated_at"}),
+	}).Create(&model).Error
+}
@@ -6,35 +6,441 @@ import (
 	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
 	"github.com/nanab/analytics-service/analytics/domain/repositories"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PostgresExecutionAnalyticsRepository implementa el repositorio usando PostgreSQL
 type PostgresExecutionAnalyticsRepository struct {
-	db *gorm.DB
+	db            *gorm.DB
+	derivedEvents *aggregates.DerivedEventPolicy // nil = no se generan eventos de dominio
 }
 
-// NewPostgresExecutionAnalyticsRepository crea una nueva instancia del repositorio
-func NewPostgresExecutionAnalyticsRepository(db *gorm.DB) repositories.ExecutionAnalyticsRepository {
-	return &PostgresExecutionAnalyticsRepository{db: db}
+// NewPostgresExecutionAnalyticsRepository crea una nueva instancia del repositorio. Con derivedEvents, cada
+// ejecución nueva escribe en el outbox, en la misma transacción, los eventos de dominio que genera.
+func NewPostgresExecutionAnalyticsRepository(db *gorm.DB, derivedEvents *aggregates.DerivedEventPolicy) repositories.ExecutionAnalyticsRepository {
+	return &PostgresExecutionAnalyticsRepository{db: db, derivedEvents: derivedEvents}
 }
 
 // Save guarda o actualiza un ExecutionAnalytics
-func (r *PostgresExecutionAnalyticsRepository) Save(ctx context.Context, execution *aggregates.ExecutionAnalytics) error {
-	model := r.toModel(execution)
+func (r *PostgresExecutionAnalyticsRepository) Save(ctx context.Context, execution *aggregates.ExecutionAnalytics) (repositories.SaveResult, error) {
+	return r.saveBatch(ctx, []*aggregates.ExecutionAnalytics{execution}, nil)
+}
+
+// batchInsertChunkSize limita las filas por sentencia INSERT para no exceder el máximo de parámetros de PostgreSQL
+const batchInsertChunkSize = 1000
+
+// SaveBatch guarda un lote de ExecutionAnalytics usando inserts multi-fila con ON CONFLICT DO NOTHING.
+// Las ejecuciones que ya existían se actualizan si la versión recibida es más reciente.
+func (r *PostgresExecutionAnalyticsRepository) SaveBatch(ctx context.Context, executions []*aggregates.ExecutionAnalytics) (repositories.SaveResult, error) {
+	if len(executions) == 0 {
+		return repositories.SaveResult{}, nil
+	}
+	return r.saveBatch(ctx, executions, nil)
+}
+
+// SaveBatchWithOffset guarda un lote de ExecutionAnalytics y el offset del consumidor en la misma transacción
+func (r *PostgresExecutionAnalyticsRepository) SaveBatchWithOffset(ctx context.Context, executions []*aggregates.ExecutionAnalytics, offset repositories.ConsumerOffset) (repositories.SaveResult, error) {
+	return r.saveBatch(ctx, executions, func(tx *gorm.DB) error {
+		return saveConsumerOffset(tx, offset)
+	})
+}
+
+// saveBatch inserta el lote, actualiza las ejecuciones existentes con versiones más recientes y, si se
+// indica, ejecuta afterInsert dentro de la misma transacción
+func (r *PostgresExecutionAnalyticsRepository) saveBatch(ctx context.Context, executions []*aggregates.ExecutionAnalytics, afterInsert func(tx *gorm.DB) error) (repositories.SaveResult, error) {
+
+	// Conservar una versión por ejecución dentro del lote: la más reciente
+	byExecutionID := make(map[string]*aggregates.ExecutionAnalytics, len(executions))
+	unique := make([]*aggregates.ExecutionAnalytics, 0, len(executions))
+	for _, execution := range executions {
+		key := execution.ExecutionID().Value()
+		if existing, exists := byExecutionID[key]; exists {
+			if execution.Supersedes(existing) {
+				byExecutionID[key] = execution
+			}
+			continue
+		}
+		byExecutionID[key] = execution
+		unique = append(unique, execution)
+	}
+	for i, execution := range unique {
+		unique[i] = byExecutionID[execution.ExecutionID().Value()]
+	}
+
+	var result repositories.SaveResult
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result = repositories.SaveResult{Stale: len(executions) - len(unique)}
+
+		var inserted []*aggregates.ExecutionAnalytics
+		for start := 0; start < len(unique); start += batchInsertChunkSize {
+			end := start + batchInsertChunkSize
+			if end > len(unique) {
+				end = len(unique)
+			}
 
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(&model).Error; err != nil {
+			chunk, err := r.insertChunk(tx, unique[start:end], byExecutionID)
+			if err != nil {
+				return err
+			}
+			inserted = append(inserted, chunk...)
+		}
+		result.Inserted = len(inserted)
+
+		// Las ejecuciones no insertadas ya existían: re-evaluaciones, cambios de estado o duplicados
+		isInserted := make(map[*aggregates.ExecutionAnalytics]bool, len(inserted))
+		for _, execution := range inserted {
+			isInserted[execution] = true
+		}
+		rejudged := make([]rejudgedExecution, 0)
+		for _, execution := range unique {
+			if isInserted[execution] {
+				continue
+			}
+			previous, err := r.applyUpdate(tx, execution)
+			if err != nil {
+				return fmt.Errorf("error updating execution %s: %w", execution.ExecutionID().Value(), err)
+			}
+			if previous != nil {
+				result.Updated++
+				rejudged = append(rejudged, rejudgedExecution{current: execution, previous: previous})
+			} else {
+				result.Stale++
+			}
+		}
+
+		// Las versiones descartadas dentro del lote quedan como stale
+		updated := make(map[*aggregates.ExecutionAnalytics]bool, len(rejudged))
+		for _, execution := range rejudged {
+			updated[execution.current] = true
+		}
+		applied := make(map[*aggregates.ExecutionAnalytics]bool, len(unique))
+		result.Outcomes = make([]repositories.SaveOutcome, len(executions))
+		for i, execution := range executions {
+			switch {
+			case applied[execution]:
+				result.Outcomes[i] = repositories.SaveOutcomeStale
+			case isInserted[execution]:
+				result.Outcomes[i] = repositories.SaveOutcomeInserted
+			case updated[execution]:
+				result.Outcomes[i] = repositories.SaveOutcomeUpdated
+			default:
+				result.Outcomes[i] = repositories.SaveOutcomeStale
+			}
+			applied[execution] = true
+		}
+
+		if err := r.saveDerivedEvents(tx, inserted, rejudged); err != nil {
 			return err
 		}
 
-		// Actualizar el ID del aggregate
-		execution.SetID(model.ID)
+		if afterInsert != nil {
+			return afterInsert(tx)
+		}
 		return nil
 	})
+	if err != nil {
+		return repositories.SaveResult{}, err
+	}
+
+	return result, nil
+}
+
+// insertChunk inserta un grupo de ejecuciones y sus test results, retornando las ejecuciones que eran nuevas
+func (r *PostgresExecutionAnalyticsRepository) insertChunk(tx *gorm.DB, executions []*aggregates.ExecutionAnalytics, byExecutionID map[string]*aggregates.ExecutionAnalytics) ([]*aggregates.ExecutionAnalytics, error) {
+	const columns = 26
+
+	var query strings.Builder
+	query.WriteString(`INSERT INTO execution_analytics (
+		event_id, execution_id, challenge_id, code_version_id, student_id,
+		language, language_family, language_version, language_raw, status, timestamp,
+		execution_time_ms, exit_code, total_tests, passed_tests, failed_tests, success, outcome,
+		score, earned_points, total_points, server_instance, revision, judged_at, created_at, updated_at
+	) VALUES `)
+
+	args := make([]interface{}, 0, len(executions)*columns)
+	for i, execution := range executions {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+
+		model := r.toModel(execution)
+		args = append(args,
+			model.EventID, model.ExecutionID, model.ChallengeID, model.CodeVersionID, model.StudentID,
+			model.Language, model.LanguageFamily, model.LanguageVersion, model.LanguageRaw,
+			model.Status, model.Timestamp, model.ExecutionTimeMs,
+			model.ExitCode, model.TotalTests, model.PassedTests, model.FailedTests,
+			model.Success, model.Outcome, model.Score, model.EarnedPoints, model.TotalPoints,
+			model.ServerInstance, model.Revision, model.JudgedAt, model.CreatedAt, model.UpdatedAt,
+		)
+	}
+	query.WriteString(" ON CONFLICT (execution_id) DO NOTHING RETURNING id, execution_id")
+
+	var rows []struct {
+		ID          uint
+		ExecutionID string
+	}
+	if err := tx.Raw(query.String(), args...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	// Solo las ejecuciones insertadas llevan sus test results
+	inserted := make([]*aggregates.ExecutionAnalytics, 0, len(rows))
+	testResults := make([]TestResultModel, 0)
+	for _, row := range rows {
+		execution := byExecutionID[row.ExecutionID]
+		execution.SetID(row.ID)
+		inserted = append(inserted, execution)
+		testResults = append(testResults, r.toTestResultModels(row.ID, execution)...)
+	}
+
+	if len(testResults) > 0 {
+		if err := tx.CreateInBatches(&testResults, batchInsertChunkSize).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return inserted, nil
+}
+
+// rejudgedExecution es una ejecución existente reemplazada por una versión más reciente
+type rejudgedExecution struct {
+	current  *aggregates.ExecutionAnalytics
+	previous *aggregates.ExecutionAnalytics
+}
+
+// applyUpdate reemplaza una ejecución existente si la versión recibida es más reciente. La fila se bloquea
+// hasta el fin de la transacción; la versión anterior se guarda en el historial y los test results se
+// reemplazan en la misma transacción. Retorna la versión reemplazada, o nil si la recibida no es más reciente.
+func (r *PostgresExecutionAnalyticsRepository) applyUpdate(tx *gorm.DB, execution *aggregates.ExecutionAnalytics) (*aggregates.ExecutionAnalytics, error) {
+	var model ExecutionAnalyticsModel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("execution_id = ?", execution.ExecutionID().Value()).
+		First(&model).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("execution_analytics_id = ?", model.ID).Order("id").Find(&model.TestResults).Error; err != nil {
+		return nil, err
+	}
+
+	stored, err := r.toDomain(&model)
+	if err != nil {
+		return nil, err
+	}
+	if !execution.Supersedes(stored) {
+		return nil, nil
+	}
+
+	history, err := r.toHistoryModel(stored, execution.EventID())
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return nil, fmt.Errorf("error saving execution history: %w", err)
+	}
+
+	updated := r.toModel(execution)
+	if err := tx.Model(&ExecutionAnalyticsModel{ID: model.ID}).Updates(map[string]interface{}{
+		"event_id":          updated.EventID,
+		"code_version_id":   updated.CodeVersionID,
+		"language":          updated.Language,
+		"language_family":   updated.LanguageFamily,
+		"language_version":  updated.LanguageVersion,
+		"language_raw":      updated.LanguageRaw,
+		"status":            updated.Status,
+		"timestamp":         updated.Timestamp,
+		"execution_time_ms": updated.ExecutionTimeMs,
+		"exit_code":         updated.ExitCode,
+		"total_tests":       updated.TotalTests,
+		"passed_tests":      updated.PassedTests,
+		"failed_tests":      updated.FailedTests,
+		"success":           updated.Success,
+		"outcome":           updated.Outcome,
+		"score":             updated.Score,
+		"earned_points":     updated.EarnedPoints,
+		"total_points":      updated.TotalPoints,
+		"server_instance":   updated.ServerInstance,
+		"revision":          updated.Revision,
+		"judged_at":         updated.JudgedAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("execution_analytics_id = ?", model.ID).Delete(&TestResultModel{}).Error; err != nil {
+		return nil, err
+	}
+	if testResults := r.toTestResultModels(model.ID, execution); len(testResults) > 0 {
+		if err := tx.CreateInBatches(&testResults, batchInsertChunkSize).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	execution.SetID(model.ID)
+	return stored, nil
+}
+
+// FindHistoryByExecutionID obtiene las versiones reemplazadas de una ejecución
+func (r *PostgresExecutionAnalyticsRepository) FindHistoryByExecutionID(ctx context.Context, executionID valueobjects.ExecutionID) ([]repositories.ExecutionRevision, error) {
+	var models []ExecutionAnalyticsHistoryModel
+	if err := r.db.WithContext(ctx).
+		Where("execution_id = ?", executionID.Value()).
+		Order("id").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	revisions := make([]repositories.ExecutionRevision, 0, len(models))
+	for _, model := range models {
+		var testResults []repositories.ExecutionRevisionTest
+		if err := json.Unmarshal(model.TestResults, &testResults); err != nil {
+			return nil, fmt.Errorf("error reading test results of execution history %d: %w", model.ID, err)
+		}
+		revisions = append(revisions, repositories.ExecutionRevision{
+			EventID:           model.EventID,
+			Revision:          model.Revision,
+			JudgedAt:          model.JudgedAt,
+			Status:            model.Status,
+			ExecutionTimeMs:   model.ExecutionTimeMs,
+			ExitCode:          model.ExitCode,
+			TotalTests:        model.TotalTests,
+			PassedTests:       model.PassedTests,
+			FailedTests:       model.FailedTests,
+			Success:           model.Success,
+			Outcome:           model.Outcome,
+			Score:             model.Score,
+			TestResults:       testResults,
+			ReplacedByEventID: model.ReplacedByEventID,
+			ReplacedAt:        model.ReplacedAt,
+		})
+	}
+	return revisions, nil
+}
+
+// saveDerivedEvents escribe en el outbox los eventos de dominio de las ejecuciones recién insertadas y de las
+// re-evaluaciones que cambiaron su resultado. Las ejecuciones insertadas se evalúan en orden de inserción para
+// que, dentro de un lote, solo la primera ejecución exitosa de un estudiante en un challenge cuente como
+// primera resolución.
+func (r *PostgresExecutionAnalyticsRepository) saveDerivedEvents(tx *gorm.DB, executions []*aggregates.ExecutionAnalytics, rejudged []rejudgedExecution) error {
+	if r.derivedEvents == nil || (len(executions) == 0 && len(rejudged) == 0) {
+		return nil
+	}
+
+	ordered := make([]*aggregates.ExecutionAnalytics, len(executions))
+	copy(ordered, executions)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ID() < ordered[j].ID() })
+
+	domainEvents := make([]*entities.DomainEvent, 0)
+	for _, execution := range ordered {
+		progress, err := r.studentProgress(tx, execution, false)
+		if err != nil {
+			return fmt.Errorf("error computing progress of student %s: %w", execution.StudentID().Value(), err)
+		}
+		domainEvents = append(domainEvents, execution.DerivedEvents(progress, *r.derivedEvents)...)
+	}
+
+	for _, execution := range rejudged {
+		if execution.current.Success() == execution.previous.Success() {
+			continue
+		}
+		progress, err := r.studentProgress(tx, execution.current, true)
+		if err != nil {
+			return fmt.Errorf("error computing progress of student %s: %w", execution.current.StudentID().Value(), err)
+		}
+		domainEvents = append(domainEvents, execution.current.RejudgedEvents(execution.previous, progress, *r.derivedEvents)...)
+	}
+
+	return saveOutboxEvents(tx, domainEvents)
+}
+
+// studentProgress calcula el avance del estudiante incluyendo la ejecución, ya guardada en la transacción.
+// Una ejecución insertada es primera resolución si no hay otra exitosa guardada antes; una re-evaluada, si no
+// hay ninguna otra exitosa, ya que las posteriores pudieron haber generado el evento.
+func (r *PostgresExecutionAnalyticsRepository) studentProgress(tx *gorm.DB, execution *aggregates.ExecutionAnalytics, rejudged bool) (aggregates.StudentProgress, error) {
+	studentID := execution.StudentID().Value()
+	var progress aggregates.StudentProgress
+
+	if execution.Success() {
+		otherSolves := "success = true AND id < ?"
+		if rejudged {
+			otherSolves = "success = true AND id <> ?"
+		}
+
+		var challenge struct {
+			Attempts      int
+			EarlierSolves int
+		}
+		if err := tx.Raw(`
+			SELECT
+				COUNT(*) FILTER (WHERE timestamp <= ?) AS attempts,
+				COUNT(*) FILTER (WHERE `+otherSolves+`) AS earlier_solves
+			FROM execution_analytics
+			WHERE student_id = ? AND challenge_id = ?`,
+			execution.Timestamp(), execution.ID(), studentID, execution.ChallengeID().Value(),
+		).Scan(&challenge).Error; err != nil {
+			return progress, err
+		}
+		progress.Attempts = challenge.Attempts
+		progress.FirstSolve = challenge.EarlierSolves == 0
+
+		if progress.FirstSolve {
+			query := `
+				SELECT COUNT(DISTINCT challenge_id)
+				FROM execution_analytics
+				WHERE student_id = ? AND success = true`
+			args := []interface{}{studentID}
+			if !rejudged {
+				query += " AND id <= ?"
+				args = append(args, execution.ID())
+			}
+			if err := tx.Raw(query, args...).Scan(&progress.SolvedChallenges).Error; err != nil {
+				return progress, err
+			}
+		}
+		return progress, nil
+	}
+
+	if rejudged {
+		// La ejecución dejó de ser exitosa: se revoca la resolución si no queda otra ejecución exitosa
+		var solves struct {
+			ChallengeSolves  int
+			SolvedChallenges int
+		}
+		if err := tx.Raw(`
+			SELECT
+				COUNT(*) FILTER (WHERE challenge_id = ?) AS challenge_solves,
+				COUNT(DISTINCT challenge_id) AS solved_challenges
+			FROM execution_analytics
+			WHERE student_id = ? AND success = true`,
+			execution.ChallengeID().Value(), studentID,
+		).Scan(&solves).Error; err != nil {
+			return progress, err
+		}
+		progress.SolveRevoked = solves.ChallengeSolves == 0
+		progress.SolvedChallenges = solves.SolvedChallenges
+	}
+
+	// Fallos desde la última ejecución exitosa anterior a esta
+	if err := tx.Raw(`
+		SELECT COUNT(*)
+		FROM execution_analytics
+		WHERE student_id = ? AND success = false AND timestamp <= ?
+			AND timestamp > COALESCE((
+				SELECT MAX(timestamp) FROM execution_analytics
+				WHERE student_id = ? AND success = true AND timestamp <= ?
+			), '-infinity'::timestamptz)`,
+		studentID, execution.Timestamp(), studentID, execution.Timestamp(),
+	).Scan(&progress.ConsecutiveFailures).Error; err != nil {
+		return progress, err
+	}
+	return progress, nil
 }
 
 // FindByExecutionID busca por ID de ejecución
@@ -149,6 +555,99 @@ func (r *PostgresExecutionAnalyticsRepository) GetSuccessRateByStudent(ctx conte
 	return result.SuccessRate, err
 }
 
+// GetOutcomeBreakdownByStudent cuenta las ejecuciones de un estudiante por resultado
+func (r *PostgresExecutionAnalyticsRepository) GetOutcomeBreakdownByStudent(ctx context.Context, studentID valueobjects.StudentID) (repositories.OutcomeBreakdown, error) {
+	var result repositories.OutcomeBreakdown
+
+	err := r.db.WithContext(ctx).
+		Model(&ExecutionAnalyticsModel{}).
+		Select(outcomeBreakdownColumns).
+		Where("student_id = ?", studentID.Value()).
+		Scan(&result).Error
+
+	return result, err
+}
+
+// GetOutcomeBreakdownByChallenge cuenta las ejecuciones de un challenge por resultado
+func (r *PostgresExecutionAnalyticsRepository) GetOutcomeBreakdownByChallenge(ctx context.Context, challengeID valueobjects.ChallengeID) (repositories.OutcomeBreakdown, error) {
+	var result repositories.OutcomeBreakdown
+
+	err := r.db.WithContext(ctx).
+		Model(&ExecutionAnalyticsModel{}).
+		Select(outcomeBreakdownColumns).
+		Where("challenge_id = ?", challengeID.Value()).
+		Scan(&result).Error
+
+	return result, err
+}
+
+// GetAverageScoreByStudent obtiene el puntaje ponderado promedio de un estudiante
+func (r *PostgresExecutionAnalyticsRepository) GetAverageScoreByStudent(ctx context.Context, studentID valueobjects.StudentID) (float64, error) {
+	var result struct {
+		AvgScore float64
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&ExecutionAnalyticsModel{}).
+		Select("COALESCE(AVG(score), 0) as avg_score").
+		Where("student_id = ?", studentID.Value()).
+		Scan(&result).Error
+
+	return result.AvgScore, err
+}
+
+// GetAverageScoreByChallenge obtiene el puntaje ponderado promedio de un challenge
+func (r *PostgresExecutionAnalyticsRepository) GetAverageScoreByChallenge(ctx context.Context, challengeID valueobjects.ChallengeID) (float64, error) {
+	var result struct {
+		AvgScore float64
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&ExecutionAnalyticsModel{}).
+		Select("COALESCE(AVG(score), 0) as avg_score").
+		Where("challenge_id = ?", challengeID.Value()).
+		Scan(&result).Error
+
+	return result.AvgScore, err
+}
+
+// GetTestStatsByChallenge obtiene las estadísticas de cada test de un challenge. Un test oculto cuenta en
+// failed_with_public_passed cuando falla en una ejecución que pasó todos sus tests públicos; las ejecuciones
+// sin tests públicos no cuentan. Los tests se ordenan por ese conteo y luego por menor tasa de aprobación.
+func (r *PostgresExecutionAnalyticsRepository) GetTestStatsByChallenge(ctx context.Context, challengeID valueobjects.ChallengeID) ([]repositories.TestStats, error) {
+	var results []repositories.TestStats
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH public_status AS (
+			SELECT test_results.execution_analytics_id, BOOL_AND(test_results.passed) as public_passed
+			FROM test_results
+			JOIN execution_analytics ON execution_analytics.id = test_results.execution_analytics_id
+			WHERE execution_analytics.challenge_id = ? AND test_results.visibility = ?
+			GROUP BY test_results.execution_analytics_id
+		)
+		SELECT
+			test_results.test_id,
+			MAX(test_results.test_name) as test_name,
+			MAX(test_results.visibility) as visibility,
+			MAX(test_results.weight) as weight,
+			COUNT(*) as executions,
+			AVG(CASE WHEN test_results.passed THEN 100.0 ELSE 0.0 END) as pass_rate,
+			AVG(test_results.duration_ms) as avg_duration_ms,
+			COUNT(*) FILTER (
+				WHERE test_results.visibility = ? AND NOT test_results.passed AND public_status.public_passed
+			) as failed_with_public_passed
+		FROM test_results
+		JOIN execution_analytics ON execution_analytics.id = test_results.execution_analytics_id
+		LEFT JOIN public_status ON public_status.execution_analytics_id = test_results.execution_analytics_id
+		WHERE execution_analytics.challenge_id = ?
+		GROUP BY test_results.test_id
+		ORDER BY failed_with_public_passed DESC, pass_rate ASC, test_name
+	`, challengeID.Value(), valueobjects.TestVisibilityPublic.Value(),
+		valueobjects.TestVisibilityHidden.Value(), challengeID.Value()).Scan(&results).Error
+
+	return results, err
+}
+
 // GetSuccessRateByChallenge obtiene tasa de éxito por challenge
 func (r *PostgresExecutionAnalyticsRepository) GetSuccessRateByChallenge(ctx context.Context, challengeID valueobjects.ChallengeID) (float64, error) {
 	var result struct {
@@ -180,7 +679,7 @@ func (r *PostgresExecutionAnalyticsRepository) GetAverageExecutionTimeByChalleng
 }
 
 // GetDailyExecutionStats obtiene estadísticas diarias de ejecuciones
-func (r *PostgresExecutionAnalyticsRepository) GetDailyExecutionStats(ctx context.Context, startDate, endDate time.Time) ([]repositories.DailyStats, error) {
+func (r *PostgresExecutionAnalyticsRepository) GetDailyExecutionStats(ctx context.Context, startDate, endDate time.Time, filter repositories.ChallengeFilter) ([]repositories.DailyStats, error) {
 	var results []repositories.DailyStats
 
 	err := r.db.WithContext(ctx).
@@ -190,8 +689,9 @@ func (r *PostgresExecutionAnalyticsRepository) GetDailyExecutionStats(ctx contex
 			COUNT(*) as total_executions,
 			SUM(CASE WHEN success = true THEN 1 ELSE 0 END) as successful_execs,
 			SUM(CASE WHEN success = false THEN 1 ELSE 0 END) as failed_execs,
-			AVG(execution_time_ms) as avg_exec_time
-		`).
+			AVG(execution_time_ms) as avg_exec_time,
+		`+outcomeBreakdownColumns).
+		Scopes(challengeFilterScope(filter)).
 		Where("timestamp BETWEEN ? AND ?", startDate, endDate).
 		Group("DATE(timestamp)").
 		Order("date DESC").
@@ -201,16 +701,18 @@ func (r *PostgresExecutionAnalyticsRepository) GetDailyExecutionStats(ctx contex
 }
 
 // GetLanguageUsageStats obtiene estadísticas de uso de lenguajes
-func (r *PostgresExecutionAnalyticsRepository) GetLanguageUsageStats(ctx context.Context, startDate, endDate time.Time) ([]repositories.LanguageStats, error) {
+func (r *PostgresExecutionAnalyticsRepository) GetLanguageUsageStats(ctx context.Context, startDate, endDate time.Time, filter repositories.ChallengeFilter) ([]repositories.LanguageStats, error) {
 	var results []repositories.LanguageStats
 
 	err := r.db.WithContext(ctx).
 		Model(&ExecutionAnalyticsModel{}).
 		Select(`
 			language,
+			MAX(language_family) as family,
 			COUNT(*) as total_executions,
-			AVG(CASE WHEN success = true THEN 100.0 ELSE 0.0 END) as success_rate
-		`).
+			AVG(CASE WHEN success = true THEN 100.0 ELSE 0.0 END) as success_rate,
+		`+outcomeBreakdownColumns).
+		Scopes(challengeFilterScope(filter)).
 		Where("timestamp BETWEEN ? AND ?", startDate, endDate).
 		Group("language").
 		Order("total_executions DESC").
@@ -219,38 +721,166 @@ func (r *PostgresExecutionAnalyticsRepository) GetLanguageUsageStats(ctx context
 	return results, err
 }
 
-// GetTopFailedChallenges obtiene los challenges con más fallos
-func (r *PostgresExecutionAnalyticsRepository) GetTopFailedChallenges(ctx context.Context, limit int) ([]repositories.ChallengeStats, error) {
-	var results []repositories.ChallengeStats
+// GetUnrecognizedLanguageStats obtiene los valores de lenguaje guardados como "other"
+func (r *PostgresExecutionAnalyticsRepository) GetUnrecognizedLanguageStats(ctx context.Context, startDate, endDate time.Time) ([]repositories.UnrecognizedLanguageStats, error) {
+	var results []repositories.UnrecognizedLanguageStats
+
+	err := r.db.WithContext(ctx).
+		Model(&ExecutionAnalyticsModel{}).
+		Select(`
+			language_raw as raw,
+			COUNT(*) as total_executions,
+			MAX(timestamp) as last_seen_at
+		`).
+		Where("language = ?", valueobjects.LanguageOther).
+		Where("timestamp BETWEEN ? AND ?", startDate, endDate).
+		Group("language_raw").
+		Order("total_executions DESC").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetDifficultyStats obtiene estadísticas por dificultad de challenge
+func (r *PostgresExecutionAnalyticsRepository) GetDifficultyStats(ctx context.Context, startDate, endDate time.Time, filter repositories.ChallengeFilter) ([]repositories.DifficultyStats, error) {
+	var results []repositories.DifficultyStats
 
 	query := r.db.WithContext(ctx).
 		Model(&ExecutionAnalyticsModel{}).
 		Select(`
-			challenge_id,
+			challenges.difficulty,
+			COUNT(DISTINCT execution_analytics.challenge_id) as challenges,
 			COUNT(*) as total_executions,
 			AVG(CASE WHEN success = true THEN 100.0 ELSE 0.0 END) as success_rate,
-			AVG(execution_time_ms) as avg_exec_time
-		`).
-		Group("challenge_id").
+			AVG(execution_time_ms) as avg_exec_time,
+		`+outcomeBreakdownColumns).
+		Joins("JOIN challenges ON challenges.challenge_id = execution_analytics.challenge_id").
+		Where("timestamp BETWEEN ? AND ?", startDate, endDate)
+
+	err := challengeConditions(query, filter).
+		Group("challenges.difficulty").
+		Order("challenges.difficulty").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetTopFailedChallenges obtiene los challenges con más fallos, con sus atributos del catálogo
+func (r *PostgresExecutionAnalyticsRepository) GetTopFailedChallenges(ctx context.Context, limit int, filter repositories.ChallengeFilter) ([]repositories.ChallengeStats, error) {
+	var rows []struct {
+		ChallengeID     string
+		Title           string
+		Difficulty      string
+		CourseID        string
+		Tags            []byte
+		Archived        bool
+		TotalExecutions int64
+		SuccessRate     float64
+		AvgExecTime     float64
+		repositories.OutcomeBreakdown
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&ExecutionAnalyticsModel{}).
+		Select(`
+			execution_analytics.challenge_id,
+			COALESCE(challenges.title, '') as title,
+			COALESCE(challenges.difficulty, '') as difficulty,
+			COALESCE(challenges.course_id, '') as course_id,
+			COALESCE(challenges.tags, '[]') as tags,
+			COALESCE(challenges.archived, false) as archived,
+			COUNT(*) as total_executions,
+			AVG(CASE WHEN success = true THEN 100.0 ELSE 0.0 END) as success_rate,
+			AVG(execution_time_ms) as avg_exec_time,
+		` + outcomeBreakdownColumns).
+		Joins("LEFT JOIN challenges ON challenges.challenge_id = execution_analytics.challenge_id")
+
+	query = challengeConditions(query, filter).
+		Group("execution_analytics.challenge_id, challenges.challenge_id").
 		Order("success_rate ASC, total_executions DESC")
 
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
 
-	err := query.Scan(&results).Error
-	return results, err
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]repositories.ChallengeStats, 0, len(rows))
+	for _, row := range rows {
+		var tags []string
+		if err := json.Unmarshal(row.Tags, &tags); err != nil {
+			return nil, fmt.Errorf("error reading tags of challenge %s: %w", row.ChallengeID, err)
+		}
+		results = append(results, repositories.ChallengeStats{
+			ChallengeID:      row.ChallengeID,
+			Title:            row.Title,
+			Difficulty:       row.Difficulty,
+			CourseID:         row.CourseID,
+			Tags:             tags,
+			Archived:         row.Archived,
+			TotalExecutions:  row.TotalExecutions,
+			SuccessRate:      row.SuccessRate,
+			AvgExecTime:      row.AvgExecTime,
+			OutcomeBreakdown: row.OutcomeBreakdown,
+		})
+	}
+	return results, nil
+}
+
+// outcomeBreakdownColumns cuenta las ejecuciones de cada resultado en una columna con el nombre del resultado,
+// que se escanea en repositories.OutcomeBreakdown
+var outcomeBreakdownColumns = func() string {
+	columns := make([]string, 0, len(valueobjects.ExecutionOutcomes()))
+	for _, outcome := range valueobjects.ExecutionOutcomes() {
+		columns = append(columns, fmt.Sprintf("COUNT(*) FILTER (WHERE execution_analytics.outcome = '%s') as %s", outcome, outcome))
+	}
+	return strings.Join(columns, ", ")
+}()
+
+// challengeFilterScope une las ejecuciones con el catálogo de challenges y aplica el filtro.
+// Sin filtro no modifica la consulta, por lo que incluye los challenges que aún no están en el catálogo.
+func challengeFilterScope(filter repositories.ChallengeFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.IsEmpty() {
+			return db
+		}
+		return challengeConditions(db.Joins("JOIN challenges ON challenges.challenge_id = execution_analytics.challenge_id"), filter)
+	}
+}
+
+// challengeConditions agrega las condiciones del filtro a una consulta unida con la tabla challenges
+func challengeConditions(db *gorm.DB, filter repositories.ChallengeFilter) *gorm.DB {
+	if filter.Difficulty != "" {
+		db = db.Where("challenges.difficulty = ?", strings.ToLower(filter.Difficulty))
+	}
+	if filter.CourseID != "" {
+		db = db.Where("challenges.course_id = ?", filter.CourseID)
+	}
+	if filter.Tag != "" {
+		tag, _ := json.Marshal([]string{strings.ToLower(filter.Tag)})
+		db = db.Where("challenges.tags @> ?::jsonb", string(tag))
+	}
+	if filter.Archived != nil {
+		db = db.Where("challenges.archived = ?", *filter.Archived)
+	}
+	return db
 }
 
 // toModel convierte del dominio a modelo de persistencia
 func (r *PostgresExecutionAnalyticsRepository) toModel(execution *aggregates.ExecutionAnalytics) ExecutionAnalyticsModel {
 	model := ExecutionAnalyticsModel{
 		ID:              execution.ID(),
+		EventID:         execution.EventID(),
 		ExecutionID:     execution.ExecutionID().Value(),
 		ChallengeID:     execution.ChallengeID().Value(),
 		CodeVersionID:   execution.CodeVersionID(),
 		StudentID:       execution.StudentID().Value(),
 		Language:        execution.Language().Value(),
+		LanguageFamily:  execution.Language().Family(),
+		LanguageVersion: execution.Language().Version(),
+		LanguageRaw:     execution.Language().Raw(),
 		Status:          execution.Status().Value(),
 		Timestamp:       execution.Timestamp(),
 		ExecutionTimeMs: execution.ExecutionTimeMs(),
@@ -259,22 +889,85 @@ func (r *PostgresExecutionAnalyticsRepository) toModel(execution *aggregates.Exe
 		PassedTests:     execution.PassedTests(),
 		FailedTests:     execution.FailedTests(),
 		Success:         execution.Success(),
+		Outcome:         execution.Outcome().Value(),
 		ServerInstance:  execution.ServerInstance(),
+		Revision:        execution.Revision(),
 		CreatedAt:       execution.CreatedAt(),
 		UpdatedAt:       execution.UpdatedAt(),
-		TestResults:     make([]TestResultModel, 0),
+		TestResults:     r.toTestResultModels(execution.ID(), execution),
 	}
+	judgedAt := execution.JudgedAt()
+	model.JudgedAt = &judgedAt
+	score := execution.WeightedScore()
+	model.Score = &score
+	model.EarnedPoints, model.TotalPoints = execution.Points()
+
+	return model
+}
 
+// toTestResultModels convierte los test results de una ejecución guardada con el ID indicado
+func (r *PostgresExecutionAnalyticsRepository) toTestResultModels(executionAnalyticsID uint, execution *aggregates.ExecutionAnalytics) []TestResultModel {
+	models := make([]TestResultModel, 0, len(execution.TestResults()))
 	for _, testResult := range execution.TestResults() {
-		model.TestResults = append(model.TestResults, TestResultModel{
-			TestID:       testResult.TestID().Value(),
-			TestName:     testResult.TestName(),
-			Passed:       testResult.Passed(),
-			ErrorMessage: testResult.ErrorMessage(),
+		models = append(models, TestResultModel{
+			ExecutionAnalyticsID: executionAnalyticsID,
+			TestID:               testResult.TestID().Value(),
+			TestName:             testResult.TestName(),
+			Passed:               testResult.Passed(),
+			ErrorMessage:         testResult.ErrorMessage(),
+			DurationMs:           testResult.DurationMs(),
+			Visibility:           testResult.Visibility().Value(),
+			Weight:               testResult.Weight(),
+			ExpectedOutput:       testResult.ExpectedOutput(),
+			ActualOutput:         testResult.ActualOutput(),
+			OutputTruncated:      testResult.OutputTruncated(),
 		})
 	}
+	return models
+}
 
-	return model
+// toHistoryModel copia una versión guardada de la ejecución al historial
+func (r *PostgresExecutionAnalyticsRepository) toHistoryModel(execution *aggregates.ExecutionAnalytics, replacedByEventID string) (ExecutionAnalyticsHistoryModel, error) {
+	testResults := make([]repositories.ExecutionRevisionTest, 0, len(execution.TestResults()))
+	for _, testResult := range execution.TestResults() {
+		weight := testResult.Weight()
+		testResults = append(testResults, repositories.ExecutionRevisionTest{
+			TestID:          testResult.TestID().Value(),
+			TestName:        testResult.TestName(),
+			Passed:          testResult.Passed(),
+			ErrorMessage:    testResult.ErrorMessage(),
+			DurationMs:      testResult.DurationMs(),
+			Visibility:      testResult.Visibility().Value(),
+			Weight:          &weight,
+			ExpectedOutput:  testResult.ExpectedOutput(),
+			ActualOutput:    testResult.ActualOutput(),
+			OutputTruncated: testResult.OutputTruncated(),
+		})
+	}
+	payload, err := json.Marshal(testResults)
+	if err != nil {
+		return ExecutionAnalyticsHistoryModel{}, fmt.Errorf("error marshaling test results: %w", err)
+	}
+	score := execution.WeightedScore()
+
+	return ExecutionAnalyticsHistoryModel{
+		ExecutionAnalyticsID: execution.ID(),
+		ExecutionID:          execution.ExecutionID().Value(),
+		EventID:              execution.EventID(),
+		Revision:             execution.Revision(),
+		JudgedAt:             execution.JudgedAt(),
+		Status:               execution.Status().Value(),
+		ExecutionTimeMs:      execution.ExecutionTimeMs(),
+		ExitCode:             execution.ExitCode(),
+		TotalTests:           execution.TotalTests(),
+		PassedTests:          execution.PassedTests(),
+		FailedTests:          execution.FailedTests(),
+		Success:              execution.Success(),
+		Outcome:              execution.Outcome().Value(),
+		Score:                &score,
+		TestResults:          payload,
+		ReplacedByEventID:    replacedByEventID,
+	}, nil
 }
 
 // toDomain convierte del modelo de persistencia al dominio
@@ -294,10 +987,7 @@ func (r *PostgresExecutionAnalyticsRepository) toDomain(model *ExecutionAnalytic
 		return nil, err
 	}
 
-	language, err := valueobjects.NewProgrammingLanguage(model.Language)
-	if err != nil {
-		return nil, err
-	}
+	language := valueobjects.RestoreProgrammingLanguage(model.Language, model.LanguageFamily, model.LanguageVersion, model.LanguageRaw)
 
 	status, err := valueobjects.NewExecutionStatus(model.Status)
 	if err != nil {
@@ -325,6 +1015,21 @@ func (r *PostgresExecutionAnalyticsRepository) toDomain(model *ExecutionAnalytic
 	}
 
 	execution.SetID(model.ID)
+	execution.SetEventID(model.EventID)
+	if model.Outcome != "" {
+		outcome, err := valueobjects.NewExecutionOutcome(model.Outcome)
+		if err != nil {
+			return nil, err
+		}
+		if err := execution.SetOutcome(outcome); err != nil {
+			return nil, err
+		}
+	}
+	if model.JudgedAt != nil {
+		execution.SetVersion(model.Revision, *model.JudgedAt)
+	} else {
+		execution.SetVersion(model.Revision, model.Timestamp)
+	}
 	execution.SetCreatedAt(model.CreatedAt)
 	execution.SetUpdatedAt(model.UpdatedAt)
 
@@ -336,12 +1041,27 @@ func (r *PostgresExecutionAnalyticsRepository) toDomain(model *ExecutionAnalytic
 			return nil, err
 		}
 
-		testResults = append(testResults, entities.NewTestResult(
+		testResult := entities.NewTestResult(
 			testID,
 			tr.TestName,
 			tr.Passed,
 			tr.ErrorMessage,
-		))
+		)
+		if tr.DurationMs != nil {
+			if err := testResult.SetDuration(*tr.DurationMs); err != nil {
+				return nil, err
+			}
+		}
+		visibility, err := valueobjects.NewTestVisibility(tr.Visibility)
+		if err != nil {
+			return nil, err
+		}
+		testResult.SetVisibility(visibility)
+		if err := testResult.SetWeight(tr.Weight); err != nil {
+			return nil, err
+		}
+		testResult.RestoreOutputs(tr.ExpectedOutput, tr.ActualOutput, tr.OutputTruncated)
+		testResults = append(testResults, testResult)
 	}
 	execution.SetTestResults(testResults)
 
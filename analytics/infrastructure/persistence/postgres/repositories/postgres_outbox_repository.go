@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// outboxLockID es la clave del advisory lock que serializa la publicación del outbox entre instancias
+const outboxLockID = 4206190001
+
+// PostgresOutboxRepository implementa el outbox de eventos de dominio usando PostgreSQL
+type PostgresOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresOutboxRepository crea una nueva instancia del repositorio
+func NewPostgresOutboxRepository(db *gorm.DB) repositories.OutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+// PublishPending publica los eventos pendientes en orden mientras tiene el advisory lock del outbox.
+// Si la transacción falla después de publicar, los eventos se vuelven a publicar en la siguiente pasada
+// (entrega al menos una vez; los consumidores descartan duplicados por el ID del evento).
+// Un evento que llega a maxAttempts intentos se aparta con failed_at para que no bloquee a los siguientes.
+func (r *PostgresOutboxRepository) PublishPending(ctx context.Context, limit int, maxAttempts int, publish func(event *repositories.OutboxEvent) error) (repositories.OutboxPublishResult, error) {
+	var published []uint
+	var failed int
+	var publishErr error
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxLockID).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		var models []OutboxEventModel
+		if err := tx.Where("published_at IS NULL AND failed_at IS NULL").Order("id").Limit(limit).Find(&models).Error; err != nil {
+			return err
+		}
+
+		for i := range models {
+			if err := publish(r.toOutboxEvent(&models[i])); err != nil {
+				updates := map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": err.Error(),
+				}
+				exhausted := maxAttempts > 0 && models[i].Attempts+1 >= maxAttempts
+				if exhausted {
+					updates["failed_at"] = time.Now()
+				}
+				if err := tx.Model(&models[i]).Updates(updates).Error; err != nil {
+					return err
+				}
+				if exhausted {
+					failed++
+					continue
+				}
+				publishErr = err
+				break
+			}
+			published = append(published, models[i].ID)
+		}
+
+		if len(published) == 0 {
+			return nil
+		}
+		return tx.Model(&OutboxEventModel{}).
+			Where("id IN ?", published).
+			Updates(map[string]interface{}{"published_at": time.Now(), "last_error": ""}).Error
+	})
+	if err != nil {
+		return repositories.OutboxPublishResult{}, err
+	}
+
+	return repositories.OutboxPublishResult{Published: len(published), Failed: failed}, publishErr
+}
+
+// GetStats obtiene la cantidad de eventos pendientes y apartados como fallidos, y el más antiguo de cada grupo
+func (r *PostgresOutboxRepository) GetStats(ctx context.Context) (*repositories.OutboxStats, error) {
+	var rows []struct {
+		Failed   bool
+		Count    int64
+		OldestAt *time.Time
+	}
+	err := r.db.WithContext(ctx).
+		Model(&OutboxEventModel{}).
+		Select("failed_at IS NOT NULL AS failed, COUNT(*) AS count, MIN(created_at) AS oldest_at").
+		Where("published_at IS NULL").
+		Group("failed_at IS NOT NULL").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error getting outbox stats: %w", err)
+	}
+
+	stats := &repositories.OutboxStats{}
+	for _, row := range rows {
+		if row.Failed {
+			stats.Failed = row.Count
+			stats.OldestFailedAt = row.OldestAt
+		} else {
+			stats.Pending = row.Count
+			stats.OldestPendingAt = row.OldestAt
+		}
+	}
+	return stats, nil
+}
+
+// DeletePublishedBefore elimina los eventos publicados antes de la fecha indicada
+func (r *PostgresOutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", before).
+		Delete(&OutboxEventModel{})
+	return result.RowsAffected, result.Error
+}
+
+// toOutboxEvent convierte del modelo de persistencia al evento del outbox
+func (r *PostgresOutboxRepository) toOutboxEvent(model *OutboxEventModel) *repositories.OutboxEvent {
+	return &repositories.OutboxEvent{
+		ID:          model.ID,
+		EventID:     model.EventID,
+		Type:        model.Type,
+		Key:         model.MessageKey,
+		Payload:     model.Payload,
+		OccurredAt:  model.OccurredAt,
+		CreatedAt:   model.CreatedAt,
+		PublishedAt: model.PublishedAt,
+		Attempts:    model.Attempts,
+		LastError:   model.LastError,
+		FailedAt:    model.FailedAt,
+	}
+}
+
+// saveOutboxEvents escribe eventos de dominio en el outbox. Recibe la transacción de quien guarda los datos
+// que los originan para que ambos se confirmen juntos.
+func saveOutboxEvents(tx *gorm.DB, domainEvents []*entities.DomainEvent) error {
+	if len(domainEvents) == 0 {
+		return nil
+	}
+
+	models := make([]OutboxEventModel, 0, len(domainEvents))
+	for _, event := range domainEvents {
+		payload, err := json.Marshal(event.Data())
+		if err != nil {
+			return fmt.Errorf("error marshaling domain event %s: %w", event.Type(), err)
+		}
+		models = append(models, OutboxEventModel{
+			EventID:    event.ID(),
+			Type:       event.Type().Value(),
+			MessageKey: event.Key(),
+			Payload:    payload,
+			OccurredAt: event.OccurredAt(),
+		})
+	}
+
+	return tx.CreateInBatches(&models, batchInsertChunkSize).Error
+}
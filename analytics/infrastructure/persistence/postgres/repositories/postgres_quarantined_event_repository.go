@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// PostgresQuarantinedEventRepository implementa el repositorio de eventos en cuarentena usando PostgreSQL
+type PostgresQuarantinedEventRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresQuarantinedEventRepository crea una nueva instancia del repositorio
+func NewPostgresQuarantinedEventRepository(db *gorm.DB) repositories.QuarantinedEventRepository {
+	return &PostgresQuarantinedEventRepository{db: db}
+}
+
+// Save guarda un evento que no pudo ser procesado
+func (r *PostgresQuarantinedEventRepository) Save(ctx context.Context, event *repositories.QuarantinedEvent) error {
+	model := QuarantinedEventModel{
+		Topic:           event.Topic,
+		Partition:       event.Partition,
+		Offset:          event.Offset,
+		MessageKey:      event.Key,
+		Payload:         event.Payload,
+		Error:           event.Error,
+		Attempts:        event.Attempts,
+		DeadLetterTopic: event.DeadLetterTopic,
+		QuarantinedAt:   event.QuarantinedAt,
+	}
+
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return err
+	}
+
+	event.ID = model.ID
+	return nil
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reconciliationBatchSize es la cantidad de filas por INSERT al guardar diferencias e IDs leídos
+const reconciliationBatchSize = 500
+
+// PostgresReconciliationRepository implementa el repositorio de reportes de reconciliación usando PostgreSQL
+type PostgresReconciliationRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresReconciliationRepository crea una nueva instancia del repositorio
+func NewPostgresReconciliationRepository(db *gorm.DB) repositories.ReconciliationRepository {
+	return &PostgresReconciliationRepository{db: db}
+}
+
+// SaveItems guarda las diferencias encontradas por un job
+func (r *PostgresReconciliationRepository) SaveItems(ctx context.Context, jobID string, items []*entities.ReconciliationItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	models := make([]SyncReconciliationItemModel, 0, len(items))
+	for _, item := range items {
+		models = append(models, SyncReconciliationItemModel{
+			SyncJobID: jobID,
+			Kind:      item.Kind().Value(),
+			Key:       item.Key(),
+			Partition: item.Partition(),
+			Offset:    item.Offset(),
+			Reason:    item.Reason(),
+		})
+	}
+
+	// Un job retomado puede volver a leer mensajes ya reportados
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&models, reconciliationBatchSize).Error
+}
+
+// SaveSeenKeys registra los IDs de negocio de los eventos leídos del tópico por un job
+func (r *PostgresReconciliationRepository) SaveSeenKeys(ctx context.Context, jobID string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	models := make([]SyncSeenKeyModel, 0, len(keys))
+	for _, key := range keys {
+		models = append(models, SyncSeenKeyModel{SyncJobID: jobID, Key: key})
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&models, reconciliationBatchSize).Error
+}
+
+// RecordOrphans guarda como diferencias los registros del target cuyo ID no fue leído del tópico
+func (r *PostgresReconciliationRepository) RecordOrphans(ctx context.Context, jobID string, target valueobjects.SyncTarget) (int64, error) {
+	table, column, err := reconciliationSource(target)
+	if err != nil {
+		return 0, err
+	}
+
+	var orphaned int64
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Los registros sin evento no tienen posición en el tópico: se recalculan completos si el paso se repite
+		if err := tx.Where("sync_job_id = ? AND kind = ?", jobID, valueobjects.ReconciliationOrphaned.Value()).
+			Delete(&SyncReconciliationItemModel{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(fmt.Sprintf(`
+			INSERT INTO sync_reconciliation_items (sync_job_id, kind, key, reason, created_at)
+			SELECT ?, ?, t.%[2]s::text, ?, NOW()
+			FROM %[1]s t
+			WHERE NOT EXISTS (
+				SELECT 1 FROM sync_seen_keys k
+				WHERE k.sync_job_id = ? AND k.key = t.%[2]s::text
+			)`, table, column),
+			jobID, valueobjects.ReconciliationOrphaned.Value(), "no event found in topic", jobID,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		orphaned = result.RowsAffected
+		return nil
+	})
+
+	return orphaned, err
+}
+
+// DeleteSeenKeys elimina los IDs leídos por un job
+func (r *PostgresReconciliationRepository) DeleteSeenKeys(ctx context.Context, jobID string) error {
+	return r.db.WithContext(ctx).
+		Where("sync_job_id = ?", jobID).
+		Delete(&SyncSeenKeyModel{}).Error
+}
+
+// FindItems busca las diferencias de un job, opcionalmente filtradas por tipo
+func (r *PostgresReconciliationRepository) FindItems(ctx context.Context, jobID string, kind *valueobjects.ReconciliationKind, limit, offset int) ([]*entities.ReconciliationItem, error) {
+	query := r.db.WithContext(ctx).Where("sync_job_id = ?", jobID)
+	if kind != nil {
+		query = query.Where("kind = ?", kind.Value())
+	}
+
+	var models []SyncReconciliationItemModel
+	if err := query.
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.ReconciliationItem, 0, len(models))
+	for _, model := range models {
+		kind, err := valueobjects.NewReconciliationKind(model.Kind)
+		if err != nil {
+			continue
+		}
+		result = append(result, entities.RestoreReconciliationItem(kind, model.Key, model.Partition, model.Offset, model.Reason))
+	}
+
+	return result, nil
+}
+
+// reconciliationSource retorna la tabla y la columna con el ID de negocio de cada target
+func reconciliationSource(target valueobjects.SyncTarget) (string, string, error) {
+	switch target {
+	case valueobjects.SyncTargetExecutionAnalytics:
+		return ExecutionAnalyticsModel{}.TableName(), "execution_id", nil
+	case valueobjects.SyncTargetUserRegistration:
+		return UserRegistrationAnalyticsModel{}.TableName(), "user_id", nil
+	case valueobjects.SyncTargetChallenge:
+		return ChallengeModel{}.TableName(), "challenge_id", nil
+	case valueobjects.SyncTargetCodeVersion:
+		return CodeVersionModel{}.TableName(), "code_version_id", nil
+	default:
+		return "", "", fmt.Errorf("unsupported sync target: %s", target)
+	}
+}
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/entities"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// uniqueViolation es el SQLSTATE de PostgreSQL para violaciones de índices únicos
+const uniqueViolation = "23505"
+
+// PostgresSyncJobRepository implementa el repositorio de jobs de sincronización usando PostgreSQL
+type PostgresSyncJobRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresSyncJobRepository crea una nueva instancia del repositorio
+func NewPostgresSyncJobRepository(db *gorm.DB) repositories.SyncJobRepository {
+	return &PostgresSyncJobRepository{db: db}
+}
+
+// Create guarda un nuevo job
+func (r *PostgresSyncJobRepository) Create(ctx context.Context, job *aggregates.SyncJob) error {
+	model := r.toModel(job)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Checkpoints").Create(&model).Error; err != nil {
+			return translateSyncJobError(err)
+		}
+		return r.saveCheckpoints(tx, model.Checkpoints)
+	})
+}
+
+// Save actualiza el estado, los contadores y los checkpoints de un job
+func (r *PostgresSyncJobRepository) Save(ctx context.Context, job *aggregates.SyncJob) error {
+	model := r.toModel(job)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Checkpoints").Save(&model).Error; err != nil {
+			return translateSyncJobError(err)
+		}
+		return r.saveCheckpoints(tx, model.Checkpoints)
+	})
+}
+
+// saveCheckpoints inserta o actualiza los checkpoints de un job
+func (r *PostgresSyncJobRepository) saveCheckpoints(tx *gorm.DB, checkpoints []SyncCheckpointModel) error {
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "sync_job_id"}, {Name: "partition"}},
+		DoUpdates: clause.AssignmentColumns([]string{"next_offset", "end_offset", "incomplete", "updated_at"}),
+	}).Create(&checkpoints).Error
+}
+
+// FindByID busca un job por ID
+func (r *PostgresSyncJobRepository) FindByID(ctx context.Context, id string) (*aggregates.SyncJob, error) {
+	var model SyncJobModel
+	err := r.db.WithContext(ctx).
+		Preload("Checkpoints", func(db *gorm.DB) *gorm.DB {
+			return db.Order("partition ASC")
+		}).
+		Where("id = ?", id).
+		First(&model).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model)
+}
+
+// FindActive busca los jobs pendientes o en ejecución
+func (r *PostgresSyncJobRepository) FindActive(ctx context.Context) ([]*aggregates.SyncJob, error) {
+	var models []SyncJobModel
+	err := r.db.WithContext(ctx).
+		Preload("Checkpoints", func(db *gorm.DB) *gorm.DB {
+			return db.Order("partition ASC")
+		}).
+		Where("status IN ?", []string{valueobjects.SyncJobPending.Value(), valueobjects.SyncJobRunning.Value()}).
+		Order("created_at ASC").
+		Find(&models).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(models)
+}
+
+// FindRecent busca los últimos jobs creados
+func (r *PostgresSyncJobRepository) FindRecent(ctx context.Context, limit int) ([]*aggregates.SyncJob, error) {
+	var models []SyncJobModel
+	err := r.db.WithContext(ctx).
+		Preload("Checkpoints", func(db *gorm.DB) *gorm.DB {
+			return db.Order("partition ASC")
+		}).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&models).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(models)
+}
+
+// translateSyncJobError convierte la violación del índice de jobs activos en ErrSyncJobAlreadyActive
+func translateSyncJobError(err error) error {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) && pgErr.SQLState() == uniqueViolation {
+		return repositories.ErrSyncJobAlreadyActive
+	}
+	return err
+}
+
+// toModel convierte un aggregate a modelo GORM
+func (r *PostgresSyncJobRepository) toModel(job *aggregates.SyncJob) SyncJobModel {
+	model := SyncJobModel{
+		ID:           job.ID(),
+		Target:       job.Target().Value(),
+		Topic:        job.Topic(),
+		FromTime:     job.From(),
+		ToTime:       job.To(),
+		DryRun:       job.DryRun(),
+		Status:       job.Status().Value(),
+		Synced:       job.Synced(),
+		Skipped:      job.Skipped(),
+		Invalid:      job.Invalid(),
+		Missing:      job.Missing(),
+		Orphaned:     job.Orphaned(),
+		ErrorMessage: job.ErrorMessage(),
+		CreatedAt:    job.CreatedAt(),
+		StartedAt:    job.StartedAt(),
+		FinishedAt:   job.FinishedAt(),
+		UpdatedAt:    job.UpdatedAt(),
+		Checkpoints:  make([]SyncCheckpointModel, 0, len(job.Checkpoints())),
+	}
+
+	if job.Status().IsActive() {
+		activeTarget := job.Target().Value()
+		model.ActiveTarget = &activeTarget
+	}
+
+	for _, checkpoint := range job.Checkpoints() {
+		model.Checkpoints = append(model.Checkpoints, SyncCheckpointModel{
+			SyncJobID:   job.ID(),
+			Partition:   checkpoint.Partition(),
+			StartOffset: checkpoint.StartOffset(),
+			NextOffset:  checkpoint.NextOffset(),
+			EndOffset:   checkpoint.EndOffset(),
+			Incomplete:  checkpoint.IsIncomplete(),
+		})
+	}
+
+	return model
+}
+
+// toDomain convierte un modelo GORM a aggregate
+func (r *PostgresSyncJobRepository) toDomain(model *SyncJobModel) (*aggregates.SyncJob, error) {
+	target, err := valueobjects.NewSyncTarget(model.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := valueobjects.NewSyncJobStatus(model.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]*entities.SyncCheckpoint, 0, len(model.Checkpoints))
+	for _, checkpoint := range model.Checkpoints {
+		checkpoints = append(checkpoints, entities.RestoreSyncCheckpoint(
+			checkpoint.Partition,
+			checkpoint.StartOffset,
+			checkpoint.NextOffset,
+			checkpoint.EndOffset,
+			checkpoint.Incomplete,
+		))
+	}
+
+	return aggregates.RestoreSyncJob(
+		model.ID,
+		target,
+		model.Topic,
+		model.FromTime,
+		model.ToTime,
+		model.DryRun,
+		status,
+		checkpoints,
+		model.Synced,
+		model.Skipped,
+		model.Invalid,
+		model.Missing,
+		model.Orphaned,
+		model.ErrorMessage,
+		model.CreatedAt,
+		model.StartedAt,
+		model.FinishedAt,
+		model.UpdatedAt,
+	), nil
+}
+
+// toDomainList convierte una lista de modelos GORM a aggregates
+func (r *PostgresSyncJobRepository) toDomainList(models []SyncJobModel) ([]*aggregates.SyncJob, error) {
+	result := make([]*aggregates.SyncJob, 0, len(models))
+
+	for _, model := range models {
+		domain, err := r.toDomain(&model)
+		if err != nil {
+			continue
+		}
+		result = append(result, domain)
+	}
+
+	return result, nil
+}
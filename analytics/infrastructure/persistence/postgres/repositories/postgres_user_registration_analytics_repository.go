@@ -159,6 +159,7 @@ func (r *PostgresUserRegistrationAnalyticsRepository) GetTopEmailDomains(ctx con
 func (r *PostgresUserRegistrationAnalyticsRepository) toModel(userReg *aggregates.UserRegistrationAnalytics) *UserRegistrationAnalyticsModel {
 	return &UserRegistrationAnalyticsModel{
 		ID:           userReg.ID(),
+		EventID:      userReg.EventID(),
 		UserID:       userReg.UserID().Value(),
 		ProfileID:    userReg.ProfileID().Value(),
 		Username:     userReg.Username(),
@@ -193,6 +194,7 @@ func (r *PostgresUserRegistrationAnalyticsRepository) toDomain(model *UserRegist
 	}
 
 	userReg.SetID(model.ID)
+	userReg.SetEventID(model.EventID)
 	userReg.SetCreatedAt(model.CreatedAt)
 	userReg.SetUpdatedAt(model.UpdatedAt)
 
@@ -27,6 +27,7 @@ func (c *AnalyticsController) RegisterRoutes(router *gin.RouterGroup) {
 	analytics := router.Group("/analytics")
 	{
 		analytics.GET("/execution/:executionId", c.GetByExecutionID)
+		analytics.GET("/execution/:executionId/history", c.GetExecutionHistory)
 		analytics.GET("/student/:studentId", c.GetByStudentID)
 		analytics.GET("/challenge/:challengeId", c.GetByChallengeID)
 		analytics.GET("/date-range", c.GetByDateRange)
@@ -35,8 +36,11 @@ func (c *AnalyticsController) RegisterRoutes(router *gin.RouterGroup) {
 		{
 			kpi.GET("/student/:studentId", c.GetStudentKPI)
 			kpi.GET("/challenge/:challengeId", c.GetChallengeKPI)
+			kpi.GET("/challenge/:challengeId/tests", c.GetChallengeTestKPI)
 			kpi.GET("/daily", c.GetDailyKPI)
 			kpi.GET("/languages", c.GetLanguageKPI)
+			kpi.GET("/languages/unrecognized", c.GetUnrecognizedLanguageKPI)
+			kpi.GET("/difficulties", c.GetDifficultyKPI)
 			kpi.GET("/top-failed-challenges", c.GetTopFailedChallenges)
 		}
 	}
@@ -51,7 +55,7 @@ type ErrorResponse struct {
 
 // GetByExecutionID obtiene analytics por ID de ejecución - SIN DTOs, retorna aggregate directo
 // @Summary Obtener analytics por ID de ejecución
-// @Description Obtiene el análisis completo de una ejecución específica por su ID
+// @Description Obtiene el análisis completo de una ejecución específica por su ID. Las salidas esperada y obtenida de los tests ocultos no se incluyen (output_redacted)
 // @Tags Analytics
 // @Accept json
 // @Produce json
@@ -82,14 +86,20 @@ func (c *AnalyticsController) GetByExecutionID(ctx *gin.Context) {
 		return
 	}
 
+	earnedPoints, totalPoints := execution.Points()
+
 	// Transformación inline - NO DTO
 	ctx.JSON(http.StatusOK, gin.H{
 		"id":                execution.ID(),
+		"event_id":          execution.EventID(),
 		"execution_id":      execution.ExecutionID().Value(),
 		"challenge_id":      execution.ChallengeID().Value(),
 		"code_version_id":   execution.CodeVersionID(),
 		"student_id":        execution.StudentID().Value(),
 		"language":          execution.Language().Value(),
+		"language_family":   execution.Language().Family(),
+		"language_version":  execution.Language().Version(),
+		"language_raw":      execution.Language().Raw(),
 		"status":            execution.Status().Value(),
 		"timestamp":         execution.Timestamp(),
 		"execution_time_ms": execution.ExecutionTimeMs(),
@@ -98,17 +108,36 @@ func (c *AnalyticsController) GetByExecutionID(ctx *gin.Context) {
 		"passed_tests":      execution.PassedTests(),
 		"failed_tests":      execution.FailedTests(),
 		"success":           execution.Success(),
+		"outcome":           execution.Outcome().Value(),
 		"success_rate":      execution.CalculateSuccessRate(),
+		"score":             execution.WeightedScore(),
+		"earned_points":     earnedPoints,
+		"total_points":      totalPoints,
 		"server_instance":   execution.ServerInstance(),
+		"revision":          execution.Revision(),
+		"judged_at":         execution.JudgedAt(),
 		"test_results": func() []gin.H {
 			results := make([]gin.H, 0, len(execution.TestResults()))
 			for _, tr := range execution.TestResults() {
-				results = append(results, gin.H{
+				result := gin.H{
 					"test_id":       tr.TestID().Value(),
 					"test_name":     tr.TestName(),
 					"passed":        tr.Passed(),
 					"error_message": tr.ErrorMessage(),
-				})
+					"duration_ms":   tr.DurationMs(),
+					"visibility":    tr.Visibility().Value(),
+					"weight":        tr.Weight(),
+					"earned_points": tr.EarnedPoints(),
+				}
+				// Las salidas de un test oculto revelarían su caso de prueba
+				if tr.Visibility().IsHidden() {
+					result["output_redacted"] = true
+				} else {
+					result["expected_output"] = tr.ExpectedOutput()
+					result["actual_output"] = tr.ActualOutput()
+					result["output_truncated"] = tr.OutputTruncated()
+				}
+				results = append(results, result)
 			}
 			return results
 		}(),
@@ -116,6 +145,81 @@ func (c *AnalyticsController) GetByExecutionID(ctx *gin.Context) {
 	})
 }
 
+// GetExecutionHistory obtiene la versión vigente de una ejecución y las versiones reemplazadas por re-evaluaciones
+// @Summary Obtener historial de una ejecución
+// @Description Obtiene la versión vigente de una ejecución y las versiones que reemplazó (re-evaluaciones y cambios de estado), de la más antigua a la más reciente
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param executionId path string true "ID de la ejecución"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/analytics/execution/{executionId}/history [get]
+func (c *AnalyticsController) GetExecutionHistory(ctx *gin.Context) {
+	executionID := ctx.Param("executionId")
+
+	execution, history, err := c.queryService.GetExecutionHistory(ctx.Request.Context(), executionID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if execution == nil {
+		ctx.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: "Execution analytics not found",
+			Code:    http.StatusNotFound,
+		})
+		return
+	}
+
+	revisions := make([]gin.H, 0, len(history))
+	for _, revision := range history {
+		revisions = append(revisions, gin.H{
+			"event_id":             revision.EventID,
+			"revision":             revision.Revision,
+			"judged_at":            revision.JudgedAt,
+			"status":               revision.Status,
+			"execution_time_ms":    revision.ExecutionTimeMs,
+			"exit_code":            revision.ExitCode,
+			"total_tests":          revision.TotalTests,
+			"passed_tests":         revision.PassedTests,
+			"failed_tests":         revision.FailedTests,
+			"success":              revision.Success,
+			"outcome":              revision.Outcome,
+			"score":                revision.Score,
+			"test_results":         revision.TestResults,
+			"replaced_by_event_id": revision.ReplacedByEventID,
+			"replaced_at":          revision.ReplacedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"execution_id": execution.ExecutionID().Value(),
+		"current": gin.H{
+			"event_id":          execution.EventID(),
+			"revision":          execution.Revision(),
+			"judged_at":         execution.JudgedAt(),
+			"status":            execution.Status().Value(),
+			"execution_time_ms": execution.ExecutionTimeMs(),
+			"exit_code":         execution.ExitCode(),
+			"total_tests":       execution.TotalTests(),
+			"passed_tests":      execution.PassedTests(),
+			"failed_tests":      execution.FailedTests(),
+			"success":           execution.Success(),
+			"outcome":           execution.Outcome().Value(),
+			"score":             execution.WeightedScore(),
+		},
+		"history":   revisions,
+		"revisions": len(revisions) + 1,
+	})
+}
+
 // GetByStudentID obtiene todas las ejecuciones de un estudiante
 // @Summary Obtener analytics por ID de estudiante
 // @Description Obtiene todos los análisis de ejecuciones de un estudiante específico
@@ -155,7 +259,9 @@ func (c *AnalyticsController) GetByStudentID(ctx *gin.Context) {
 			"timestamp":         exec.Timestamp(),
 			"execution_time_ms": exec.ExecutionTimeMs(),
 			"success":           exec.Success(),
+			"outcome":           exec.Outcome().Value(),
 			"success_rate":      exec.CalculateSuccessRate(),
+			"score":             exec.WeightedScore(),
 			"passed_tests":      exec.PassedTests(),
 			"total_tests":       exec.TotalTests(),
 		})
@@ -207,7 +313,9 @@ func (c *AnalyticsController) GetByChallengeID(ctx *gin.Context) {
 			"timestamp":         exec.Timestamp(),
 			"execution_time_ms": exec.ExecutionTimeMs(),
 			"success":           exec.Success(),
+			"outcome":           exec.Outcome().Value(),
 			"success_rate":      exec.CalculateSuccessRate(),
+			"score":             exec.WeightedScore(),
 			"passed_tests":      exec.PassedTests(),
 			"total_tests":       exec.TotalTests(),
 		})
@@ -280,6 +388,8 @@ func (c *AnalyticsController) GetByDateRange(ctx *gin.Context) {
 			"timestamp":         exec.Timestamp(),
 			"execution_time_ms": exec.ExecutionTimeMs(),
 			"success":           exec.Success(),
+			"outcome":           exec.Outcome().Value(),
+			"score":             exec.WeightedScore(),
 		})
 	}
 
@@ -323,16 +433,38 @@ func (c *AnalyticsController) GetStudentKPI(ctx *gin.Context) {
 		return
 	}
 
+	outcomes, err := c.queryService.GetStudentOutcomes(ctx.Request.Context(), studentID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	avgScore, err := c.queryService.GetStudentAverageScore(ctx.Request.Context(), studentID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"student_id":       studentID,
 		"total_executions": count,
 		"success_rate":     successRate,
+		"avg_score":        avgScore,
+		"outcomes":         outcomes.ByOutcome(),
 	})
 }
 
 // GetChallengeKPI obtiene KPIs de un challenge
 // @Summary Obtener KPIs de un challenge
-// @Description Obtiene las métricas clave de rendimiento de un challenge específico
+// @Description Obtiene las métricas clave de rendimiento de un challenge específico y sus datos del catálogo, si se conocen
 // @Tags KPI
 // @Accept json
 // @Produce json
@@ -373,12 +505,96 @@ func (c *AnalyticsController) GetChallengeKPI(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
+	outcomes, err := c.queryService.GetChallengeOutcomes(ctx.Request.Context(), challengeID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	avgScore, err := c.queryService.GetChallengeAverageScore(ctx.Request.Context(), challengeID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	challenge, err := c.queryService.GetChallenge(ctx.Request.Context(), challengeID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	response := gin.H{
 		"challenge_id":          challengeID,
 		"total_executions":      count,
 		"success_rate":          successRate,
 		"avg_execution_time_ms": avgTime,
-	})
+		"avg_score":             avgScore,
+		"outcomes":              outcomes.ByOutcome(),
+	}
+	if challenge != nil {
+		response["challenge"] = gin.H{
+			"title":      challenge.Title(),
+			"difficulty": challenge.Difficulty(),
+			"tags":       challenge.Tags(),
+			"course_id":  challenge.CourseID(),
+			"test_count": challenge.TestCount(),
+			"archived":   challenge.Archived(),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetChallengeTestKPI obtiene estadísticas por test de un challenge
+// @Summary Obtener KPIs por test de un challenge
+// @Description Obtiene la tasa de aprobación, la duración promedio y el peso de cada test de un challenge. failed_with_public_passed cuenta las ejecuciones en las que un test oculto falló aunque pasaron todos los tests públicos
+// @Tags KPI
+// @Accept json
+// @Produce json
+// @Param challengeId path string true "ID del challenge"
+// @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/analytics/kpi/challenge/{challengeId}/tests [get]
+func (c *AnalyticsController) GetChallengeTestKPI(ctx *gin.Context) {
+	challengeID := ctx.Param("challengeId")
+
+	stats, err := c.queryService.GetChallengeTestStats(ctx.Request.Context(), challengeID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	responses := make([]gin.H, 0, len(stats))
+	for _, stat := range stats {
+		responses = append(responses, gin.H{
+			"test_id":                   stat.TestID,
+			"test_name":                 stat.TestName,
+			"visibility":                stat.Visibility,
+			"weight":                    stat.Weight,
+			"executions":                stat.Executions,
+			"pass_rate":                 stat.PassRate,
+			"avg_duration_ms":           stat.AvgDurationMs,
+			"failed_with_public_passed": stat.FailedWithPublicPassed,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, responses)
 }
 
 // GetDailyKPI obtiene estadísticas diarias
@@ -388,7 +604,12 @@ func (c *AnalyticsController) GetChallengeKPI(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param limit query int false "Límite de resultados" default(30)
+// @Param difficulty query string false "Filtrar por dificultad del challenge"
+// @Param courseId query string false "Filtrar por curso del challenge"
+// @Param tag query string false "Filtrar por etiqueta del challenge"
+// @Param archived query bool false "Filtrar por challenges archivados (true) o activos (false)"
 // @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/analytics/kpi/daily [get]
 func (c *AnalyticsController) GetDailyKPI(ctx *gin.Context) {
@@ -421,7 +642,12 @@ func (c *AnalyticsController) GetDailyKPI(ctx *gin.Context) {
 		}
 	}
 
-	stats, err := c.queryService.GetDailyStats(ctx.Request.Context(), startDate, endDate)
+	filter, ok := parseChallengeFilter(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := c.queryService.GetDailyStats(ctx.Request.Context(), startDate, endDate, filter)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
@@ -440,7 +666,12 @@ func (c *AnalyticsController) GetDailyKPI(ctx *gin.Context) {
 // @Tags KPI
 // @Accept json
 // @Produce json
+// @Param difficulty query string false "Filtrar por dificultad del challenge"
+// @Param courseId query string false "Filtrar por curso del challenge"
+// @Param tag query string false "Filtrar por etiqueta del challenge"
+// @Param archived query bool false "Filtrar por challenges archivados (true) o activos (false)"
 // @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/analytics/kpi/languages [get]
 func (c *AnalyticsController) GetLanguageKPI(ctx *gin.Context) {
@@ -473,7 +704,12 @@ func (c *AnalyticsController) GetLanguageKPI(ctx *gin.Context) {
 		}
 	}
 
-	stats, err := c.queryService.GetLanguageStats(ctx.Request.Context(), startDate, endDate)
+	filter, ok := parseChallengeFilter(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := c.queryService.GetLanguageStats(ctx.Request.Context(), startDate, endDate, filter)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
@@ -487,8 +723,74 @@ func (c *AnalyticsController) GetLanguageKPI(ctx *gin.Context) {
 	for _, stat := range stats {
 		responses = append(responses, gin.H{
 			"language":         stat.Language,
+			"family":           stat.Family,
 			"total_executions": stat.TotalExecutions,
 			"success_rate":     stat.SuccessRate,
+			"outcomes":         stat.ByOutcome(),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// GetUnrecognizedLanguageKPI obtiene los lenguajes recibidos que no están en el registro
+// @Summary Obtener lenguajes fuera del registro
+// @Description Obtiene los valores de lenguaje recibidos que no están en el registro de lenguajes (guardados como "other"), para agregarlos como lenguaje o alias
+// @Tags KPI
+// @Accept json
+// @Produce json
+// @Param startDate query string false "Fecha de inicio (RFC3339)"
+// @Param endDate query string false "Fecha de fin (RFC3339)"
+// @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/analytics/kpi/languages/unrecognized [get]
+func (c *AnalyticsController) GetUnrecognizedLanguageKPI(ctx *gin.Context) {
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -30)
+
+	if startDateStr := ctx.Query("startDate"); startDateStr != "" {
+		var err error
+		startDate, err = time.Parse(time.RFC3339, startDateStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_date",
+				Message: "Invalid start date format. Use RFC3339",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	if endDateStr := ctx.Query("endDate"); endDateStr != "" {
+		var err error
+		endDate, err = time.Parse(time.RFC3339, endDateStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_date",
+				Message: "Invalid end date format. Use RFC3339",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	stats, err := c.queryService.GetUnrecognizedLanguageStats(ctx.Request.Context(), startDate, endDate)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	responses := make([]gin.H, 0, len(stats))
+	for _, stat := range stats {
+		responses = append(responses, gin.H{
+			"raw":              stat.Raw,
+			"total_executions": stat.TotalExecutions,
+			"last_seen_at":     stat.LastSeenAt,
 		})
 	}
 
@@ -502,13 +804,23 @@ func (c *AnalyticsController) GetLanguageKPI(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param limit query int false "Límite de resultados" default(10)
+// @Param difficulty query string false "Filtrar por dificultad del challenge"
+// @Param courseId query string false "Filtrar por curso del challenge"
+// @Param tag query string false "Filtrar por etiqueta del challenge"
+// @Param archived query bool false "Filtrar por challenges archivados (true) o activos (false)"
 // @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/analytics/kpi/top-failed-challenges [get]
 func (c *AnalyticsController) GetTopFailedChallenges(ctx *gin.Context) {
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
 
-	stats, err := c.queryService.GetTopFailedChallenges(ctx.Request.Context(), limit)
+	filter, ok := parseChallengeFilter(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := c.queryService.GetTopFailedChallenges(ctx.Request.Context(), limit, filter)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
@@ -522,15 +834,122 @@ func (c *AnalyticsController) GetTopFailedChallenges(ctx *gin.Context) {
 	for _, stat := range stats {
 		responses = append(responses, gin.H{
 			"challenge_id":          stat.ChallengeID,
+			"title":                 stat.Title,
+			"difficulty":            stat.Difficulty,
+			"course_id":             stat.CourseID,
+			"tags":                  stat.Tags,
+			"archived":              stat.Archived,
+			"total_executions":      stat.TotalExecutions,
+			"success_rate":          stat.SuccessRate,
+			"avg_execution_time_ms": stat.AvgExecTime,
+			"outcomes":              stat.ByOutcome(),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// GetDifficultyKPI obtiene estadísticas por dificultad de challenge
+// @Summary Obtener KPIs por dificultad de challenge
+// @Description Obtiene las métricas agregadas por dificultad. Solo incluye los challenges del catálogo.
+// @Tags KPI
+// @Accept json
+// @Produce json
+// @Param startDate query string false "Fecha de inicio (RFC3339)"
+// @Param endDate query string false "Fecha de fin (RFC3339)"
+// @Param difficulty query string false "Filtrar por dificultad del challenge"
+// @Param courseId query string false "Filtrar por curso del challenge"
+// @Param tag query string false "Filtrar por etiqueta del challenge"
+// @Param archived query bool false "Filtrar por challenges archivados (true) o activos (false)"
+// @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/analytics/kpi/difficulties [get]
+func (c *AnalyticsController) GetDifficultyKPI(ctx *gin.Context) {
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -30)
+
+	if startDateStr := ctx.Query("startDate"); startDateStr != "" {
+		var err error
+		startDate, err = time.Parse(time.RFC3339, startDateStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_date",
+				Message: "Invalid start date format. Use RFC3339",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	if endDateStr := ctx.Query("endDate"); endDateStr != "" {
+		var err error
+		endDate, err = time.Parse(time.RFC3339, endDateStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_date",
+				Message: "Invalid end date format. Use RFC3339",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	filter, ok := parseChallengeFilter(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := c.queryService.GetDifficultyStats(ctx.Request.Context(), startDate, endDate, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	responses := make([]gin.H, 0, len(stats))
+	for _, stat := range stats {
+		responses = append(responses, gin.H{
+			"difficulty":            stat.Difficulty,
+			"challenges":            stat.Challenges,
 			"total_executions":      stat.TotalExecutions,
 			"success_rate":          stat.SuccessRate,
 			"avg_execution_time_ms": stat.AvgExecTime,
+			"outcomes":              stat.ByOutcome(),
 		})
 	}
 
 	ctx.JSON(http.StatusOK, responses)
 }
 
+// parseChallengeFilter lee los filtros por atributos del challenge (difficulty, courseId, tag, archived).
+// Si son inválidos responde 400 y retorna false.
+func parseChallengeFilter(ctx *gin.Context) (repositories.ChallengeFilter, bool) {
+	filter := repositories.ChallengeFilter{
+		Difficulty: ctx.Query("difficulty"),
+		CourseID:   ctx.Query("courseId"),
+		Tag:        ctx.Query("tag"),
+	}
+
+	if archivedStr := ctx.Query("archived"); archivedStr != "" {
+		archived, err := strconv.ParseBool(archivedStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_filter",
+				Message: "Invalid archived filter. Use true or false",
+				Code:    http.StatusBadRequest,
+			})
+			return filter, false
+		}
+		filter.Archived = &archived
+	}
+
+	return filter, true
+}
+
 // Función auxiliar inline para transformar DailyStats - NO mapper class
 func transformDailyStats(stats []repositories.DailyStats) []gin.H {
 	responses := make([]gin.H, 0, len(stats))
@@ -547,6 +966,7 @@ func transformDailyStats(stats []repositories.DailyStats) []gin.H {
 			"failed_executions":     stat.FailedExecs,
 			"success_rate":          successRate,
 			"avg_execution_time_ms": stat.AvgExecTime,
+			"outcomes":              stat.ByOutcome(),
 		})
 	}
 	return responses
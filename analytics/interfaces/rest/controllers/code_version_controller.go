@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"github.com/nanab/analytics-service/analytics/application/queryservices"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CodeVersionController maneja las peticiones REST sobre las versiones de código de los estudiantes
+type CodeVersionController struct {
+	queryService *queryservices.CodeVersionQueryService
+}
+
+// NewCodeVersionController crea una nueva instancia del controlador
+func NewCodeVersionController(queryService *queryservices.CodeVersionQueryService) *CodeVersionController {
+	return &CodeVersionController{
+		queryService: queryService,
+	}
+}
+
+// RegisterRoutes registra las rutas del controlador
+func (c *CodeVersionController) RegisterRoutes(router *gin.RouterGroup) {
+	analytics := router.Group("/analytics")
+	{
+		analytics.GET("/student/:studentId/challenge/:challengeId/versions", c.GetTimeline)
+	}
+}
+
+// GetTimeline obtiene el timeline de versiones de un estudiante en un challenge
+// @Summary Obtener el timeline de versiones de código
+// @Description Obtiene las versiones de código de un estudiante en un challenge, en orden, con los tests aprobados, el exit code y el tiempo de su última ejecución, y un resumen de la tendencia (productive, thrashing, stalled o insufficient_data)
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param studentId path string true "ID del estudiante"
+// @Param challengeId path string true "ID del challenge"
+// @Success 200 {object} queryservices.CodeVersionTimeline
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/analytics/student/{studentId}/challenge/{challengeId}/versions [get]
+func (c *CodeVersionController) GetTimeline(ctx *gin.Context) {
+	studentID, err := valueobjects.NewStudentID(ctx.Param("studentId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_student_id",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	challengeID, err := valueobjects.NewChallengeID(ctx.Param("challengeId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_challenge_id",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	timeline, err := c.queryService.GetTimeline(ctx.Request.Context(), studentID, challengeID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, timeline)
+}
@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"github.com/nanab/analytics-service/analytics/application/commandservices"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxIngestBatchSize es la cantidad máxima de eventos por petición de ingesta
+const MaxIngestBatchSize = 1000
+
+// IngestResponse es la respuesta de una petición de ingesta de eventos
+type IngestResponse struct {
+	Accepted   int                            `json:"accepted"`
+	Rejected   int                            `json:"rejected"`
+	Duplicates int                            `json:"duplicates"` // Aceptados que ya estaban guardados
+	Updated    int                            `json:"updated"`    // Aceptados que reemplazaron una versión anterior
+	Results    []commandservices.IngestResult `json:"results"`
+}
+
+// EventIngestController maneja la ingesta de eventos por HTTP
+type EventIngestController struct {
+	ingestService *commandservices.EventIngestService
+}
+
+// NewEventIngestController crea una nueva instancia del controlador
+func NewEventIngestController(ingestService *commandservices.EventIngestService) *EventIngestController {
+	return &EventIngestController{
+		ingestService: ingestService,
+	}
+}
+
+// RegisterRoutes registra las rutas del controlador
+func (c *EventIngestController) RegisterRoutes(router *gin.RouterGroup) {
+	ingest := router.Group("/ingest")
+	{
+		ingest.POST("/executions", c.IngestExecutions)
+		ingest.POST("/registrations", c.IngestRegistrations)
+	}
+}
+
+// IngestExecutions guarda eventos de ejecución enviados por HTTP
+// @Summary Ingerir eventos de ejecución
+// @Description Guarda uno o varios eventos de ejecución de código para los ejecutores que no pueden publicar en Kafka. El cuerpo es un evento o un arreglo de eventos con el formato de execution.analytics (también se aceptan envelopes versionados y CloudEvents). Los eventos válidos se guardan en un solo lote y cada evento se acepta o rechaza por separado (también si la base de datos no acepta sus datos); las ejecuciones que ya existen se aceptan como duplicadas, por lo que reenviar un lote es seguro
+// @Tags Ingest
+// @Accept json
+// @Produce json
+// @Param events body object true "Evento o arreglo de eventos (máximo 1000)"
+// @Success 200 {object} IngestResponse "Resultado por evento"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/ingest/executions [post]
+func (c *EventIngestController) IngestExecutions(ctx *gin.Context) {
+	items, ok := ingestItems(ctx)
+	if !ok {
+		return
+	}
+
+	results, err := c.ingestService.IngestExecutions(ctx.Request.Context(), items)
+	if err != nil {
+		respondIngestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ingestResponse(results))
+}
+
+// IngestRegistrations guarda eventos de registro de usuarios enviados por HTTP
+// @Summary Ingerir eventos de registro de usuarios
+// @Description Guarda uno o varios eventos de registro de usuarios para los productores que no pueden publicar en Kafka. El cuerpo es un evento o un arreglo de eventos con el formato de iam.user.registered (también se aceptan envelopes versionados y CloudEvents). Los eventos sin fecha de registro toman la hora de recepción. Cada evento se acepta o rechaza por separado; los usuarios que ya existen se aceptan como duplicados
+// @Tags Ingest
+// @Accept json
+// @Produce json
+// @Param events body object true "Evento o arreglo de eventos (máximo 1000)"
+// @Success 200 {object} IngestResponse "Resultado por evento"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/ingest/registrations [post]
+func (c *EventIngestController) IngestRegistrations(ctx *gin.Context) {
+	items, ok := ingestItems(ctx)
+	if !ok {
+		return
+	}
+
+	results, err := c.ingestService.IngestRegistrations(ctx.Request.Context(), items)
+	if err != nil {
+		respondIngestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ingestResponse(results))
+}
+
+// ingestItems lee el cuerpo de la petición como un evento o un arreglo de eventos.
+// Si el cuerpo es inválido responde 400 y retorna false.
+func ingestItems(ctx *gin.Context) ([]json.RawMessage, bool) {
+	items, err := parseIngestBody(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return nil, false
+	}
+	return items, true
+}
+
+// parseIngestBody separa los eventos del cuerpo, que puede ser un objeto o un arreglo de objetos
+func parseIngestBody(ctx *gin.Context) ([]json.RawMessage, error) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, errors.New("request body is empty")
+	}
+
+	var items []json.RawMessage
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, fmt.Errorf("invalid JSON array: %w", err)
+		}
+	} else {
+		if !json.Valid(body) {
+			return nil, errors.New("request body is not valid JSON")
+		}
+		items = []json.RawMessage{body}
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("no events in request body")
+	}
+	if len(items) > MaxIngestBatchSize {
+		return nil, fmt.Errorf("too many events: %d (max %d)", len(items), MaxIngestBatchSize)
+	}
+	return items, nil
+}
+
+// ingestResponse resume los resultados de un lote
+func ingestResponse(results []commandservices.IngestResult) IngestResponse {
+	response := IngestResponse{Results: results}
+	for _, result := range results {
+		if result.Status != commandservices.IngestStatusAccepted {
+			response.Rejected++
+			continue
+		}
+		response.Accepted++
+		if result.Duplicate {
+			response.Duplicates++
+		}
+		if result.Updated {
+			response.Updated++
+		}
+	}
+	return response
+}
+
+// respondIngestError responde un error de guardado. Los eventos guardados antes del error no se revierten;
+// reenviar el lote completo es seguro porque el guardado es idempotente.
+func respondIngestError(ctx *gin.Context, err error) {
+	ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "ingest_failed",
+		Message: err.Error(),
+		Code:    http.StatusInternalServerError,
+	})
+}
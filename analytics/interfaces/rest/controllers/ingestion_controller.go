@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"github.com/nanab/analytics-service/analytics/application/commandservices"
+	"github.com/nanab/analytics-service/analytics/application/queryservices"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PartitionsRequest es el cuerpo de las peticiones de pausa y reanudación del consumo
+type PartitionsRequest struct {
+	Topic      string  `json:"topic" binding:"required" example:"execution.analytics"`
+	Partitions []int32 `json:"partitions,omitempty"` // Vacío = todas las particiones
+}
+
+// ResetOffsetsRequest es el cuerpo de la petición de reseteo de offsets del consumer group
+type ResetOffsetsRequest struct {
+	Topic      string     `json:"topic" binding:"required" example:"execution.analytics"`
+	Partitions []int32    `json:"partitions,omitempty"` // Vacío = todas las particiones
+	Strategy   string     `json:"strategy" binding:"required" enums:"earliest,latest,timestamp,offset" example:"timestamp"`
+	Timestamp  *time.Time `json:"timestamp,omitempty" example:"2024-05-20T12:00:00Z"`
+	Offset     *int64     `json:"offset,omitempty"`
+}
+
+// IngestionController maneja las peticiones de administración de la ingesta
+type IngestionController struct {
+	queryService *queryservices.IngestionQueryService
+	adminService *commandservices.ConsumerAdminService
+}
+
+// NewIngestionController crea una nueva instancia del controlador
+func NewIngestionController(queryService *queryservices.IngestionQueryService, adminService *commandservices.ConsumerAdminService) *IngestionController {
+	return &IngestionController{
+		queryService: queryService,
+		adminService: adminService,
+	}
+}
+
+// RegisterRoutes registra las rutas del controlador
+func (c *IngestionController) RegisterRoutes(router *gin.RouterGroup) {
+	admin := router.Group("/admin")
+	{
+		admin.GET("/ingestion", c.GetIngestionStatus)
+		admin.GET("/ingestion/group", c.GetConsumerGroup)
+		admin.POST("/ingestion/pause", c.Pause)
+		admin.POST("/ingestion/resume", c.Resume)
+		admin.POST("/ingestion/reset-offsets", c.ResetOffsets)
+	}
+}
+
+// GetIngestionStatus obtiene el estado de la ingesta de eventos
+// @Summary Estado de la ingesta de Kafka
+// @Description Obtiene, por tópico y partición, el offset confirmado, el high-water mark, el lag, si está pausada, el último evento procesado, los mensajes por segundo y los errores
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} queryservices.IngestionStatus
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/ingestion [get]
+func (c *IngestionController) GetIngestionStatus(ctx *gin.Context) {
+	status, err := c.queryService.GetIngestionStatus(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "ingestion_status_error",
+			Message: err.Error(),
+			Code:    http.StatusServiceUnavailable,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+// GetConsumerGroup obtiene la membresía del consumer group
+// @Summary Membresía del consumer group
+// @Description Obtiene el estado del consumer group, sus miembros con las particiones asignadas, y las particiones pausadas y con reseteos pendientes en esta instancia
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} queryservices.ConsumerGroupStatus
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/ingestion/group [get]
+func (c *IngestionController) GetConsumerGroup(ctx *gin.Context) {
+	group, err := c.queryService.GetConsumerGroup(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "ingestion_status_error",
+			Message: err.Error(),
+			Code:    http.StatusServiceUnavailable,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+}
+
+// Pause detiene el consumo de un tópico o de algunas de sus particiones
+// @Summary Pausar el consumo
+// @Description Detiene la lectura de las particiones indicadas (todas si no se indican) asignadas a esta instancia, por ejemplo durante un mantenimiento de la base de datos. La pausa se mantiene tras un rebalanceo, pero no tras un reinicio del servicio
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body PartitionsRequest true "Tópico y particiones"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/ingestion/pause [post]
+func (c *IngestionController) Pause(ctx *gin.Context) {
+	var request PartitionsRequest
+	if !bindIngestionRequest(ctx, &request) {
+		return
+	}
+
+	paused, err := c.adminService.Pause(request.Topic, request.Partitions)
+	if err != nil {
+		respondIngestionAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"topic":      request.Topic,
+		"partitions": paused,
+		"paused":     true,
+	})
+}
+
+// Resume retoma el consumo de un tópico o de algunas de sus particiones
+// @Summary Retomar el consumo
+// @Description Retoma la lectura de las particiones indicadas (todas si no se indican)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body PartitionsRequest true "Tópico y particiones"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/ingestion/resume [post]
+func (c *IngestionController) Resume(ctx *gin.Context) {
+	var request PartitionsRequest
+	if !bindIngestionRequest(ctx, &request) {
+		return
+	}
+
+	resumed, err := c.adminService.Resume(request.Topic, request.Partitions)
+	if err != nil {
+		respondIngestionAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"topic":      request.Topic,
+		"partitions": resumed,
+		"paused":     false,
+	})
+}
+
+// ResetOffsets mueve los offsets del consumer group en un tópico
+// @Summary Resetear offsets del consumer group
+// @Description Mueve los offsets de las particiones indicadas (todas si no se indican) al mensaje más antiguo (earliest), al final (latest), al primer mensaje desde una fecha (timestamp) o a un offset explícito (offset). La instancia se reincorpora al consumer group y fija los offsets de las particiones que tiene asignadas
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body ResetOffsetsRequest true "Tópico, particiones y estrategia"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/ingestion/reset-offsets [post]
+func (c *IngestionController) ResetOffsets(ctx *gin.Context) {
+	var request ResetOffsetsRequest
+	if !bindIngestionRequest(ctx, &request) {
+		return
+	}
+
+	offsets, err := c.adminService.ResetOffsets(ctx.Request.Context(), commandservices.OffsetResetRequest{
+		Topic:      request.Topic,
+		Partitions: request.Partitions,
+		Strategy:   commandservices.OffsetResetStrategy(request.Strategy),
+		Timestamp:  request.Timestamp,
+		Offset:     request.Offset,
+	})
+	if err != nil {
+		respondIngestionAdminError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"topic":    request.Topic,
+		"strategy": request.Strategy,
+		"offsets":  offsets,
+	})
+}
+
+// Helper methods
+
+// bindIngestionRequest deserializa el cuerpo de la petición
+func bindIngestionRequest(ctx *gin.Context, request interface{}) bool {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return false
+	}
+	return true
+}
+
+// respondIngestionAdminError responde el error de una operación de administración de la ingesta
+func respondIngestionAdminError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, commandservices.ErrUnknownConsumerTopic),
+		errors.Is(err, commandservices.ErrUnknownPartition),
+		errors.Is(err, commandservices.ErrInvalidOffsetReset):
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	default:
+		ctx.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "ingestion_admin_error",
+			Message: err.Error(),
+			Code:    http.StatusServiceUnavailable,
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"github.com/nanab/analytics-service/analytics/application/queryservices"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OutboxController maneja las peticiones de administración del outbox de eventos de dominio
+type OutboxController struct {
+	queryService *queryservices.OutboxQueryService
+}
+
+// NewOutboxController crea una nueva instancia del controlador
+func NewOutboxController(queryService *queryservices.OutboxQueryService) *OutboxController {
+	return &OutboxController{
+		queryService: queryService,
+	}
+}
+
+// RegisterRoutes registra las rutas del controlador
+func (c *OutboxController) RegisterRoutes(router *gin.RouterGroup) {
+	admin := router.Group("/admin")
+	{
+		admin.GET("/outbox", c.GetOutboxStatus)
+	}
+}
+
+// GetOutboxStatus obtiene el estado del outbox
+// @Summary Estado del outbox de eventos de dominio
+// @Description Obtiene la cantidad de eventos pendientes de publicar y de eventos apartados como fallidos por agotar OUTBOX_MAX_ATTEMPTS, con la fecha del más antiguo de cada grupo. El estado es DEGRADED si hay eventos apartados
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} queryservices.OutboxStatus
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/outbox [get]
+func (c *OutboxController) GetOutboxStatus(ctx *gin.Context) {
+	status, err := c.queryService.GetStatus(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "outbox_status_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
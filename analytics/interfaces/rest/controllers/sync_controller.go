@@ -2,20 +2,38 @@ package controllers
 
 import (
 	"github.com/nanab/analytics-service/analytics/application/commandservices"
+	"github.com/nanab/analytics-service/analytics/application/queryservices"
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
+	"github.com/nanab/analytics-service/analytics/domain/repositories"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// ReplayRequest es el cuerpo de la petición de replay de una ventana de tiempo
+type ReplayRequest struct {
+	Topic  string     `json:"topic" binding:"required" example:"execution.analytics"`
+	Start  time.Time  `json:"start" binding:"required" example:"2024-05-20T12:00:00Z"`
+	End    *time.Time `json:"end,omitempty" example:"2024-05-20T18:00:00Z"`
+	DryRun bool       `json:"dry_run,omitempty" example:"false"`
+}
+
 // SyncController maneja las peticiones de sincronización
 type SyncController struct {
-	syncService *commandservices.SyncService
+	jobService      *commandservices.SyncJobService
+	jobQueryService *queryservices.SyncJobQueryService
 }
 
 // NewSyncController crea una nueva instancia del controlador
-func NewSyncController(syncService *commandservices.SyncService) *SyncController {
+func NewSyncController(jobService *commandservices.SyncJobService, jobQueryService *queryservices.SyncJobQueryService) *SyncController {
 	return &SyncController{
-		syncService: syncService,
+		jobService:      jobService,
+		jobQueryService: jobQueryService,
 	}
 }
 
@@ -24,32 +42,397 @@ func (c *SyncController) RegisterRoutes(router *gin.RouterGroup) {
 	sync := router.Group("/sync")
 	{
 		sync.POST("/events", c.SyncEvents)
+		sync.POST("/replay", c.Replay)
+
+		// Jobs de sincronización
+		jobs := sync.Group("/jobs")
+		{
+			jobs.GET("", c.GetJobs)
+			jobs.GET("/:id", c.GetJob)
+			jobs.GET("/:id/report", c.GetJobReport)
+			jobs.POST("/:id/cancel", c.CancelJob)
+			jobs.POST("/:id/resume", c.ResumeJob)
+		}
 	}
 }
 
-// SyncEvents sincroniza todos los eventos del tópico de Kafka
+// SyncEvents inicia la sincronización de los eventos del tópico de Kafka
 // @Summary Sincronizar eventos de Kafka
-// @Description Obtiene todos los eventos del tópico execution.analytics de Kafka y los guarda en la base de datos
+// @Description Crea un job en segundo plano que lee todos los eventos del tópico execution.analytics de Kafka y los guarda en la base de datos. El avance se consulta en /api/v1/sync/jobs/{id}. Con dryRun=true no escribe: compara el tópico con execution_analytics y genera un reporte en /api/v1/sync/jobs/{id}/report
 // @Tags Sync
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{}
+// @Param dryRun query bool false "Solo comparar el tópico con la base de datos" default(false)
+// @Success 202 {object} map[string]interface{} "Job creado"
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Ya hay un job activo para el tópico"
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/sync/events [post]
 func (c *SyncController) SyncEvents(ctx *gin.Context) {
-	count, err := c.syncService.SyncAllEvents(ctx.Request.Context())
+	dryRun, ok := dryRunParam(ctx)
+	if !ok {
+		return
+	}
+
+	job, err := c.jobService.StartJob(ctx.Request.Context(), valueobjects.SyncTargetExecutionAnalytics, dryRun)
+	if err != nil {
+		respondSyncJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, syncJobResponse(job))
+}
+
+// Replay reprocesa los eventos publicados en una ventana de tiempo
+// @Summary Replay de una ventana de tiempo
+// @Description Crea un job en segundo plano que reprocesa los mensajes del tópico publicados entre start y end (RFC3339). Acepta los tópicos de ejecuciones, registros de usuarios, catálogo de challenges y versiones de código. Los offsets se obtienen con la API offsets-for-times de Kafka; sin end, el replay llega hasta el último mensaje. Con dry_run solo reporta los eventos de la ventana que faltan en la base de datos o son inválidos
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param request body ReplayRequest true "Tópico y ventana de tiempo"
+// @Success 202 {object} map[string]interface{} "Job creado"
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Ya hay un job activo para el tópico"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/sync/replay [post]
+func (c *SyncController) Replay(ctx *gin.Context) {
+	var request ReplayRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	job, err := c.jobService.StartReplay(ctx.Request.Context(), request.Topic, request.Start, request.End, request.DryRun)
+	if err != nil {
+		respondSyncJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, syncJobResponse(job))
+}
+
+// GetJobs obtiene los últimos jobs de sincronización
+// @Summary Listar jobs de sincronización
+// @Description Obtiene los últimos jobs de sincronización creados
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param limit query int false "Límite de resultados" default(20)
+// @Success 200 {array} map[string]interface{}
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/sync/jobs [get]
+func (c *SyncController) GetJobs(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if limit <= 0 {
+		limit = 20
+	}
+
+	jobs, err := c.jobQueryService.GetRecentJobs(ctx.Request.Context(), limit)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "sync_error",
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	response := make([]gin.H, 0, len(jobs))
+	for _, job := range jobs {
+		response = append(response, syncJobResponse(job))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetJob obtiene el avance de un job de sincronización
+// @Summary Obtener job de sincronización
+// @Description Obtiene el estado, el avance por partición y los contadores de eventos sincronizados, omitidos e inválidos de un job
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del job (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/sync/jobs/{id} [get]
+func (c *SyncController) GetJob(ctx *gin.Context) {
+	id, ok := syncJobID(ctx)
+	if !ok {
+		return
+	}
+
+	job, err := c.jobQueryService.GetJob(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
 			Message: err.Error(),
 			Code:    http.StatusInternalServerError,
 		})
 		return
 	}
 
+	if job == nil {
+		respondSyncJobError(ctx, commandservices.ErrSyncJobNotFound)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, syncJobResponse(job))
+}
+
+// GetJobReport obtiene el reporte de reconciliación de un job de sincronización
+// @Summary Obtener reporte de reconciliación
+// @Description Obtiene las diferencias encontradas por un job: eventos que faltan en la base de datos (missing, solo dry-run), registros sin evento en el tópico (orphaned, solo dry-run del tópico completo) , eventos que no pasan la validación de dominio con su motivo (invalid) y rangos de offsets que el job no llegó a leer porque la partición dejó de entregar mensajes (unread). Los registros sin evento se calculan al terminar el job y pueden incluir eventos eliminados por la retención del tópico
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del job (UUID)"
+// @Param kind query string false "Tipo de diferencia" Enums(missing, orphaned, invalid, unread)
+// @Param limit query int false "Límite de resultados" default(100)
+// @Param offset query int false "Offset para paginación" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/sync/jobs/{id}/report [get]
+func (c *SyncController) GetJobReport(ctx *gin.Context) {
+	id, ok := syncJobID(ctx)
+	if !ok {
+		return
+	}
+
+	var kind *valueobjects.ReconciliationKind
+	if value := ctx.Query("kind"); value != "" {
+		parsed, err := valueobjects.NewReconciliationKind(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_kind",
+				Message: "kind must be one of: missing, orphaned, invalid, unread",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		kind = &parsed
+	}
+
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "100"))
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if limit <= 0 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	job, err := c.jobQueryService.GetJob(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+	if job == nil {
+		respondSyncJobError(ctx, commandservices.ErrSyncJobNotFound)
+		return
+	}
+
+	items, err := c.jobQueryService.GetReport(ctx.Request.Context(), id, kind, limit, offset)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	response := make([]gin.H, 0, len(items))
+	for _, item := range items {
+		response = append(response, gin.H{
+			"kind":      item.Kind().Value(),
+			"key":       item.Key(),
+			"partition": item.Partition(),
+			"offset":    item.Offset(),
+			"reason":    item.Reason(),
+		})
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
-		"message":      "Sync completed successfully",
-		"synced_count": count,
-		"status":       "success",
+		"job_id":  job.ID(),
+		"dry_run": job.DryRun(),
+		"status":  job.Status().Value(),
+		"summary": gin.H{
+			"present":  job.Skipped(),
+			"missing":  job.Missing(),
+			"orphaned": job.Orphaned(),
+			"invalid":  job.Invalid(),
+			"unread":   job.Unread(),
+		},
+		"items":  response,
+		"limit":  limit,
+		"offset": offset,
 	})
 }
+
+// CancelJob cancela un job de sincronización
+// @Summary Cancelar job de sincronización
+// @Description Detiene un job activo guardando su checkpoint; puede retomarse después
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del job (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "El job ya terminó"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/sync/jobs/{id}/cancel [post]
+func (c *SyncController) CancelJob(ctx *gin.Context) {
+	id, ok := syncJobID(ctx)
+	if !ok {
+		return
+	}
+
+	job, err := c.jobService.CancelJob(ctx.Request.Context(), id)
+	if err != nil {
+		respondSyncJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, syncJobResponse(job))
+}
+
+// ResumeJob retoma un job de sincronización
+// @Summary Retomar job de sincronización
+// @Description Retoma un job fallido o cancelado desde el checkpoint de cada partición
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del job (UUID)"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "El job ya está activo o completado"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/sync/jobs/{id}/resume [post]
+func (c *SyncController) ResumeJob(ctx *gin.Context) {
+	id, ok := syncJobID(ctx)
+	if !ok {
+		return
+	}
+
+	job, err := c.jobService.ResumeJob(ctx.Request.Context(), id)
+	if err != nil {
+		respondSyncJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, syncJobResponse(job))
+}
+
+// Helper methods
+
+// syncJobID obtiene y valida el ID del job de la ruta
+func syncJobID(ctx *gin.Context) (string, bool) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_job_id",
+			Message: "invalid sync job ID format: must be a valid UUID",
+			Code:    http.StatusBadRequest,
+		})
+		return "", false
+	}
+	return id, true
+}
+
+// dryRunParam obtiene el parámetro dryRun de la query
+func dryRunParam(ctx *gin.Context) (bool, bool) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_dry_run",
+			Message: "dryRun must be a boolean",
+			Code:    http.StatusBadRequest,
+		})
+		return false, false
+	}
+	return dryRun, true
+}
+
+// respondSyncJobError responde el error de una operación sobre jobs de sincronización
+func respondSyncJobError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, commandservices.ErrUnknownSyncTopic), errors.Is(err, aggregates.ErrInvalidReplayWindow):
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	case errors.Is(err, commandservices.ErrSyncJobNotFound):
+		ctx.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, repositories.ErrSyncJobAlreadyActive), errors.Is(err, aggregates.ErrInvalidSyncJobTransition):
+		ctx.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "sync_conflict",
+			Message: err.Error(),
+			Code:    http.StatusConflict,
+		})
+	default:
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "sync_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
+
+// syncJobResponse convierte un job a la respuesta REST
+func syncJobResponse(job *aggregates.SyncJob) gin.H {
+	partitions := make([]gin.H, 0, len(job.Checkpoints()))
+	for _, checkpoint := range job.Checkpoints() {
+		partitions = append(partitions, gin.H{
+			"partition":    checkpoint.Partition(),
+			"start_offset": checkpoint.StartOffset(),
+			"next_offset":  checkpoint.NextOffset(),
+			"end_offset":   checkpoint.EndOffset(),
+			"done":         checkpoint.IsDone(),
+			"incomplete":   checkpoint.IsIncomplete(),
+			"unread":       checkpoint.Unread(),
+		})
+	}
+
+	return gin.H{
+		"id":          job.ID(),
+		"target":      job.Target().Value(),
+		"topic":       job.Topic(),
+		"replay":      job.IsReplay(),
+		"from":        job.From(),
+		"to":          job.To(),
+		"dry_run":     job.DryRun(),
+		"status":      job.Status().Value(),
+		"progress":    job.Progress(),
+		"synced":      job.Synced(),
+		"skipped":     job.Skipped(),
+		"invalid":     job.Invalid(),
+		"missing":     job.Missing(),
+		"orphaned":    job.Orphaned(),
+		"unread":      job.Unread(),
+		"error":       job.ErrorMessage(),
+		"partitions":  partitions,
+		"created_at":  job.CreatedAt(),
+		"started_at":  job.StartedAt(),
+		"finished_at": job.FinishedAt(),
+		"updated_at":  job.UpdatedAt(),
+	}
+}
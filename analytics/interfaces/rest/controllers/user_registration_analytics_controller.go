@@ -12,18 +12,18 @@ import (
 
 // UserRegistrationAnalyticsController maneja las peticiones REST de analytics de registros de usuarios
 type UserRegistrationAnalyticsController struct {
-	queryService *queryservices.UserRegistrationAnalyticsQueryService
-	syncService  *commandservices.UserRegistrationSyncService
+	queryService   *queryservices.UserRegistrationAnalyticsQueryService
+	syncJobService *commandservices.SyncJobService
 }
 
 // NewUserRegistrationAnalyticsController crea una nueva instancia del controlador
 func NewUserRegistrationAnalyticsController(
 	queryService *queryservices.UserRegistrationAnalyticsQueryService,
-	syncService *commandservices.UserRegistrationSyncService,
+	syncJobService *commandservices.SyncJobService,
 ) *UserRegistrationAnalyticsController {
 	return &UserRegistrationAnalyticsController{
-		queryService: queryService,
-		syncService:  syncService,
+		queryService:   queryService,
+		syncJobService: syncJobService,
 	}
 }
 
@@ -115,30 +115,31 @@ func (c *UserRegistrationAnalyticsController) GetTotalUsers(ctx *gin.Context) {
 	})
 }
 
-// SyncFromKafka sincroniza todos los eventos de Kafka
+// SyncFromKafka inicia la sincronización de los eventos de Kafka
 // @Summary Sincronizar eventos de registro de usuarios desde Kafka
-// @Description Lee todos los mensajes disponibles del tópico community.registration y los guarda en la base de datos
+// @Description Crea un job en segundo plano que lee todos los mensajes disponibles del tópico de registros y los guarda en la base de datos. El avance se consulta en /api/v1/sync/jobs/{id}. Con dryRun=true no escribe: compara el tópico con user_registration_analytics y genera un reporte en /api/v1/sync/jobs/{id}/report
 // @Tags User Registration Analytics
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{} "Resultado de la sincronización"
+// @Param dryRun query bool false "Solo comparar el tópico con la base de datos" default(false)
+// @Success 202 {object} map[string]interface{} "Job creado"
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Ya hay un job activo para el tópico"
 // @Failure 500 {object} ErrorResponse "Error interno del servidor"
 // @Router /api/v1/user-registration-analytics/sync [post]
 func (c *UserRegistrationAnalyticsController) SyncFromKafka(ctx *gin.Context) {
-	count, err := c.syncService.SyncAllEvents(ctx.Request.Context())
+	dryRun, ok := dryRunParam(ctx)
+	if !ok {
+		return
+	}
+
+	job, err := c.syncJobService.StartJob(ctx.Request.Context(), valueobjects.SyncTargetUserRegistration, dryRun)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "sync_error",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
-		})
+		respondSyncJobError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"message":       "Sync completed successfully",
-		"events_synced": count,
-	})
+	ctx.JSON(http.StatusAccepted, syncJobResponse(job))
 }
 
 // Helper methods
@@ -146,6 +147,7 @@ func (c *UserRegistrationAnalyticsController) SyncFromKafka(ctx *gin.Context) {
 func (c *UserRegistrationAnalyticsController) toResponse(userReg *aggregates.UserRegistrationAnalytics) gin.H {
 	return gin.H{
 		"id":            userReg.ID(),
+		"event_id":      userReg.EventID(),
 		"user_id":       userReg.UserID().Value(),
 		"profile_id":    userReg.ProfileID().Value(),
 		"username":      userReg.Username(),
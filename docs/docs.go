@@ -24,9 +24,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/analytics/challenge/{challengeId}": {
+        "/api/v1/admin/ingestion": {
             "get": {
-                "description": "Obtiene todos los análisis de ejecuciones de un challenge específico",
+                "description": "Obtiene, por tópico y partición, el offset confirmado, el high-water mark, el lag, si está pausada, el último evento procesado, los mensajes por segundo y los errores",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,45 +34,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Analytics"
-                ],
-                "summary": "Obtener analytics por ID de challenge",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID del challenge",
-                        "name": "challengeId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Número de página",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Tamaño de página",
-                        "name": "pageSize",
-                        "in": "query"
-                    }
+                    "Admin"
                 ],
+                "summary": "Estado de la ingesta de Kafka",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "additionalProperties": true
-                            }
+                            "$ref": "#/definitions/queryservices.IngestionStatus"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -80,9 +53,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/analytics/date-range": {
+        "/api/v1/admin/ingestion/group": {
             "get": {
-                "description": "Obtiene todos los análisis de ejecuciones en un rango de fechas específico",
+                "description": "Obtiene el estado del consumer group, sus miembros con las particiones asignadas, y las particiones pausadas y con reseteos pendientes en esta instancia",
                 "consumes": [
                     "application/json"
                 ],
@@ -90,52 +63,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Analytics"
-                ],
-                "summary": "Obtener analytics por rango de fechas",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Fecha de inicio (RFC3339)",
-                        "name": "startDate",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Fecha de fin (RFC3339)",
-                        "name": "endDate",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Número de página",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Tamaño de página",
-                        "name": "pageSize",
-                        "in": "query"
-                    }
+                    "Admin"
                 ],
+                "summary": "Membresía del consumer group",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "additionalProperties": true
-                            }
+                            "$ref": "#/definitions/queryservices.ConsumerGroupStatus"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -143,9 +82,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/analytics/execution/{executionId}": {
-            "get": {
-                "description": "Obtiene el análisis completo de una ejecución específica por su ID",
+        "/api/v1/admin/ingestion/pause": {
+            "post": {
+                "description": "Detiene la lectura de las particiones indicadas (todas si no se indican) asignadas a esta instancia, por ejemplo durante un mantenimiento de la base de datos. La pausa se mantiene tras un rebalanceo, pero no tras un reinicio del servicio",
                 "consumes": [
                     "application/json"
                 ],
@@ -153,16 +92,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Analytics"
+                    "Admin"
                 ],
-                "summary": "Obtener analytics por ID de ejecución",
+                "summary": "Pausar el consumo",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID de la ejecución",
-                        "name": "executionId",
-                        "in": "path",
-                        "required": true
+                        "description": "Tópico y particiones",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PartitionsRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -179,8 +120,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -188,9 +129,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/analytics/kpi/challenge/{challengeId}": {
-            "get": {
-                "description": "Obtiene las métricas clave de rendimiento de un challenge específico",
+        "/api/v1/admin/ingestion/reset-offsets": {
+            "post": {
+                "description": "Mueve los offsets de las particiones indicadas (todas si no se indican) al mensaje más antiguo (earliest), al final (latest), al primer mensaje desde una fecha (timestamp) o a un offset explícito (offset). La instancia se reincorpora al consumer group y fija los offsets de las particiones que tiene asignadas",
                 "consumes": [
                     "application/json"
                 ],
@@ -198,21 +139,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "KPI"
+                    "Admin"
                 ],
-                "summary": "Obtener KPIs de un challenge",
+                "summary": "Resetear offsets del consumer group",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID del challenge",
-                        "name": "challengeId",
-                        "in": "path",
-                        "required": true
+                        "description": "Tópico, particiones y estrategia",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ResetOffsetsRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -223,13 +166,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/analytics/kpi/daily": {
-            "get": {
-                "description": "Obtiene las métricas agregadas por día",
+        "/api/v1/admin/ingestion/resume": {
+            "post": {
+                "description": "Retoma la lectura de las particiones indicadas (todas si no se indican)",
                 "consumes": [
                     "application/json"
                 ],
@@ -237,31 +186,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "KPI"
+                    "Admin"
                 ],
-                "summary": "Obtener KPIs diarios",
+                "summary": "Retomar el consumo",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 30,
-                        "description": "Límite de resultados",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Tópico y particiones",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PartitionsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "additionalProperties": true
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -269,9 +223,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/analytics/kpi/languages": {
+        "/api/v1/admin/outbox": {
             "get": {
-                "description": "Obtiene las métricas agregadas por lenguaje de programación",
+                "description": "Obtiene la cantidad de eventos pendientes de publicar y de eventos apartados como fallidos por agotar OUTBOX_MAX_ATTEMPTS, con la fecha del más antiguo de cada grupo. El estado es DEGRADED si hay eventos apartados",
                 "consumes": [
                     "application/json"
                 ],
@@ -279,18 +233,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "KPI"
+                    "Admin"
                 ],
-                "summary": "Obtener KPIs por lenguaje de programación",
+                "summary": "Estado del outbox de eventos de dominio",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "additionalProperties": true
-                            }
+                            "$ref": "#/definitions/queryservices.OutboxStatus"
                         }
                     },
                     "500": {
@@ -302,9 +252,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/analytics/kpi/student/{studentId}": {
+        "/api/v1/analytics/challenge/{challengeId}": {
             "get": {
-                "description": "Obtiene las métricas clave de rendimiento de un estudiante específico",
+                "description": "Obtiene todos los análisis de ejecuciones de un challenge específico",
                 "consumes": [
                     "application/json"
                 ],
@@ -312,54 +262,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "KPI"
+                    "Analytics"
                 ],
-                "summary": "Obtener KPIs de un estudiante",
+                "summary": "Obtener analytics por ID de challenge",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID del estudiante",
-                        "name": "studentId",
+                        "description": "ID del challenge",
+                        "name": "challengeId",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/analytics/kpi/top-failed-challenges": {
-            "get": {
-                "description": "Obtiene los challenges que tienen mayor cantidad de ejecuciones fallidas",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "KPI"
-                ],
-                "summary": "Obtener top challenges con más fallos",
-                "parameters": [
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Límite de resultados",
-                        "name": "limit",
+                        "default": 1,
+                        "description": "Número de página",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Tamaño de página",
+                        "name": "pageSize",
                         "in": "query"
                     }
                 ],
@@ -374,8 +299,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -383,9 +308,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/analytics/student/{studentId}": {
+        "/api/v1/analytics/date-range": {
             "get": {
-                "description": "Obtiene todos los análisis de ejecuciones de un estudiante específico",
+                "description": "Obtiene todos los análisis de ejecuciones en un rango de fechas específico",
                 "consumes": [
                     "application/json"
                 ],
@@ -395,13 +320,20 @@ const docTemplate = `{
                 "tags": [
                     "Analytics"
                 ],
-                "summary": "Obtener analytics por ID de estudiante",
+                "summary": "Obtener analytics por rango de fechas",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID del estudiante",
-                        "name": "studentId",
-                        "in": "path",
+                        "description": "Fecha de inicio (RFC3339)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha de fin (RFC3339)",
+                        "name": "endDate",
+                        "in": "query",
                         "required": true
                     },
                     {
@@ -439,9 +371,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/sync/events": {
-            "post": {
-                "description": "Obtiene todos los eventos del tópico execution.analytics de Kafka y los guarda en la base de datos",
+        "/api/v1/analytics/execution/{executionId}": {
+            "get": {
+                "description": "Obtiene el análisis completo de una ejecución específica por su ID. Las salidas esperada y obtenida de los tests ocultos no se incluyen (output_redacted)",
                 "consumes": [
                     "application/json"
                 ],
@@ -449,9 +381,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sync"
+                    "Analytics"
+                ],
+                "summary": "Obtener analytics por ID de ejecución",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la ejecución",
+                        "name": "executionId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Sincronizar eventos de Kafka",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -460,8 +401,14 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -469,9 +416,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user-registration-analytics/kpi/total-users": {
+        "/api/v1/analytics/execution/{executionId}/history": {
             "get": {
-                "description": "Obtiene el número total de usuarios registrados en el sistema",
+                "description": "Obtiene la versión vigente de una ejecución y las versiones que reemplazó (re-evaluaciones y cambios de estado), de la más antigua a la más reciente",
                 "consumes": [
                     "application/json"
                 ],
@@ -479,9 +426,1100 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Registration Analytics"
+                    "Analytics"
                 ],
-                "summary": "Obtener total de usuarios registrados",
+                "summary": "Obtener historial de una ejecución",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la ejecución",
+                        "name": "executionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/kpi/challenge/{challengeId}": {
+            "get": {
+                "description": "Obtiene las métricas clave de rendimiento de un challenge específico y sus datos del catálogo, si se conocen",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KPI"
+                ],
+                "summary": "Obtener KPIs de un challenge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del challenge",
+                        "name": "challengeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/kpi/challenge/{challengeId}/tests": {
+            "get": {
+                "description": "Obtiene la tasa de aprobación, la duración promedio y el peso de cada test de un challenge. failed_with_public_passed cuenta las ejecuciones en las que un test oculto falló aunque pasaron todos los tests públicos",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KPI"
+                ],
+                "summary": "Obtener KPIs por test de un challenge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del challenge",
+                        "name": "challengeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/kpi/daily": {
+            "get": {
+                "description": "Obtiene las métricas agregadas por día",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KPI"
+                ],
+                "summary": "Obtener KPIs diarios",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Límite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por dificultad del challenge",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por curso del challenge",
+                        "name": "courseId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por etiqueta del challenge",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filtrar por challenges archivados (true) o activos (false)",
+                        "name": "archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/kpi/difficulties": {
+            "get": {
+                "description": "Obtiene las métricas agregadas por dificultad. Solo incluye los challenges del catálogo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KPI"
+                ],
+                "summary": "Obtener KPIs por dificultad de challenge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fecha de inicio (RFC3339)",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha de fin (RFC3339)",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por dificultad del challenge",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por curso del challenge",
+                        "name": "courseId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por etiqueta del challenge",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filtrar por challenges archivados (true) o activos (false)",
+                        "name": "archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/kpi/languages": {
+            "get": {
+                "description": "Obtiene las métricas agregadas por lenguaje de programación",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KPI"
+                ],
+                "summary": "Obtener KPIs por lenguaje de programación",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtrar por dificultad del challenge",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por curso del challenge",
+                        "name": "courseId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por etiqueta del challenge",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filtrar por challenges archivados (true) o activos (false)",
+                        "name": "archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/kpi/languages/unrecognized": {
+            "get": {
+                "description": "Obtiene los valores de lenguaje recibidos que no están en el registro de lenguajes (guardados como \"other\"), para agregarlos como lenguaje o alias",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KPI"
+                ],
+                "summary": "Obtener lenguajes fuera del registro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fecha de inicio (RFC3339)",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha de fin (RFC3339)",
+                        "name": "endDate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/kpi/student/{studentId}": {
+            "get": {
+                "description": "Obtiene las métricas clave de rendimiento de un estudiante específico",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KPI"
+                ],
+                "summary": "Obtener KPIs de un estudiante",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del estudiante",
+                        "name": "studentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/kpi/top-failed-challenges": {
+            "get": {
+                "description": "Obtiene los challenges que tienen mayor cantidad de ejecuciones fallidas",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KPI"
+                ],
+                "summary": "Obtener top challenges con más fallos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Límite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por dificultad del challenge",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por curso del challenge",
+                        "name": "courseId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por etiqueta del challenge",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filtrar por challenges archivados (true) o activos (false)",
+                        "name": "archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/student/{studentId}": {
+            "get": {
+                "description": "Obtiene todos los análisis de ejecuciones de un estudiante específico",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Obtener analytics por ID de estudiante",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del estudiante",
+                        "name": "studentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Número de página",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Tamaño de página",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/student/{studentId}/challenge/{challengeId}/versions": {
+            "get": {
+                "description": "Obtiene las versiones de código de un estudiante en un challenge, en orden, con los tests aprobados, el exit code y el tiempo de su última ejecución, y un resumen de la tendencia (productive, thrashing, stalled o insufficient_data)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Obtener el timeline de versiones de código",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del estudiante",
+                        "name": "studentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del challenge",
+                        "name": "challengeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/queryservices.CodeVersionTimeline"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/ingest/executions": {
+            "post": {
+                "description": "Guarda uno o varios eventos de ejecución de código para los ejecutores que no pueden publicar en Kafka. El cuerpo es un evento o un arreglo de eventos con el formato de execution.analytics (también se aceptan envelopes versionados y CloudEvents). Los eventos válidos se guardan en un solo lote y cada evento se acepta o rechaza por separado (también si la base de datos no acepta sus datos); las ejecuciones que ya existen se aceptan como duplicadas, por lo que reenviar un lote es seguro",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ingest"
+                ],
+                "summary": "Ingerir eventos de ejecución",
+                "parameters": [
+                    {
+                        "description": "Evento o arreglo de eventos (máximo 1000)",
+                        "name": "events",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resultado por evento",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.IngestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/ingest/registrations": {
+            "post": {
+                "description": "Guarda uno o varios eventos de registro de usuarios para los productores que no pueden publicar en Kafka. El cuerpo es un evento o un arreglo de eventos con el formato de iam.user.registered (también se aceptan envelopes versionados y CloudEvents). Los eventos sin fecha de registro toman la hora de recepción. Cada evento se acepta o rechaza por separado; los usuarios que ya existen se aceptan como duplicados",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ingest"
+                ],
+                "summary": "Ingerir eventos de registro de usuarios",
+                "parameters": [
+                    {
+                        "description": "Evento o arreglo de eventos (máximo 1000)",
+                        "name": "events",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resultado por evento",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.IngestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sync/events": {
+            "post": {
+                "description": "Crea un job en segundo plano que lee todos los eventos del tópico execution.analytics de Kafka y los guarda en la base de datos. El avance se consulta en /api/v1/sync/jobs/{id}. Con dryRun=true no escribe: compara el tópico con execution_analytics y genera un reporte en /api/v1/sync/jobs/{id}/report",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Sincronizar eventos de Kafka",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Solo comparar el tópico con la base de datos",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Job creado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya hay un job activo para el tópico",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sync/jobs": {
+            "get": {
+                "description": "Obtiene los últimos jobs de sincronización creados",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Listar jobs de sincronización",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Límite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sync/jobs/{id}": {
+            "get": {
+                "description": "Obtiene el estado, el avance por partición y los contadores de eventos sincronizados, omitidos e inválidos de un job",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Obtener job de sincronización",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del job (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sync/jobs/{id}/cancel": {
+            "post": {
+                "description": "Detiene un job activo guardando su checkpoint; puede retomarse después",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Cancelar job de sincronización",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del job (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "El job ya terminó",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sync/jobs/{id}/report": {
+            "get": {
+                "description": "Obtiene las diferencias encontradas por un job: eventos que faltan en la base de datos (missing, solo dry-run), registros sin evento en el tópico (orphaned, solo dry-run del tópico completo) , eventos que no pasan la validación de dominio con su motivo (invalid) y rangos de offsets que el job no llegó a leer porque la partición dejó de entregar mensajes (unread). Los registros sin evento se calculan al terminar el job y pueden incluir eventos eliminados por la retención del tópico",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Obtener reporte de reconciliación",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del job (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "missing",
+                            "orphaned",
+                            "invalid",
+                            "unread"
+                        ],
+                        "type": "string",
+                        "description": "Tipo de diferencia",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Límite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginación",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sync/jobs/{id}/resume": {
+            "post": {
+                "description": "Retoma un job fallido o cancelado desde el checkpoint de cada partición",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Retomar job de sincronización",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del job (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "El job ya está activo o completado",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sync/replay": {
+            "post": {
+                "description": "Crea un job en segundo plano que reprocesa los mensajes del tópico publicados entre start y end (RFC3339). Acepta los tópicos de ejecuciones, registros de usuarios, catálogo de challenges y versiones de código. Los offsets se obtienen con la API offsets-for-times de Kafka; sin end, el replay llega hasta el último mensaje. Con dry_run solo reporta los eventos de la ventana que faltan en la base de datos o son inválidos",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Replay de una ventana de tiempo",
+                "parameters": [
+                    {
+                        "description": "Tópico y ventana de tiempo",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReplayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Job creado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya hay un job activo para el tópico",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user-registration-analytics/kpi/total-users": {
+            "get": {
+                "description": "Obtiene el número total de usuarios registrados en el sistema",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Registration Analytics"
+                ],
+                "summary": "Obtener total de usuarios registrados",
                 "responses": {
                     "200": {
                         "description": "Total de usuarios",
@@ -501,7 +1539,7 @@ const docTemplate = `{
         },
         "/api/v1/user-registration-analytics/sync": {
             "post": {
-                "description": "Lee todos los mensajes disponibles del tópico community.registration y los guarda en la base de datos",
+                "description": "Crea un job en segundo plano que lee todos los mensajes disponibles del tópico de registros y los guarda en la base de datos. El avance se consulta en /api/v1/sync/jobs/{id}. Con dryRun=true no escribe: compara el tópico con user_registration_analytics y genera un reporte en /api/v1/sync/jobs/{id}/report",
                 "consumes": [
                     "application/json"
                 ],
@@ -512,14 +1550,35 @@ const docTemplate = `{
                     "User Registration Analytics"
                 ],
                 "summary": "Sincronizar eventos de registro de usuarios desde Kafka",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Solo comparar el tópico con la base de datos",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
-                    "200": {
-                        "description": "Resultado de la sincronización",
+                    "202": {
+                        "description": "Job creado",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya hay un job activo para el tópico",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Error interno del servidor",
                         "schema": {
@@ -576,6 +1635,52 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "commandservices.IngestResult": {
+            "type": "object",
+            "properties": {
+                "duplicate": {
+                    "description": "El evento ya estaba guardado (o una versión más reciente)",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID de la ejecución o del usuario",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "Posición del evento en el lote",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/commandservices.IngestStatus"
+                },
+                "updated": {
+                    "description": "El evento reemplazó una versión anterior de la ejecución",
+                    "type": "boolean"
+                }
+            }
+        },
+        "commandservices.IngestStatus": {
+            "type": "string",
+            "enum": [
+                "accepted",
+                "rejected"
+            ],
+            "x-enum-comments": {
+                "IngestStatusAccepted": "Guardado o ya existente",
+                "IngestStatusRejected": "Evento inválido, no se guardó"
+            },
+            "x-enum-descriptions": [
+                "Guardado o ya existente",
+                "Evento inválido, no se guardó"
+            ],
+            "x-enum-varnames": [
+                "IngestStatusAccepted",
+                "IngestStatusRejected"
+            ]
+        },
         "controllers.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -589,6 +1694,396 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "controllers.IngestResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "duplicates": {
+                    "description": "Aceptados que ya estaban guardados",
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/commandservices.IngestResult"
+                    }
+                },
+                "updated": {
+                    "description": "Aceptados que reemplazaron una versión anterior",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.PartitionsRequest": {
+            "type": "object",
+            "required": [
+                "topic"
+            ],
+            "properties": {
+                "partitions": {
+                    "description": "Vacío = todas las particiones",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "topic": {
+                    "type": "string",
+                    "example": "execution.analytics"
+                }
+            }
+        },
+        "controllers.ReplayRequest": {
+            "type": "object",
+            "required": [
+                "start",
+                "topic"
+            ],
+            "properties": {
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "end": {
+                    "type": "string",
+                    "example": "2024-05-20T18:00:00Z"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2024-05-20T12:00:00Z"
+                },
+                "topic": {
+                    "type": "string",
+                    "example": "execution.analytics"
+                }
+            }
+        },
+        "controllers.ResetOffsetsRequest": {
+            "type": "object",
+            "required": [
+                "strategy",
+                "topic"
+            ],
+            "properties": {
+                "offset": {
+                    "type": "integer"
+                },
+                "partitions": {
+                    "description": "Vacío = todas las particiones",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "strategy": {
+                    "type": "string",
+                    "enum": [
+                        "earliest",
+                        "latest",
+                        "timestamp",
+                        "offset"
+                    ],
+                    "example": "timestamp"
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2024-05-20T12:00:00Z"
+                },
+                "topic": {
+                    "type": "string",
+                    "example": "execution.analytics"
+                }
+            }
+        },
+        "queryservices.CodeVersionTimeline": {
+            "type": "object",
+            "properties": {
+                "challenge_id": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/queryservices.CodeVersionTimelineSummary"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/queryservices.CodeVersionTimelineEntry"
+                    }
+                }
+            }
+        },
+        "queryservices.CodeVersionTimelineEntry": {
+            "type": "object",
+            "properties": {
+                "code_version_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "execution_time_delta_ms": {
+                    "type": "integer"
+                },
+                "execution_time_ms": {
+                    "type": "integer"
+                },
+                "executions": {
+                    "type": "integer"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "last_executed_at": {
+                    "type": "string"
+                },
+                "lines_changed": {
+                    "type": "integer"
+                },
+                "parent_version_id": {
+                    "type": "string"
+                },
+                "passed_tests": {
+                    "description": "Campos de la última ejecución, nil sin ejecuciones",
+                    "type": "integer"
+                },
+                "passed_tests_delta": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total_tests": {
+                    "type": "integer"
+                },
+                "version_number": {
+                    "description": "0 si no se recibió el evento de la versión",
+                    "type": "integer"
+                }
+            }
+        },
+        "queryservices.CodeVersionTimelineSummary": {
+            "type": "object",
+            "properties": {
+                "best_passed_tests": {
+                    "type": "integer"
+                },
+                "executed_versions": {
+                    "type": "integer"
+                },
+                "first_passing_version": {
+                    "description": "Posición (desde 1) de la primera versión exitosa",
+                    "type": "integer"
+                },
+                "improvements": {
+                    "type": "integer"
+                },
+                "regressions": {
+                    "type": "integer"
+                },
+                "trend": {
+                    "type": "string"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "versions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "queryservices.ConsumerGroupMember": {
+            "type": "object",
+            "properties": {
+                "assignments": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer",
+                            "format": "int32"
+                        }
+                    }
+                },
+                "client_host": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "local": {
+                    "description": "Miembro de esta instancia",
+                    "type": "boolean"
+                },
+                "member_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "queryservices.ConsumerGroupStatus": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "string"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/queryservices.ConsumerGroupMember"
+                    }
+                },
+                "observed_at": {
+                    "type": "string"
+                },
+                "paused": {
+                    "description": "Particiones pausadas en esta instancia",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer",
+                            "format": "int32"
+                        }
+                    }
+                },
+                "pending_resets": {
+                    "description": "Particiones con un reseteo de offset por aplicar",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer",
+                            "format": "int32"
+                        }
+                    }
+                },
+                "protocol": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "queryservices.IngestionStatus": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "string"
+                },
+                "observed_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "topics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/queryservices.TopicIngestionStatus"
+                    }
+                },
+                "total_lag": {
+                    "type": "integer"
+                }
+            }
+        },
+        "queryservices.OutboxStatus": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "description": "Eventos apartados por agotar los intentos de publicación",
+                    "type": "integer"
+                },
+                "observed_at": {
+                    "type": "string"
+                },
+                "oldest_failed_at": {
+                    "type": "string"
+                },
+                "oldest_pending_at": {
+                    "type": "string"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "queryservices.PartitionIngestionStatus": {
+            "type": "object",
+            "properties": {
+                "committed_offset": {
+                    "description": "-1 si el grupo no ha confirmado offsets",
+                    "type": "integer"
+                },
+                "error_count": {
+                    "type": "integer"
+                },
+                "high_water_mark": {
+                    "type": "integer"
+                },
+                "lag": {
+                    "type": "integer"
+                },
+                "last_event_timestamp": {
+                    "type": "string"
+                },
+                "last_processed_at": {
+                    "type": "string"
+                },
+                "messages_per_second": {
+                    "type": "number"
+                },
+                "partition": {
+                    "type": "integer"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "processed_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "queryservices.TopicIngestionStatus": {
+            "type": "object",
+            "properties": {
+                "consumer": {
+                    "type": "string"
+                },
+                "error_count": {
+                    "type": "integer"
+                },
+                "lag": {
+                    "type": "integer"
+                },
+                "messages_per_second": {
+                    "type": "number"
+                },
+                "partitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/queryservices.PartitionIngestionStatus"
+                    }
+                },
+                "processed_count": {
+                    "type": "integer"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"net/http"
 	"os"
@@ -10,9 +11,15 @@ import (
 	"time"
 
 	"github.com/nanab/analytics-service/analytics/application/commandservices"
+	"github.com/nanab/analytics-service/analytics/application/events"
 	"github.com/nanab/analytics-service/analytics/application/queryservices"
+	"github.com/nanab/analytics-service/analytics/domain/model/aggregates"
+	"github.com/nanab/analytics-service/analytics/domain/model/valueobjects"
 	"github.com/nanab/analytics-service/analytics/infrastructure/config"
 	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/kafka"
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/schemaregistry"
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/serde"
+	"github.com/nanab/analytics-service/analytics/infrastructure/messaging/source"
 	"github.com/nanab/analytics-service/analytics/infrastructure/persistence/postgres/repositories"
 	"github.com/nanab/analytics-service/analytics/interfaces/rest/controllers"
 	_ "github.com/nanab/analytics-service/docs"
@@ -51,30 +58,299 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	// Eventos de dominio derivados de las ejecuciones: se escriben en el outbox junto con cada ejecución nueva
+	var derivedEvents *aggregates.DerivedEventPolicy
+	if cfg.Outbox.Enabled {
+		derivedEvents = &aggregates.DerivedEventPolicy{
+			Milestones:                cfg.Outbox.Milestones,
+			AtRiskConsecutiveFailures: cfg.Outbox.AtRiskConsecutiveFailures,
+		}
+	}
+
 	// Crear repositorios
-	executionRepository := repositories.NewPostgresExecutionAnalyticsRepository(db)
+	executionRepository := repositories.NewPostgresExecutionAnalyticsRepository(db, derivedEvents)
 	userRegistrationRepository := repositories.NewPostgresUserRegistrationAnalyticsRepository(db)
+	quarantinedEventRepository := repositories.NewPostgresQuarantinedEventRepository(db)
+	consumerOffsetRepository := repositories.NewPostgresConsumerOffsetRepository(db)
+	syncJobRepository := repositories.NewPostgresSyncJobRepository(db)
+	reconciliationRepository := repositories.NewPostgresReconciliationRepository(db)
+	outboxRepository := repositories.NewPostgresOutboxRepository(db)
+	challengeRepository := repositories.NewPostgresChallengeRepository(db)
+	codeVersionRepository := repositories.NewPostgresCodeVersionRepository(db)
+
+	// Configurar Schema Registry y decoders por tópico
+	var schemaRegistryClient *schemaregistry.Client
+	if cfg.SchemaRegistry.URL != "" {
+		schemaRegistryClient = schemaregistry.NewClient(cfg.SchemaRegistry.URL, &http.Client{
+			Timeout: time.Duration(cfg.SchemaRegistry.TimeoutMs) * time.Millisecond,
+		})
+		schemaRegistryClient.SetBasicAuth(cfg.SchemaRegistry.Username, cfg.SchemaRegistry.Password)
+	}
+
+	executionDecoder, err := serde.NewDecoder(cfg.GetTopicFormat(cfg.Kafka.Topic), schemaRegistryClient)
+	if err != nil {
+		log.Fatalf("Failed to create decoder for topic %s: %v", cfg.Kafka.Topic, err)
+	}
+	userRegistrationDecoder, err := serde.NewDecoder(cfg.GetTopicFormat(cfg.KafkaUserRegistration.Topic), schemaRegistryClient)
+	if err != nil {
+		log.Fatalf("Failed to create decoder for topic %s: %v", cfg.KafkaUserRegistration.Topic, err)
+	}
+	challengeDecoder, err := serde.NewDecoder(cfg.GetTopicFormat(cfg.KafkaChallenge.Topic), schemaRegistryClient)
+	if err != nil {
+		log.Fatalf("Failed to create decoder for topic %s: %v", cfg.KafkaChallenge.Topic, err)
+	}
+	codeVersionDecoder, err := serde.NewDecoder(cfg.GetTopicFormat(cfg.KafkaCodeVersion.Topic), schemaRegistryClient)
+	if err != nil {
+		log.Fatalf("Failed to create decoder for topic %s: %v", cfg.KafkaCodeVersion.Topic, err)
+	}
+
+	// Registro de lenguajes: predefinidos más los de LANGUAGE_REGISTRY_FILE
+	configuredLanguages := make([]valueobjects.LanguageDefinition, 0, len(cfg.Languages.Definitions))
+	for _, language := range cfg.Languages.Definitions {
+		configuredLanguages = append(configuredLanguages, valueobjects.LanguageDefinition{
+			Name:    language.Name,
+			Aliases: language.Aliases,
+			Family:  language.Family,
+			Version: language.Version,
+		})
+	}
+	languageRegistry, err := valueobjects.NewLanguageRegistry(
+		valueobjects.MergeLanguageDefinitions(valueobjects.DefaultLanguageDefinitions(), configuredLanguages),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create language registry: %v", err)
+	}
 
 	// Crear servicios de ejecución de código
 	executionCommandService := commandservices.NewExecutionAnalyticsCommandService(executionRepository)
-	executionQueryService := queryservices.NewExecutionAnalyticsQueryService(executionRepository)
+	executionQueryService := queryservices.NewExecutionAnalyticsQueryService(executionRepository, challengeRepository)
 	executionSyncService := commandservices.NewSyncService(
-		cfg.Kafka.BootstrapServers,
 		cfg.Kafka.Topic,
 		executionRepository,
+		executionDecoder,
+		languageRegistry,
 	)
 
 	// Crear servicios de registro de usuarios
+	userRegistrationTimestamps := events.TimestampPolicy{
+		Location:     cfg.KafkaUserRegistration.Timezone,
+		MaxClockSkew: time.Duration(cfg.KafkaUserRegistration.MaxClockSkewMs) * time.Millisecond,
+	}
 	userRegistrationCommandService := commandservices.NewUserRegistrationAnalyticsCommandService(userRegistrationRepository)
 	userRegistrationQueryService := queryservices.NewUserRegistrationAnalyticsQueryService(userRegistrationRepository)
 	userRegistrationSyncService := commandservices.NewUserRegistrationSyncService(
-		cfg.Kafka.BootstrapServers,
 		cfg.KafkaUserRegistration.Topic,
 		userRegistrationRepository,
+		userRegistrationDecoder,
+		userRegistrationTimestamps,
+	)
+
+	// Crear servicios del catálogo de challenges
+	challengeCommandService := commandservices.NewChallengeCommandService(challengeRepository)
+	challengeSyncService := commandservices.NewChallengeSyncService(
+		cfg.KafkaChallenge.Topic,
+		challengeRepository,
+		challengeDecoder,
+	)
+
+	// Crear servicios de versiones de código
+	codeVersionCommandService := commandservices.NewCodeVersionCommandService(codeVersionRepository)
+	codeVersionQueryService := queryservices.NewCodeVersionQueryService(codeVersionRepository)
+	codeVersionSyncService := commandservices.NewCodeVersionSyncService(
+		cfg.KafkaCodeVersion.Topic,
+		codeVersionRepository,
+		codeVersionDecoder,
+	)
+
+	// Servicio de ingesta por HTTP para los productores que no pueden publicar en Kafka
+	eventIngestService := commandservices.NewEventIngestService(
+		executionRepository,
+		userRegistrationRepository,
+		userRegistrationTimestamps,
+		languageRegistry,
 	)
 
+	// Configurar consumidor de Kafka con Azure Event Hub (un único consumer group para todos los tópicos)
+	consumerConfig := &kafka.ConsumerConfig{
+		Brokers:          cfg.Kafka.BootstrapServers,
+		GroupID:          cfg.Kafka.GroupID,
+		SecurityProtocol: cfg.Kafka.SecurityProtocol,
+		SaslMechanism:    cfg.Kafka.SaslMechanism,
+		SaslUsername:     cfg.Kafka.SaslUsername,
+		SaslPassword:     cfg.Kafka.SaslPassword,
+		RequestTimeoutMs: cfg.Kafka.RequestTimeoutMs,
+		SessionTimeoutMs: cfg.Kafka.SessionTimeoutMs,
+		EnableAutoCommit: cfg.Kafka.EnableAutoCommit,
+		InitialOffset:    cfg.Kafka.InitialOffset,
+		Workers:          cfg.Kafka.Workers,
+
+		TLSCAFile:             cfg.Kafka.TLSCAFile,
+		TLSCertFile:           cfg.Kafka.TLSCertFile,
+		TLSKeyFile:            cfg.Kafka.TLSKeyFile,
+		TLSServerName:         cfg.Kafka.TLSServerName,
+		TLSInsecureSkipVerify: cfg.Kafka.TLSInsecureSkipVerify,
+
+		OAuthToken:        cfg.Kafka.OAuthToken,
+		OAuthTokenURL:     cfg.Kafka.OAuthTokenURL,
+		OAuthClientID:     cfg.Kafka.OAuthClientID,
+		OAuthClientSecret: cfg.Kafka.OAuthClientSecret,
+		OAuthScopes:       cfg.Kafka.OAuthScopes,
+
+		RetryMaxAttempts:      cfg.Kafka.RetryMaxAttempts,
+		RetryInitialBackoffMs: cfg.Kafka.RetryInitialBackoffMs,
+		RetryMaxBackoffMs:     cfg.Kafka.RetryMaxBackoffMs,
+		RetryMaxElapsedMs:     cfg.Kafka.RetryMaxElapsedMs,
+
+		DeadLetterTransientErrors: cfg.Kafka.DeadLetterTransientErrors,
+	}
+
+	// Fuente de mensajes de los jobs de sincronización y, con MESSAGE_SOURCE=file, también de la ingesta
+	var messageSource events.MessageSource
+	if cfg.MessageSource.Type == "file" {
+		messageSource = source.NewFileSource(cfg.MessageSource.Files)
+	} else {
+		syncSaramaConfig, err := kafka.NewSaramaConfig(consumerConfig)
+		if err != nil {
+			log.Fatalf("Failed to create Kafka configuration: %v", err)
+		}
+		messageSource = kafka.NewKafkaSource(cfg.Kafka.BootstrapServers, syncSaramaConfig)
+	}
+
+	// Crear servicios de jobs de sincronización (retoma los jobs interrumpidos por un reinicio).
+	// Los tópicos opcionales solo se sincronizan si están configurados.
+	syncers := []commandservices.TopicSyncer{executionSyncService, userRegistrationSyncService}
+	if cfg.KafkaChallenge.Topic != "" {
+		syncers = append(syncers, challengeSyncService)
+	}
+	if cfg.KafkaCodeVersion.Topic != "" {
+		syncers = append(syncers, codeVersionSyncService)
+	}
+	syncJobService := commandservices.NewSyncJobService(
+		messageSource,
+		syncJobRepository,
+		reconciliationRepository,
+		syncers...,
+	)
+	syncJobQueryService := queryservices.NewSyncJobQueryService(syncJobRepository, reconciliationRepository)
+
+	// Comando replay: reprocesa una ventana de tiempo y termina sin iniciar el servidor
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplayCommand(os.Args[2:], syncJobService))
+	}
+
+	if err := syncJobService.ResumeInterruptedJobs(context.Background()); err != nil {
+		log.Printf("Warning: Failed to resume interrupted sync jobs: %v", err)
+	}
+
 	log.Println("Services initialized successfully")
 
+	var consumer *kafka.Consumer
+	var deadLetterPublisher *kafka.DeadLetterPublisher
+	if cfg.MessageSource.Type == "file" {
+		// Sin broker no hay dead-letter: los mensajes que fallan solo se registran en el log
+		log.Println("Creating consumer for message files...")
+		consumer = kafka.NewSourceConsumer(messageSource, consumerConfig, nil)
+	} else {
+		// Configurar dead-letter para mensajes que no pudieron procesarse
+		if cfg.Kafka.DeadLetterEnabled {
+			deadLetterPublisher, err = kafka.NewDeadLetterPublisher(consumerConfig, cfg.Kafka.DeadLetterTopic, quarantinedEventRepository)
+			if err != nil {
+				log.Printf("Warning: Failed to create dead-letter publisher, failed messages will only be logged: %v", err)
+				deadLetterPublisher = nil
+			}
+		}
+
+		log.Println("Creating Kafka consumer...")
+		consumer, err = kafka.NewConsumerWithConfig(consumerConfig, deadLetterPublisher)
+		if err != nil {
+			log.Fatalf("Failed to create Kafka consumer: %v", err)
+		}
+	}
+
+	// Guardar los offsets en la base de datos junto con los datos ingeridos
+	if cfg.Kafka.OffsetStorage == "postgres" {
+		log.Println("Storing Kafka offsets in PostgreSQL")
+		consumer.UseOffsetStore(consumerOffsetRepository)
+	}
+
+	// Registrar las rutas de cada tópico y sus tipos de evento
+	executionRoute := kafka.NewExecutionAnalyticsRoute(
+		cfg.Kafka.Topic,
+		executionCommandService,
+		executionDecoder,
+		languageRegistry,
+		cfg.Kafka.BatchSize,
+		time.Duration(cfg.Kafka.BatchTimeoutMs)*time.Millisecond,
+	)
+	executionRoute.EventTypes = cfg.Kafka.EventTypes
+	if err := consumer.Register(executionRoute); err != nil {
+		log.Fatalf("Failed to register execution analytics route: %v", err)
+	}
+
+	userRegistrationRoute := kafka.NewUserRegistrationRoute(
+		cfg.KafkaUserRegistration.Topic,
+		userRegistrationCommandService,
+		userRegistrationDecoder,
+		userRegistrationTimestamps,
+	)
+	userRegistrationRoute.EventTypes = cfg.KafkaUserRegistration.EventTypes
+	if err := consumer.Register(userRegistrationRoute); err != nil {
+		log.Fatalf("Failed to register user registration route: %v", err)
+	}
+
+	// Tópicos opcionales: solo se consumen si están configurados
+	var optionalTopics []string
+	if cfg.KafkaChallenge.Topic != "" {
+		challengeRoute := kafka.NewChallengeRoute(
+			cfg.KafkaChallenge.Topic,
+			challengeCommandService,
+			challengeDecoder,
+		)
+		challengeRoute.EventTypes = cfg.KafkaChallenge.EventTypes
+		if err := consumer.Register(challengeRoute); err != nil {
+			log.Fatalf("Failed to register challenge catalog route: %v", err)
+		}
+		optionalTopics = append(optionalTopics, cfg.KafkaChallenge.Topic)
+	}
+
+	if cfg.KafkaCodeVersion.Topic != "" {
+		codeVersionRoute := kafka.NewCodeVersionRoute(
+			cfg.KafkaCodeVersion.Topic,
+			codeVersionCommandService,
+			codeVersionDecoder,
+		)
+		codeVersionRoute.EventTypes = cfg.KafkaCodeVersion.EventTypes
+		if err := consumer.Register(codeVersionRoute); err != nil {
+			log.Fatalf("Failed to register code version route: %v", err)
+		}
+		optionalTopics = append(optionalTopics, cfg.KafkaCodeVersion.Topic)
+	}
+
+	// Monitor de lag y estado de ingesta
+	ingestionMonitor := kafka.NewIngestionMonitor(consumerConfig, consumer)
+	ingestionQueryService := queryservices.NewIngestionQueryService(ingestionMonitor, int64(cfg.Kafka.LagWarningThreshold))
+	consumerAdminService := commandservices.NewConsumerAdminService(ingestionMonitor)
+
+	// Estado del outbox: eventos pendientes y apartados como fallidos
+	outboxQueryService := queryservices.NewOutboxQueryService(outboxRepository)
+
+	// Relay del outbox: publica los eventos de dominio para los servicios de notificaciones y gamificación
+	var outboxRelay *kafka.OutboxRelay
+	if cfg.Outbox.Enabled {
+		outboxRelay, err = kafka.NewOutboxRelay(consumerConfig, kafka.OutboxRelayConfig{
+			Topic:        cfg.Outbox.Topic,
+			Source:       "/" + cfg.ServiceDiscovery.ServiceName,
+			BatchSize:    cfg.Outbox.BatchSize,
+			PollInterval: time.Duration(cfg.Outbox.PollIntervalMs) * time.Millisecond,
+			Retention:    time.Duration(cfg.Outbox.RetentionHours) * time.Hour,
+			MaxAttempts:  cfg.Outbox.MaxAttempts,
+		}, outboxRepository)
+		if err != nil {
+			log.Printf("Warning: Failed to create outbox relay, domain events will stay pending in the outbox: %v", err)
+			outboxRelay = nil
+		}
+	}
+
 	// Configurar Gin
 	router := gin.Default()
 
@@ -115,16 +391,31 @@ func main() {
 	analyticsController := controllers.NewAnalyticsController(executionQueryService)
 	analyticsController.RegisterRoutes(apiV1)
 
-	syncController := controllers.NewSyncController(executionSyncService)
+	codeVersionController := controllers.NewCodeVersionController(codeVersionQueryService)
+	codeVersionController.RegisterRoutes(apiV1)
+
+	syncController := controllers.NewSyncController(syncJobService, syncJobQueryService)
 	syncController.RegisterRoutes(apiV1)
 
 	// Controladores de registro de usuarios
 	userRegistrationController := controllers.NewUserRegistrationAnalyticsController(
 		userRegistrationQueryService,
-		userRegistrationSyncService,
+		syncJobService,
 	)
 	userRegistrationController.RegisterRoutes(apiV1)
 
+	// Controlador de administración de la ingesta
+	ingestionController := controllers.NewIngestionController(ingestionQueryService, consumerAdminService)
+	ingestionController.RegisterRoutes(apiV1)
+
+	// Controlador de administración del outbox
+	outboxController := controllers.NewOutboxController(outboxQueryService)
+	outboxController.RegisterRoutes(apiV1)
+
+	// Controlador de ingesta de eventos por HTTP
+	eventIngestController := controllers.NewEventIngestController(eventIngestService)
+	eventIngestController.RegisterRoutes(apiV1)
+
 	// Iniciar servidor HTTP en goroutine
 	srv := &http.Server{
 		Addr:    cfg.GetServerAddress(),
@@ -163,64 +454,46 @@ func main() {
 		}
 	}
 
-	// Configurar consumidor de Kafka para ejecuciones de código con Azure Event Hub
-	executionConsumerConfig := &kafka.ConsumerConfig{
-		Brokers:          cfg.Kafka.BootstrapServers,
-		GroupID:          cfg.Kafka.GroupID,
-		Topic:            cfg.Kafka.Topic,
-		SecurityProtocol: cfg.Kafka.SecurityProtocol,
-		SaslMechanism:    cfg.Kafka.SaslMechanism,
-		SaslUsername:     cfg.Kafka.SaslUsername,
-		SaslPassword:     cfg.Kafka.SaslPassword,
-		RequestTimeoutMs: cfg.Kafka.RequestTimeoutMs,
-		SessionTimeoutMs: cfg.Kafka.SessionTimeoutMs,
-		EnableAutoCommit: cfg.Kafka.EnableAutoCommit,
-	}
-
-	log.Println("Creating execution analytics Kafka consumer...")
-	executionConsumer, err := kafka.NewConsumerWithConfig(executionConsumerConfig, executionCommandService)
-	if err != nil {
-		log.Fatalf("Failed to create execution Kafka consumer: %v", err)
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// Configurar consumidor de Kafka para registros de usuarios con Azure Event Hub
-	userRegistrationConsumerConfig := &kafka.ConsumerConfig{
-		Brokers:          cfg.Kafka.BootstrapServers,
-		GroupID:          cfg.KafkaUserRegistration.GroupID,
-		Topic:            cfg.KafkaUserRegistration.Topic,
-		SecurityProtocol: cfg.Kafka.SecurityProtocol,
-		SaslMechanism:    cfg.Kafka.SaslMechanism,
-		SaslUsername:     cfg.Kafka.SaslUsername,
-		SaslPassword:     cfg.Kafka.SaslPassword,
-		RequestTimeoutMs: cfg.Kafka.RequestTimeoutMs,
-		SessionTimeoutMs: cfg.Kafka.SessionTimeoutMs,
-		EnableAutoCommit: cfg.Kafka.EnableAutoCommit,
+	// Un tópico opcional que no existe en el broker se omite en lugar de detener el consumo de los demás
+	if cfg.MessageSource.Type != "file" && len(optionalTopics) > 0 {
+		skipped, err := ingestionMonitor.SkipMissingTopics(optionalTopics...)
+		if err != nil {
+			log.Printf("Warning: Failed to check optional topics %v: %v", optionalTopics, err)
+		}
+		for _, topic := range skipped {
+			log.Printf("Warning: Topic %s does not exist, its route will not be consumed", topic)
+		}
 	}
 
-	log.Println("Creating user registration Kafka consumer...")
-	userRegistrationConsumer, err := kafka.NewUserRegistrationConsumerWithConfig(userRegistrationConsumerConfig, userRegistrationCommandService)
-	if err != nil {
-		log.Fatalf("Failed to create user registration Kafka consumer: %v", err)
+	// Migración al consumer group compartido: el tópico de registros parte donde lo dejó su grupo anterior
+	if cfg.MessageSource.Type != "file" && cfg.KafkaUserRegistration.LegacyGroupID != "" {
+		seedCtx, cancelSeed := context.WithTimeout(ctx, 30*time.Second)
+		seeded, err := ingestionMonitor.SeedOffsetsFromGroup(seedCtx, cfg.KafkaUserRegistration.Topic, cfg.KafkaUserRegistration.LegacyGroupID)
+		cancelSeed()
+		if err != nil {
+			log.Printf("Warning: Failed to seed offsets of topic %s from group %s: %v",
+				cfg.KafkaUserRegistration.Topic, cfg.KafkaUserRegistration.LegacyGroupID, err)
+		} else if len(seeded) > 0 {
+			log.Printf("Seeded %d partition(s) of topic %s from group %s",
+				len(seeded), cfg.KafkaUserRegistration.Topic, cfg.KafkaUserRegistration.LegacyGroupID)
+		}
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Iniciar consumidor de ejecuciones
+	// Iniciar consumidor
 	go func() {
-		log.Printf("Starting Kafka consumer for topic: %s", cfg.Kafka.Topic)
-		if err := executionConsumer.Start(ctx); err != nil {
-			log.Printf("Execution Kafka consumer error: %v", err)
+		log.Printf("Starting Kafka consumer for topics: %v", consumer.Topics())
+		if err := consumer.Start(ctx); err != nil {
+			log.Printf("Kafka consumer error: %v", err)
 		}
 	}()
 
-	// Iniciar consumidor de registros de usuarios
-	go func() {
-		log.Printf("Starting Kafka consumer for user registrations on topic: %s", cfg.KafkaUserRegistration.Topic)
-		if err := userRegistrationConsumer.Start(ctx); err != nil {
-			log.Printf("User registration Kafka consumer error: %v", err)
-		}
-	}()
+	// Iniciar relay del outbox
+	if outboxRelay != nil {
+		go outboxRelay.Run(ctx)
+	}
 
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
@@ -236,13 +509,24 @@ func main() {
 		}
 	}
 
-	// Detener consumidores de Kafka
+	// Detener consumidor de Kafka
 	cancel()
-	if err := executionConsumer.Close(); err != nil {
-		log.Printf("Error closing execution Kafka consumer: %v", err)
+	if err := consumer.Close(); err != nil {
+		log.Printf("Error closing Kafka consumer: %v", err)
 	}
-	if err := userRegistrationConsumer.Close(); err != nil {
-		log.Printf("Error closing user registration Kafka consumer: %v", err)
+	syncJobService.Close()
+	if err := ingestionMonitor.Close(); err != nil {
+		log.Printf("Error closing ingestion monitor: %v", err)
+	}
+	if deadLetterPublisher != nil {
+		if err := deadLetterPublisher.Close(); err != nil {
+			log.Printf("Error closing dead-letter producer: %v", err)
+		}
+	}
+	if outboxRelay != nil {
+		if err := outboxRelay.Close(); err != nil {
+			log.Printf("Error closing outbox producer: %v", err)
+		}
 	}
 
 	// Detener servidor HTTP
@@ -255,3 +539,71 @@ func main() {
 
 	log.Println("Server exited")
 }
+
+// runReplayCommand reprocesa los mensajes de un tópico publicados en una ventana de tiempo y espera a que termine.
+// Uso: analytics-service replay -topic execution.analytics -from 2024-05-20T12:00:00Z [-to 2024-05-20T18:00:00Z] [-dry-run]
+// Retorna el código de salida del proceso.
+func runReplayCommand(args []string, syncJobService *commandservices.SyncJobService) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	topic := flags.String("topic", "", "Tópico a reprocesar")
+	fromStr := flags.String("from", "", "Inicio de la ventana (RFC3339)")
+	toStr := flags.String("to", "", "Fin de la ventana (RFC3339, opcional: por defecto hasta el último mensaje)")
+	dryRun := flags.Bool("dry-run", false, "Solo reportar los eventos que faltan en la base de datos o son inválidos")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *topic == "" || *fromStr == "" {
+		log.Println("Usage: replay -topic <topic> -from <RFC3339> [-to <RFC3339>] [-dry-run]")
+		return 2
+	}
+
+	from, err := time.Parse(time.RFC3339, *fromStr)
+	if err != nil {
+		log.Printf("Invalid -from time, use RFC3339: %v", err)
+		return 2
+	}
+
+	var to *time.Time
+	if *toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, *toStr)
+		if err != nil {
+			log.Printf("Invalid -to time, use RFC3339: %v", err)
+			return 2
+		}
+		to = &parsed
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	job, err := syncJobService.StartReplay(ctx, *topic, from, to, *dryRun)
+	if err != nil {
+		log.Printf("Failed to start replay: %v", err)
+		return 1
+	}
+
+	log.Printf("Replay job %s started for topic %s", job.ID(), *topic)
+	job, err = syncJobService.WaitJob(ctx, job.ID())
+	if err != nil {
+		// Interrumpido: el job queda activo y el servicio lo retoma al iniciar
+		syncJobService.Close()
+		log.Printf("Replay interrupted, it will resume when the service starts: %v", err)
+		return 1
+	}
+
+	if job.DryRun() {
+		log.Printf("Replay job %s (dry-run) %s: %d present, %d missing, %d invalid; report at /api/v1/sync/jobs/%s/report",
+			job.ID(), job.Status(), job.Skipped(), job.Missing(), job.Invalid(), job.ID())
+	} else {
+		log.Printf("Replay job %s %s: %d synced, %d skipped, %d invalid",
+			job.ID(), job.Status(), job.Synced(), job.Skipped(), job.Invalid())
+	}
+	if job.Status() != valueobjects.SyncJobCompleted {
+		if job.ErrorMessage() != "" {
+			log.Printf("Replay error: %s", job.ErrorMessage())
+		}
+		return 1
+	}
+	return 0
+}
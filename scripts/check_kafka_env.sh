@@ -60,7 +60,6 @@ echo ""
 check_var "KAFKA_TOPIC"
 check_var "KAFKA_GROUP_ID"
 check_var "KAFKA_USER_REGISTRATION_TOPIC"
-check_var "KAFKA_USER_REGISTRATION_GROUP_ID"
 
 echo ""
 echo "=================================================="
@@ -116,9 +116,8 @@ check_var "KAFKA_TOPIC" || ((errors++))
 check_var "KAFKA_USER_REGISTRATION_TOPIC" || ((errors++))
 
 echo ""
-echo "=== Consumer Groups ==="
+echo "=== Consumer Group ==="
 check_var "KAFKA_GROUP_ID" || ((errors++))
-check_var "KAFKA_USER_REGISTRATION_GROUP_ID" || ((errors++))
 
 echo ""
 echo "=== Timeout Configuration ==="